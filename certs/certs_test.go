@@ -0,0 +1,143 @@
+package certs
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile writes data to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// handshake runs a TLS handshake between server and client over a loopback
+// connection and returns the server's and the client's error.
+func handshake(t *testing.T, server, client *tls.Config) (serverErr, clientErr error) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	done := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- tls.Server(conn, server).Handshake()
+	}()
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if clientErr = tls.Client(conn, client).Handshake(); clientErr != nil {
+		conn.Close()
+	}
+	return <-done, clientErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := NewCA("test CA")
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	caFile := writeFile(t, dir, "ca.pem", ca.CertPEM())
+	certPEM, keyPEM, err := ca.IssueServer("localhost", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("IssueServer: %v", err)
+	}
+	serverCert, serverKey := writeFile(t, dir, "server.pem", certPEM), writeFile(t, dir, "server-key.pem", keyPEM)
+	certPEM, keyPEM, err = ca.IssueClient("alice")
+	if err != nil {
+		t.Fatalf("IssueClient: %v", err)
+	}
+	clientCert, clientKey := writeFile(t, dir, "alice.pem", certPEM), writeFile(t, dir, "alice-key.pem", keyPEM)
+
+	server, err := ServerConfig(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatalf("ServerConfig: %v", err)
+	}
+	client, err := ClientConfig(caFile, clientCert, clientKey)
+	if err != nil {
+		t.Fatalf("ClientConfig: %v", err)
+	}
+	for _, name := range []string{"localhost", "127.0.0.1"} {
+		client.ServerName = name
+		if serverErr, clientErr := handshake(t, server, client); serverErr != nil || clientErr != nil {
+			t.Fatalf("handshake with %s: server %v, client %v", name, serverErr, clientErr)
+		}
+	}
+
+	client.ServerName = "example.com"
+	if _, err := handshake(t, server, client); err == nil {
+		t.Error("expected the client to reject a certificate for another host")
+	}
+	anonymous, err := ClientConfig(caFile, "", "")
+	if err != nil {
+		t.Fatalf("ClientConfig: %v", err)
+	}
+	anonymous.ServerName = "localhost"
+	if serverErr, _ := handshake(t, server, anonymous); serverErr == nil {
+		t.Errorf("expected the server to reject a client without a certificate, got %v", serverErr)
+	}
+
+	// A client certificate from another CA is refused too.
+	other, err := NewCA("other CA")
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	certPEM, keyPEM, err = other.IssueClient("alice")
+	if err != nil {
+		t.Fatalf("IssueClient: %v", err)
+	}
+	impostor, err := ClientConfig(caFile, writeFile(t, dir, "impostor.pem", certPEM), writeFile(t, dir, "impostor-key.pem", keyPEM))
+	if err != nil {
+		t.Fatalf("ClientConfig: %v", err)
+	}
+	impostor.ServerName = "localhost"
+	if serverErr, _ := handshake(t, server, impostor); serverErr == nil {
+		t.Errorf("expected the server to reject a certificate from another CA, got %v", serverErr)
+	}
+}
+
+func TestParseCA(t *testing.T) {
+	ca, err := NewCA("test CA")
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		t.Fatalf("KeyPEM: %v", err)
+	}
+	loaded, err := ParseCA(ca.CertPEM(), keyPEM)
+	if err != nil {
+		t.Fatalf("ParseCA: %v", err)
+	}
+	if !loaded.cert.Equal(ca.cert) || !loaded.key.Equal(ca.key) {
+		t.Error("expected the CA to round-trip")
+	}
+
+	certPEM, leafKeyPEM, err := ca.IssueClient("alice")
+	if err != nil {
+		t.Fatalf("IssueClient: %v", err)
+	}
+	if _, err := ParseCA(certPEM, leafKeyPEM); err == nil {
+		t.Error("expected an error parsing a client certificate as a CA")
+	}
+	if _, _, err := ca.IssueServer(); err == nil {
+		t.Error("expected an error issuing a server certificate for no hosts")
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -17,6 +20,23 @@ import (
 var (
 	errExit       = errors.New("exit requested")
 	strikethrough = color.New(color.CrossedOut)
+	overdue       = color.New(color.FgRed)
+
+	priorityColors = map[todo.Priority]*color.Color{
+		todo.PriorityLow:    color.New(color.FgBlue),
+		todo.PriorityMedium: color.New(color.FgCyan),
+		todo.PriorityHigh:   color.New(color.FgYellow),
+		todo.PriorityUrgent: color.New(color.FgRed, color.Bold),
+	}
+)
+
+const (
+	// dueSoonDays is the window, in calendar days including today, covered
+	// by the "due this week" view.
+	dueSoonDays = 7
+	// listPageSize is the number of todos shown before asking whether to
+	// fetch the next page.
+	listPageSize = 20
 )
 
 type menuItem struct {
@@ -31,15 +51,27 @@ type App struct {
 	menu    []menuItem
 	lines   chan string
 	scanErr chan error
+	now     func() time.Time
+	// project is the active project, whose todos the menu lists and adds
+	// to.
+	project todo.Project
+	// history and undone are the undo and redo stacks of this session, and
+	// versions holds the version it last left each changed todo at.
+	history  []change
+	undone   []change
+	versions map[int]int
 }
 
 func New(store todo.Storage, scanner *bufio.Scanner, out io.Writer) *App {
 	app := &App{
-		store:   store,
-		scanner: scanner,
-		out:     out,
-		lines:   make(chan string),
-		scanErr: make(chan error, 1),
+		store:    store,
+		scanner:  scanner,
+		out:      out,
+		lines:    make(chan string),
+		scanErr:  make(chan error, 1),
+		now:      time.Now,
+		project:  todo.Inbox(),
+		versions: make(map[int]int),
 	}
 	app.menu = []menuItem{
 		{"Add a todo", app.handleAdd},
@@ -48,7 +80,26 @@ func New(store todo.Storage, scanner *bufio.Scanner, out io.Writer) *App {
 		{"Mark as completed", func(ctx context.Context) error { return app.handleSetCompleted(ctx, true) }},
 		{"Mark as incomplete", func(ctx context.Context) error { return app.handleSetCompleted(ctx, false) }},
 		{"Edit a todo", app.handleEdit},
-		{"Exit", func(context.Context) error { return errExit }},
+		{"List overdue todos", func(ctx context.Context) error {
+			return app.handleDue(ctx, "overdue", todo.Todo.IsOverdue)
+		}},
+		{"List todos due today", func(ctx context.Context) error {
+			return app.handleDue(ctx, "due today", func(t todo.Todo, now time.Time) bool { return t.IsDueWithin(now, 1) })
+		}},
+		{"List todos due this week", func(ctx context.Context) error {
+			return app.handleDue(ctx, "due this week", func(t todo.Todo, now time.Time) bool { return t.IsDueWithin(now, dueSoonDays) })
+		}},
+		{"List todos by priority", app.handleListByPriority},
+		{"List todos by tag", app.handleListByTag},
+		{"Search", app.handleSearch},
+		{"Live list", app.handleLiveList},
+		{"Trash", app.handleTrash},
+		{"Undo last action (u)", app.handleUndo},
+		{"Redo (r)", app.handleRedo},
+		{"List todos by date", app.handleListByTime},
+		{"Add a subtask", app.handleAddSubtask},
+		{"What can I work on now?", app.handleReady},
+		{"Projects", app.handleProjects},
 	}
 	return app
 }
@@ -76,9 +127,18 @@ func (a *App) Run(ctx context.Context) error {
 			}
 			return err
 		}
+		if shortcut, ok := a.shortcuts()[strings.ToLower(choice)]; ok {
+			if err := shortcut(ctx); err != nil {
+				if errors.Is(err, errExit) {
+					return nil
+				}
+				return err
+			}
+			continue
+		}
 		option, parseErr := strconv.Atoi(choice)
 		if parseErr != nil || option < 0 || option > len(a.menu) {
-			fmt.Fprintf(a.out, "Error: please enter a number between 0 and %d.\n", len(a.menu))
+			fmt.Fprintf(a.out, "Error: please enter a number between 0 and %d, or q to exit.\n", len(a.menu))
 			continue
 		}
 		if option == 0 {
@@ -94,30 +154,238 @@ func (a *App) Run(ctx context.Context) error {
 	}
 }
 
+// shortcuts are the menu choices that can also be made by letter. Exit is
+// made only by letter, so it keeps its key as the menu grows.
+func (a *App) shortcuts() map[string]func(ctx context.Context) error {
+	return map[string]func(ctx context.Context) error{
+		"u": a.handleUndo,
+		"r": a.handleRedo,
+		"q": func(context.Context) error { return errExit },
+	}
+}
+
 func (a *App) printMenu() {
 	fmt.Fprintln(a.out, "===== Todo CLI =====")
 	for i, item := range a.menu {
 		fmt.Fprintf(a.out, "%d. %s\n", i+1, item.label)
 	}
+	fmt.Fprintln(a.out, "q. Exit")
+	fmt.Fprintf(a.out, "Project: %s\n", a.project.Name)
 	fmt.Fprintln(a.out, "====================")
 }
 
+// printTodos prints todos as a tree: each subtask is indented under its
+// parent, and each parent shows how many of its subtasks are done. Siblings
+// keep their order in todos, and a subtask whose parent is not in todos is
+// printed at the top level.
 func (a *App) printTodos(todos []todo.Todo) {
 	if len(todos) == 0 {
 		fmt.Fprintln(a.out, "No todos found.")
 		return
 	}
+	listed := make(map[int]todo.Todo, len(todos))
 	for _, t := range todos {
-		label := t.Title
-		if t.Description != "" {
-			label += " - " + t.Description
+		listed[t.ID] = t
+	}
+	var roots []todo.Todo
+	subtasks := make(map[int][]todo.Todo)
+	for _, t := range todos {
+		if _, ok := listed[t.ParentID]; ok {
+			subtasks[t.ParentID] = append(subtasks[t.ParentID], t)
+		} else {
+			roots = append(roots, t)
 		}
+	}
+	now := a.now()
+	var printLevel func(level []todo.Todo, indent string)
+	printLevel = func(level []todo.Todo, indent string) {
+		for _, t := range level {
+			a.printTodo(t, indent, subtasks[t.ID], listed, now)
+			printLevel(subtasks[t.ID], indent+"    ")
+		}
+	}
+	printLevel(roots, "")
+}
+
+// printTodo prints one line of printTodos for t, whose listed subtasks are
+// given. listed holds every todo printed, by ID.
+func (a *App) printTodo(t todo.Todo, indent string, subtasks []todo.Todo, listed map[int]todo.Todo, now time.Time) {
+	label := t.Title
+	if t.Description != "" {
+		label += " - " + t.Description
+	}
+	suffix := progressSuffix(subtasks) + blockedSuffix(t, listed) + tagSuffix(t) + dueSuffix(t, now) + repeatSuffix(t) + ageSuffix(t, now) + trashSuffix(t, now)
+	if t.Completed {
+		fmt.Fprintf(a.out, "%s[✓] %d. %s%s%s\n", indent, t.ID, priorityPrefix(t), strikethrough.Sprint(label), suffix)
+	} else {
+		fmt.Fprintf(a.out, "%s[ ] %d. %s%s%s\n", indent, t.ID, priorityPrefix(t), label, suffix)
+	}
+}
+
+// progressSuffix says how many of a parent's subtasks are done.
+func progressSuffix(subtasks []todo.Todo) string {
+	if len(subtasks) == 0 {
+		return ""
+	}
+	done := 0
+	for _, t := range subtasks {
 		if t.Completed {
-			fmt.Fprintf(a.out, "[✓] %d. %s\n", t.ID, strikethrough.Sprint(label))
-		} else {
-			fmt.Fprintf(a.out, "[ ] %d. %s\n", t.ID, label)
+			done++
+		}
+	}
+	return fmt.Sprintf(" (%d/%d done)", done, len(subtasks))
+}
+
+func priorityPrefix(t todo.Todo) string {
+	if t.Priority == todo.PriorityNone {
+		return ""
+	}
+	marker := "[" + t.Priority.String() + "] "
+	if c, ok := priorityColors[t.Priority]; ok {
+		return c.Sprint(marker)
+	}
+	return marker
+}
+
+// sortByPriority orders todos from most to least pressing, keeping the
+// existing (ID) order among todos of equal priority.
+func sortByPriority(todos []todo.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].Priority > todos[j].Priority
+	})
+}
+
+// timeFields maps the names of the todo timestamps a list can be sorted by
+// to their values.
+var timeFields = map[string]func(todo.Todo) time.Time{
+	"created":   func(t todo.Todo) time.Time { return t.CreatedAt },
+	"updated":   func(t todo.Todo) time.Time { return t.UpdatedAt },
+	"completed": func(t todo.Todo) time.Time { return t.CompletedAt },
+}
+
+// sortByTime orders todos from the latest to the earliest value of at.
+// Todos without one, such as incomplete todos by completion time, go last
+// in their existing (ID) order.
+func sortByTime(todos []todo.Todo, at func(todo.Todo) time.Time) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return at(todos[i]).After(at(todos[j]))
+	})
+}
+
+// blockedSuffix names the todos an incomplete todo still waits on. Blockers
+// shown as completed in listed are left out; those not listed are named, as
+// their state is unknown here.
+func blockedSuffix(t todo.Todo, listed map[int]todo.Todo) string {
+	if t.Completed {
+		return ""
+	}
+	var open []string
+	for _, id := range t.BlockedBy {
+		if blocker, ok := listed[id]; !ok || !blocker.Completed {
+			open = append(open, fmt.Sprintf("#%d", id))
 		}
 	}
+	if len(open) == 0 {
+		return ""
+	}
+	return " (blocked by " + strings.Join(open, ", ") + ")"
+}
+
+// readyTodos returns the todos that can be worked on now: those incomplete
+// todos whose blockers are all completed. all holds every live todo; a
+// blocker missing from it is in the trash or purged, and blocks nothing.
+func readyTodos(todos, all []todo.Todo) []todo.Todo {
+	completed := make(map[int]bool, len(all))
+	for _, t := range all {
+		completed[t.ID] = t.Completed
+	}
+	var ready []todo.Todo
+	for _, t := range todos {
+		if t.Completed {
+			continue
+		}
+		blocked := slices.ContainsFunc(t.BlockedBy, func(id int) bool {
+			done, live := completed[id]
+			return live && !done
+		})
+		if !blocked {
+			ready = append(ready, t)
+		}
+	}
+	return ready
+}
+
+func tagSuffix(t todo.Todo) string {
+	var b strings.Builder
+	for _, tag := range t.Tags {
+		b.WriteString(" #" + tag)
+	}
+	return b.String()
+}
+
+func dueSuffix(t todo.Todo, now time.Time) string {
+	if t.DueDate == "" {
+		return ""
+	}
+	due := t.DueDate
+	if t.DueTime != "" {
+		due += " " + t.DueTime
+	}
+	if t.IsOverdue(now) {
+		return overdue.Sprintf(" (overdue, due %s)", due)
+	}
+	return fmt.Sprintf(" (due %s)", due)
+}
+
+// repeatSuffix says how an incomplete todo repeats. Once it is completed,
+// its next occurrence carries the schedule on.
+func repeatSuffix(t todo.Todo) string {
+	if t.Recurrence == "" || t.Completed {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", describeRule(t.Recurrence))
+}
+
+// ageSuffix says how long ago the latest of a live todo's completion, last
+// change and creation happened. Todos stored before timestamps were
+// recorded have no age.
+func ageSuffix(t todo.Todo, now time.Time) string {
+	switch {
+	case t.Trashed() || t.CreatedAt.IsZero():
+		return ""
+	case !t.CompletedAt.IsZero():
+		return " (done " + relativeAge(t.CompletedAt, now) + ")"
+	case t.UpdatedAt.After(t.CreatedAt):
+		return " (updated " + relativeAge(t.UpdatedAt, now) + ")"
+	default:
+		return " (added " + relativeAge(t.CreatedAt, now) + ")"
+	}
+}
+
+// relativeAge describes how long before now at was, e.g. "3d ago", in the
+// largest whole unit. Times in the future, as a skewed clock can report,
+// are "just now".
+func relativeAge(at, now time.Time) string {
+	d := now.Sub(at)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d/(365*24*time.Hour)))
+	}
+}
+
+func trashSuffix(t todo.Todo, now time.Time) string {
+	if !t.Trashed() {
+		return ""
+	}
+	return fmt.Sprintf(" (deleted %s)", t.DeletedAt.In(now.Location()).Format("2006-01-02 15:04"))
 }
 
 func (a *App) readLine(ctx context.Context, prompt string) (string, error) {
@@ -126,62 +394,172 @@ func (a *App) readLine(ctx context.Context, prompt string) (string, error) {
 	case <-ctx.Done():
 		return "", errExit
 	case line, ok := <-a.lines:
-		if !ok {
-			select {
-			case err := <-a.scanErr:
-				if err != nil {
-					return "", errors.New("input error")
-				}
-			default:
+		return a.received(line, ok)
+	}
+}
+
+// received turns a receive from a.lines into readLine's result, reporting
+// errExit once input is exhausted.
+func (a *App) received(line string, ok bool) (string, error) {
+	if !ok {
+		select {
+		case err := <-a.scanErr:
+			if err != nil {
+				return "", errors.New("input error")
 			}
-			return "", errExit
+		default:
 		}
-		return strings.TrimSpace(line), nil
+		return "", errExit
 	}
+	return strings.TrimSpace(line), nil
 }
 
-func (a *App) readID(ctx context.Context, prompt string) (int, error) {
-	input, err := a.readLine(ctx, prompt)
-	if err != nil {
-		return 0, err
-	}
-	id, parseErr := strconv.Atoi(input)
-	if parseErr != nil {
-		return 0, fmt.Errorf("invalid ID %q", input)
-	}
-	return id, nil
+// pickTodo shows the todos of the active project a page at a time and asks
+// for the ID of one of them, which it returns as shown. Entering "n" at the
+// prompt shows the next page.
+func (a *App) pickTodo(ctx context.Context, prompt string) (todo.Todo, error) {
+	return a.pickFrom(ctx, todo.ListOptions{ProjectID: a.project.ID}, prompt)
 }
 
-func (a *App) listAndPromptID(ctx context.Context, prompt string) (int, error) {
-	todos, err := a.store.List(ctx)
-	if err != nil {
-		return 0, err
-	}
-	a.printTodos(todos)
-	if len(todos) == 0 {
-		return 0, fmt.Errorf("no todos to select from")
+// pickFrom is like pickTodo but offers the todos matching opts.
+func (a *App) pickFrom(ctx context.Context, opts todo.ListOptions, prompt string) (todo.Todo, error) {
+	opts.PageSize = listPageSize
+	shown := make(map[int]todo.Todo)
+	for {
+		todos, next, err := a.store.List(ctx, opts)
+		if err != nil {
+			return todo.Todo{}, err
+		}
+		a.printTodos(todos)
+		for _, t := range todos {
+			shown[t.ID] = t
+		}
+		if len(shown) == 0 {
+			return todo.Todo{}, fmt.Errorf("no todos to select from")
+		}
+		p := prompt
+		if next != "" {
+			p = strings.TrimSuffix(prompt, ": ") + " (n for next page): "
+		}
+		input, err := a.readLine(ctx, p)
+		if err != nil {
+			return todo.Todo{}, err
+		}
+		if next != "" && strings.EqualFold(input, "n") {
+			opts.PageToken = next
+			continue
+		}
+		id, parseErr := strconv.Atoi(input)
+		if parseErr != nil {
+			return todo.Todo{}, fmt.Errorf("invalid ID %q", input)
+		}
+		t, ok := shown[id]
+		if !ok {
+			return todo.Todo{}, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+		}
+		return t, nil
 	}
-	id, err := a.readID(ctx, prompt)
-	if err != nil {
-		return 0, err
+}
+
+// expecting makes a change to t conditional on t still being at the
+// version the user was shown, so it cannot overwrite someone else's change.
+func expecting(ctx context.Context, t todo.Todo) context.Context {
+	return todo.WithExpectedVersion(ctx, t.Version)
+}
+
+// printPages prints the todos matching opts a page at a time, asking
+// before fetching each following page.
+func (a *App) printPages(ctx context.Context, opts todo.ListOptions) error {
+	opts.PageSize = listPageSize
+	for {
+		todos, next, err := a.store.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		a.printTodos(todos)
+		if next == "" {
+			return nil
+		}
+		input, err := a.readLine(ctx, "> Enter n for the next page, or press Enter to stop: ")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(input, "n") {
+			return nil
+		}
+		opts.PageToken = next
 	}
-	for _, t := range todos {
-		if t.ID == id {
-			return id, nil
+}
+
+// listAll fetches every todo matching opts, for views that sort or filter
+// the whole result on the client.
+func (a *App) listAll(ctx context.Context, opts todo.ListOptions) ([]todo.Todo, error) {
+	opts.PageSize = todo.MaxPageSize
+	var all []todo.Todo
+	for {
+		todos, next, err := a.store.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, todos...)
+		if next == "" {
+			return all, nil
 		}
+		opts.PageToken = next
 	}
-	return 0, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
 }
 
 func (a *App) handleErr(err error) error {
 	if errors.Is(err, errExit) {
 		return err
 	}
-	fmt.Fprintf(a.out, "Error: %v\n", err)
+	fmt.Fprintf(a.out, "Error: %s\n", errorText(err))
+	return nil
+}
+
+// handleChangeErr reports err from a change to the todo with the given ID.
+// If someone else changed the todo after it was shown, it offers to reload
+// the todo so the user can see what changed before trying again.
+func (a *App) handleChangeErr(ctx context.Context, id int, err error) error {
+	if !errors.Is(err, todo.ErrVersionConflict) {
+		return a.handleErr(err)
+	}
+	fmt.Fprintf(a.out, "Todo %d was changed by someone else, so your change was not applied.\n", id)
+	answer, err := a.readLine(ctx, "> Reload it? (y/n): ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return nil
+	}
+	todos, _, err := a.store.List(ctx, todo.ListOptions{ProjectID: a.project.ID, Filter: fmt.Sprintf("id=%d", id)})
+	if err != nil {
+		return a.handleErr(err)
+	}
+	if len(todos) == 0 {
+		fmt.Fprintf(a.out, "Todo %d has been deleted or moved to another project.\n", id)
+		return nil
+	}
+	a.printTodos(todos)
 	return nil
 }
 
 func (a *App) handleAdd(ctx context.Context) error {
+	return a.addTodo(ctx, 0)
+}
+
+// handleAddSubtask adds a todo as a subtask of one the user picks.
+func (a *App) handleAddSubtask(ctx context.Context) error {
+	parent, err := a.pickTodo(ctx, "> Enter the ID of the todo to add a subtask to: ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	return a.addTodo(ctx, parent.ID)
+}
+
+// addTodo prompts for a new todo and adds it as a subtask of parentID, or at
+// the top level if parentID is 0.
+func (a *App) addTodo(ctx context.Context, parentID int) error {
 	title, err := a.readLine(ctx, "> Enter title: ")
 	if err != nil {
 		return a.handleErr(err)
@@ -193,31 +571,319 @@ func (a *App) handleAdd(ctx context.Context) error {
 	if err != nil {
 		return a.handleErr(err)
 	}
-	if err := a.store.Add(ctx, title, desc); err != nil {
+	priority, err := a.readPriority(ctx, "> Enter priority (none/low/medium/high/urgent, optional): ")
+	if err != nil {
 		return a.handleErr(err)
 	}
-	fmt.Fprintln(a.out, "Todo added successfully.")
+	dueDate, dueTime, err := a.readDue(ctx, "> Enter due date (YYYY-MM-DD, optional): ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	// Only a todo with a due date is asked how it repeats, as its next
+	// occurrences are due by the same schedule.
+	var rule string
+	if dueDate != "" {
+		if rule, err = a.readRepeat(ctx, "optional"); err != nil {
+			return a.handleErr(err)
+		}
+	}
+	draft := todo.Draft{Title: title, Description: desc, DueDate: dueDate, DueTime: dueTime, Priority: priority, ParentID: parentID, ProjectID: a.project.ID, Recurrence: rule}
+	added, err := a.store.Add(ctx, draft)
+	if err != nil {
+		return a.handleErr(err)
+	}
+	a.recordAdd(added)
+	fmt.Fprintf(a.out, "Added #%d\n", added.ID)
+	a.printTodos([]todo.Todo{added})
 	return nil
 }
 
 func (a *App) handleList(ctx context.Context) error {
-	todos, err := a.store.List(ctx)
+	if err := a.printPages(ctx, todo.ListOptions{ProjectID: a.project.ID}); err != nil {
+		return a.handleErr(err)
+	}
+	return nil
+}
+
+func (a *App) handleListByPriority(ctx context.Context) error {
+	todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: a.project.ID})
+	if err != nil {
+		return a.handleErr(err)
+	}
+	sortByPriority(todos)
+	a.printTodos(todos)
+	return nil
+}
+
+// handleListByTime lists every todo of the active project, most recently
+// created, updated or completed first.
+func (a *App) handleListByTime(ctx context.Context) error {
+	choice, err := a.readLine(ctx, "> Sort by time (c)reated, (u)pdated, or (d)one? ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	var field string
+	switch strings.ToLower(choice) {
+	case "c", "created":
+		field = "created"
+	case "u", "updated":
+		field = "updated"
+	case "d", "done", "completed":
+		field = "completed"
+	default:
+		fmt.Fprintf(a.out, "Error: invalid choice %q, enter 'c', 'u', or 'd'.\n", choice)
+		return nil
+	}
+	todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: a.project.ID})
 	if err != nil {
 		return a.handleErr(err)
 	}
+	sortByTime(todos, timeFields[field])
 	a.printTodos(todos)
 	return nil
 }
 
+// handleReady lists the todos of the active project that can be worked on
+// now.
+func (a *App) handleReady(ctx context.Context) error {
+	todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: a.project.ID})
+	if err != nil {
+		return a.handleErr(err)
+	}
+	all, err := a.listEveryProject(ctx)
+	if err != nil {
+		return a.handleErr(err)
+	}
+	ready := readyTodos(todos, all)
+	if len(ready) == 0 {
+		fmt.Fprintln(a.out, "No todos are ready to work on.")
+		return nil
+	}
+	a.printTodos(ready)
+	return nil
+}
+
+func (a *App) handleListByTag(ctx context.Context) error {
+	input, err := a.readLine(ctx, "> Enter tags to match (space or comma separated): ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	tags := splitTags(input)
+	if len(tags) == 0 {
+		return a.handleErr(fmt.Errorf("%w: enter at least one tag", todo.ErrInvalidTag))
+	}
+	if err := a.printPages(ctx, todo.ListOptions{ProjectID: a.project.ID, Tags: tags}); err != nil {
+		return a.handleErr(err)
+	}
+	return nil
+}
+
+func (a *App) handleSearch(ctx context.Context) error {
+	filter, err := a.readLine(ctx, `> Enter filter (e.g. completed=false AND title~"deploy"): `)
+	if err != nil {
+		return a.handleErr(err)
+	}
+	if err := a.printPages(ctx, todo.ListOptions{ProjectID: a.project.ID, Filter: filter}); err != nil {
+		return a.handleErr(err)
+	}
+	return nil
+}
+
+// handleLiveList shows every todo of the active project and redraws the
+// list whenever the store reports a change, until the user presses Enter.
+// Pending changes are drawn before input is checked, so a burst of updates
+// is never skipped.
+func (a *App) handleLiveList(ctx context.Context) error {
+	w, ok := a.store.(todo.Watcher)
+	if !ok {
+		return a.handleErr(errors.New("live updates are not supported by this storage"))
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := w.Watch(watchCtx)
+	if err != nil {
+		return a.handleErr(err)
+	}
+	if err := a.drawLive(ctx, nil); err != nil {
+		return a.handleErr(err)
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if err := a.liveEvent(ctx, e, ok); err != nil {
+				return a.handleErr(err)
+			}
+			if !ok {
+				return nil
+			}
+			continue
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			return errExit
+		case e, ok := <-events:
+			if err := a.liveEvent(ctx, e, ok); err != nil {
+				return a.handleErr(err)
+			}
+			if !ok {
+				return nil
+			}
+		case line, ok := <-a.lines:
+			if _, err := a.received(line, ok); err != nil {
+				return a.handleErr(err)
+			}
+			return nil
+		}
+	}
+}
+
+func (a *App) liveEvent(ctx context.Context, e todo.Event, ok bool) error {
+	if !ok {
+		fmt.Fprintln(a.out, "Live updates ended.")
+		return nil
+	}
+	return a.drawLive(ctx, &e)
+}
+
+// drawLive clears the terminal, when writing to one, and prints the full
+// list with the change that triggered the redraw.
+func (a *App) drawLive(ctx context.Context, e *todo.Event) error {
+	todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: a.project.ID})
+	if err != nil {
+		return err
+	}
+	if !color.NoColor {
+		fmt.Fprint(a.out, "\033[H\033[2J")
+	}
+	fmt.Fprintln(a.out, "===== Live list (press Enter to stop) =====")
+	switch {
+	case e == nil:
+	case e.Type == todo.EventCreated:
+		fmt.Fprintln(a.out, "A todo was created.")
+	default:
+		fmt.Fprintf(a.out, "Todo %d was %s.\n", e.ID, e.Type)
+	}
+	a.printTodos(todos)
+	return nil
+}
+
+// splitTags splits user input on spaces and commas, dropping a leading '#'
+// from each tag.
+func splitTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+	tags := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimPrefix(f, "#"); f != "" {
+			tags = append(tags, f)
+		}
+	}
+	return tags
+}
+
+// handleDue lists the todos for which match reports true, soonest first.
+func (a *App) handleDue(ctx context.Context, label string, match func(todo.Todo, time.Time) bool) error {
+	todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: a.project.ID})
+	if err != nil {
+		return a.handleErr(err)
+	}
+	now := a.now()
+	var due []todo.Todo
+	for _, t := range todos {
+		if match(t, now) {
+			due = append(due, t)
+		}
+	}
+	if len(due) == 0 {
+		fmt.Fprintf(a.out, "No todos %s.\n", label)
+		return nil
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		di, _ := due[i].DueAt(now.Location())
+		dj, _ := due[j].DueAt(now.Location())
+		return di.Before(dj)
+	})
+	a.printTodos(due)
+	return nil
+}
+
 func (a *App) handleDelete(ctx context.Context) error {
-	id, err := a.listAndPromptID(ctx, "> Enter todo ID to delete: ")
+	picked, err := a.pickTodo(ctx, "> Enter todo ID to delete: ")
 	if err != nil {
 		return a.handleErr(err)
 	}
-	if err := a.store.Delete(ctx, id); err != nil {
+	err = a.store.Delete(expecting(ctx, picked), picked.ID)
+	if errors.Is(err, todo.ErrHasSubtasks) {
+		return a.deleteTree(ctx, picked)
+	}
+	if err != nil {
+		return a.handleChangeErr(ctx, picked.ID, err)
+	}
+	a.recordDelete(picked.ID, picked.Version+1)
+	fmt.Fprintln(a.out, "Todo moved to the trash. Restore it from the Trash menu.")
+	return nil
+}
+
+// deleteTree offers to move t to the trash together with its subtasks.
+func (a *App) deleteTree(ctx context.Context, t todo.Todo) error {
+	answer, err := a.readLine(ctx, fmt.Sprintf("> Todo %d has subtasks. Move them to the trash too? (y/n): ", t.ID))
+	if err != nil {
 		return a.handleErr(err)
 	}
-	fmt.Fprintln(a.out, "Todo deleted successfully.")
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		fmt.Fprintln(a.out, "Todo left as it was.")
+		return nil
+	}
+	n, err := a.store.DeleteTree(expecting(ctx, t), t.ID)
+	if err != nil {
+		return a.handleChangeErr(ctx, t.ID, err)
+	}
+	a.recordDelete(t.ID, t.Version+1)
+	fmt.Fprintf(a.out, "Todo and %d subtask(s) moved to the trash. Restore them from the Trash menu.\n", n-1)
+	return nil
+}
+
+// handleTrash browses the trash, restores a todo from it or empties it.
+func (a *App) handleTrash(ctx context.Context) error {
+	choice, err := a.readLine(ctx, "> (b)rowse the trash, (r)estore a todo, or (e)mpty the trash? ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	trashed := todo.ListOptions{ProjectID: a.project.ID, Trashed: true}
+	switch strings.ToLower(choice) {
+	case "b", "browse":
+		if err := a.printPages(ctx, trashed); err != nil {
+			return a.handleErr(err)
+		}
+	case "r", "restore":
+		picked, err := a.pickFrom(ctx, trashed, "> Enter todo ID to restore: ")
+		if err != nil {
+			return a.handleErr(err)
+		}
+		restored, err := a.store.Restore(expecting(ctx, picked), picked.ID)
+		if err != nil {
+			return a.handleErr(err)
+		}
+		a.recordRestore(restored)
+		fmt.Fprintln(a.out, "Todo restored.")
+		a.printTodos([]todo.Todo{restored})
+	case "e", "empty":
+		answer, err := a.readLine(ctx, "> Permanently delete every todo in the trash, in every project? (y/n): ")
+		if err != nil {
+			return a.handleErr(err)
+		}
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Fprintln(a.out, "Trash left as it was.")
+			return nil
+		}
+		n, err := a.store.PurgeTrash(ctx)
+		if err != nil {
+			return a.handleErr(err)
+		}
+		fmt.Fprintf(a.out, "Trash emptied: %d todo(s) permanently deleted.\n", n)
+	default:
+		fmt.Fprintf(a.out, "Error: invalid choice %q, enter 'b', 'r', or 'e'.\n", choice)
+	}
 	return nil
 }
 
@@ -226,53 +892,104 @@ func (a *App) handleSetCompleted(ctx context.Context, completed bool) error {
 	if !completed {
 		action = "incomplete"
 	}
-	id, err := a.listAndPromptID(ctx, fmt.Sprintf("> Enter todo ID to mark as %s: ", action))
+	picked, err := a.pickTodo(ctx, fmt.Sprintf("> Enter todo ID to mark as %s: ", action))
 	if err != nil {
 		return a.handleErr(err)
 	}
-	if err := a.store.SetCompleted(ctx, id, completed); err != nil {
+	updated, err := a.store.SetCompleted(expecting(ctx, picked), picked.ID, completed)
+	if err != nil {
 		if errors.Is(err, todo.ErrAlreadyCompleted) || errors.Is(err, todo.ErrAlreadyIncomplete) {
-			fmt.Fprintf(a.out, "Info: todo %d is already %s.\n", id, action)
+			fmt.Fprintf(a.out, "Info: todo %d is already %s.\n", picked.ID, action)
 			return nil
 		}
-		return a.handleErr(err)
+		return a.handleChangeErr(ctx, picked.ID, err)
 	}
+	a.recordSetCompleted(updated, spawned(picked, updated))
 	fmt.Fprintf(a.out, "Todo marked as %s.\n", action)
+	a.printTodos([]todo.Todo{updated})
+	a.printNextOccurrence(picked, updated)
 	return nil
 }
 
+// spawned returns the ID of the next occurrence a change of a recurring todo
+// from before to after added, or 0 if it added none.
+func spawned(before, after todo.Todo) int {
+	if before.NextID == 0 {
+		return after.NextID
+	}
+	return 0
+}
+
+func (a *App) printNextOccurrence(before, after todo.Todo) {
+	if id := spawned(before, after); id != 0 {
+		fmt.Fprintf(a.out, "Next occurrence added as #%d.\n", id)
+	}
+}
+
 func (a *App) handleEdit(ctx context.Context) error {
-	id, err := a.listAndPromptID(ctx, "> Enter todo ID to edit: ")
+	picked, err := a.pickTodo(ctx, "> Enter todo ID to edit: ")
 	if err != nil {
 		return a.handleErr(err)
 	}
 
-	field, err := a.readLine(ctx, "> Edit (t)itle, (d)escription, or (b)oth? ")
+	field, err := a.readLine(ctx, "> Edit (t)itle, (d)escription, d(u)e date, (p)riority, ta(g)s, bloc(k)ers, (r)epeat, (m)ove under a parent, pro(j)ect, or (b)oth? ")
 	if err != nil {
 		return a.handleErr(err)
 	}
 
 	switch strings.ToLower(field) {
 	case "t", "title":
-		if err := a.doEditTitle(ctx, id); err != nil {
-			return a.handleErr(err)
+		if err := a.doEditTitle(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
 		}
 
 	case "d", "description":
-		if err := a.doEditDescription(ctx, id); err != nil {
-			return a.handleErr(err)
+		if err := a.doEditDescription(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
 		}
 
 	case "b", "both":
-		if err := a.doEditTitle(ctx, id); err != nil {
-			return a.handleErr(err)
+		if err := a.doEditBoth(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
 		}
-		if err := a.doEditDescription(ctx, id); err != nil {
-			return a.handleErr(err)
+
+	case "u", "due":
+		if err := a.doEditDue(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "p", "priority":
+		if err := a.doEditPriority(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "g", "tags":
+		if err := a.doEditTags(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "k", "blockers":
+		if err := a.doEditBlockers(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "r", "repeat":
+		if err := a.doEditRepeat(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "m", "move":
+		if err := a.doMove(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
+		}
+
+	case "j", "project":
+		if err := a.doMoveToProject(ctx, picked); err != nil {
+			return a.handleChangeErr(ctx, picked.ID, err)
 		}
 
 	default:
-		fmt.Fprintf(a.out, "Error: invalid choice %q, enter 't', 'd', or 'b'.\n", field)
+		fmt.Fprintf(a.out, "Error: invalid choice %q, enter 't', 'd', 'u', 'p', 'g', 'k', 'r', 'm', 'j', or 'b'.\n", field)
 	}
 
 	return nil
@@ -280,36 +997,304 @@ func (a *App) handleEdit(ctx context.Context) error {
 
 // doEditTitle prompts for and applies a title change.
 // Returns nil on success (including "unchanged" info), or an error.
-func (a *App) doEditTitle(ctx context.Context, id int) error {
+func (a *App) doEditTitle(ctx context.Context, t todo.Todo) error {
 	title, err := a.readLine(ctx, "> Enter new title: ")
 	if err != nil {
 		return err
 	}
-	if err := a.store.EditTitle(ctx, id, title); err != nil {
+	updated, err := a.store.EditTitle(expecting(ctx, t), t.ID, title)
+	if err != nil {
 		if errors.Is(err, todo.ErrTitleUnchanged) {
 			fmt.Fprintln(a.out, "Info: title is already the same.")
 			return nil
 		}
 		return err
 	}
+	a.recordEdit("title edit", t, updated, []string{todo.FieldTitle})
 	fmt.Fprintln(a.out, "Title updated successfully.")
+	a.printTodos([]todo.Todo{updated})
 	return nil
 }
 
 // doEditDescription prompts for and applies a description change.
 // Returns nil on success (including "unchanged" info), or an error.
-func (a *App) doEditDescription(ctx context.Context, id int) error {
+func (a *App) doEditDescription(ctx context.Context, t todo.Todo) error {
 	desc, err := a.readLine(ctx, "> Enter new description: ")
 	if err != nil {
 		return err
 	}
-	if err := a.store.EditDescription(ctx, id, desc); err != nil {
+	updated, err := a.store.EditDescription(expecting(ctx, t), t.ID, desc)
+	if err != nil {
 		if errors.Is(err, todo.ErrDescriptionUnchanged) {
 			fmt.Fprintln(a.out, "Info: description is already the same.")
 			return nil
 		}
 		return err
 	}
+	a.recordEdit("description edit", t, updated, []string{todo.FieldDescription})
 	fmt.Fprintln(a.out, "Description updated successfully.")
+	a.printTodos([]todo.Todo{updated})
 	return nil
 }
+
+// doEditBoth prompts for a new title and description and applies them in a
+// single update, so neither changes unless both are valid. The title is
+// checked before prompting for the description.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doEditBoth(ctx context.Context, t todo.Todo) error {
+	title, err := a.readLine(ctx, "> Enter new title: ")
+	if err != nil {
+		return err
+	}
+	if err := todo.ValidateTitle(title); err != nil {
+		return err
+	}
+	desc, err := a.readLine(ctx, "> Enter new description: ")
+	if err != nil {
+		return err
+	}
+	mask := []string{todo.FieldTitle, todo.FieldDescription}
+	updated, err := a.store.Update(expecting(ctx, t), todo.Todo{ID: t.ID, Title: title, Description: desc}, mask)
+	if err != nil {
+		if errors.Is(err, todo.ErrTodoUnchanged) {
+			fmt.Fprintln(a.out, "Info: title and description are already the same.")
+			return nil
+		}
+		return err
+	}
+	a.recordEdit("title and description edit", t, updated, mask)
+	fmt.Fprintln(a.out, "Title and description updated successfully.")
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+// doEditDue prompts for and applies a due date change. A blank date clears it.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doEditDue(ctx context.Context, t todo.Todo) error {
+	dueDate, dueTime, err := a.readDue(ctx, "> Enter new due date (YYYY-MM-DD, blank to clear): ")
+	if err != nil {
+		return err
+	}
+	updated, err := a.store.EditDue(expecting(ctx, t), t.ID, dueDate, dueTime)
+	if err != nil {
+		if errors.Is(err, todo.ErrDueUnchanged) {
+			fmt.Fprintln(a.out, "Info: due date is already the same.")
+			return nil
+		}
+		return err
+	}
+	a.recordEdit("due date edit", t, updated, []string{todo.FieldDueDate, todo.FieldDueTime})
+	fmt.Fprintln(a.out, "Due date updated successfully.")
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+// doEditPriority prompts for and applies a priority change.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doEditPriority(ctx context.Context, t todo.Todo) error {
+	priority, err := a.readPriority(ctx, "> Enter new priority (none/low/medium/high/urgent): ")
+	if err != nil {
+		return err
+	}
+	updated, err := a.store.EditPriority(expecting(ctx, t), t.ID, priority)
+	if err != nil {
+		if errors.Is(err, todo.ErrPriorityUnchanged) {
+			fmt.Fprintln(a.out, "Info: priority is already the same.")
+			return nil
+		}
+		return err
+	}
+	a.recordEdit("priority edit", t, updated, []string{todo.FieldPriority})
+	fmt.Fprintln(a.out, "Priority updated successfully.")
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+// doMove prompts for a new parent and makes the todo a subtask of it, or a
+// top-level todo if the input is blank.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doMove(ctx context.Context, t todo.Todo) error {
+	input, err := a.readLine(ctx, "> Enter the ID of the new parent (blank for top level): ")
+	if err != nil {
+		return err
+	}
+	parentID := 0
+	if input != "" {
+		if parentID, err = strconv.Atoi(input); err != nil {
+			return fmt.Errorf("invalid ID %q", input)
+		}
+	}
+	mask := []string{todo.FieldParentID}
+	updated, err := a.store.Update(expecting(ctx, t), todo.Todo{ID: t.ID, ParentID: parentID}, mask)
+	if err != nil {
+		if errors.Is(err, todo.ErrTodoUnchanged) {
+			fmt.Fprintln(a.out, "Info: todo is already there.")
+			return nil
+		}
+		return err
+	}
+	a.recordEdit("move", t, updated, mask)
+	fmt.Fprintln(a.out, "Todo moved.")
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+// doEditRepeat prompts for and applies a new repeat schedule. A blank
+// schedule stops the todo repeating.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doEditRepeat(ctx context.Context, t todo.Todo) error {
+	rule, err := a.readRepeat(ctx, "blank to stop repeating")
+	if err != nil {
+		return err
+	}
+	mask := []string{todo.FieldRecurrence}
+	updated, err := a.store.Update(expecting(ctx, t), todo.Todo{ID: t.ID, Recurrence: rule}, mask)
+	if err != nil {
+		if errors.Is(err, todo.ErrTodoUnchanged) {
+			fmt.Fprintln(a.out, "Info: repeat schedule is already the same.")
+			return nil
+		}
+		return err
+	}
+	a.recordEdit("repeat edit", t, updated, mask)
+	fmt.Fprintln(a.out, "Repeat schedule updated successfully.")
+	a.printTodos([]todo.Todo{updated})
+	a.printNextOccurrence(t, updated)
+	return nil
+}
+
+// doEditTags prompts for tags to attach ("tag") or detach ("-tag") and
+// applies each in turn, stopping at the first error.
+func (a *App) doEditTags(ctx context.Context, t todo.Todo) error {
+	input, err := a.readLine(ctx, "> Enter tags to add, prefix with '-' to remove (e.g. work -home): ")
+	if err != nil {
+		return err
+	}
+	tags := splitTags(input)
+	if len(tags) == 0 {
+		return fmt.Errorf("%w: enter at least one tag", todo.ErrInvalidTag)
+	}
+	// Each change is made at the version the previous one returned, and
+	// whatever was applied is undone as one.
+	before, changed := t, false
+	defer func() {
+		if changed {
+			a.recordEdit("tag edit", before, t, []string{todo.FieldTags})
+		}
+	}()
+	for _, tag := range tags {
+		if name, ok := strings.CutPrefix(tag, "-"); ok {
+			updated, err := a.store.RemoveTag(expecting(ctx, t), t.ID, name)
+			if err != nil {
+				if errors.Is(err, todo.ErrTagNotPresent) {
+					fmt.Fprintf(a.out, "Info: todo %d has no tag %q.\n", t.ID, name)
+					continue
+				}
+				return err
+			}
+			t, changed = updated, true
+			fmt.Fprintf(a.out, "Tag %q removed.\n", name)
+			continue
+		}
+		updated, err := a.store.AddTag(expecting(ctx, t), t.ID, tag)
+		if err != nil {
+			if errors.Is(err, todo.ErrTagAlreadyPresent) {
+				fmt.Fprintf(a.out, "Info: todo %d already has tag %q.\n", t.ID, tag)
+				continue
+			}
+			return err
+		}
+		t, changed = updated, true
+		fmt.Fprintf(a.out, "Tag %q added.\n", tag)
+	}
+	if changed {
+		a.printTodos([]todo.Todo{t})
+	}
+	return nil
+}
+
+// doEditBlockers prompts for the IDs of todos to block the todo on, or with
+// a '-' prefix to unblock it from, and applies each in turn, stopping at the
+// first error.
+func (a *App) doEditBlockers(ctx context.Context, t todo.Todo) error {
+	input, err := a.readLine(ctx, "> Enter IDs of todos to wait on, prefix with '-' to stop waiting (e.g. 3 -5): ")
+	if err != nil {
+		return err
+	}
+	fields := splitTags(input)
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: enter at least one ID", todo.ErrInvalidID)
+	}
+	// As with tags, each change is made at the version the previous one
+	// returned, and whatever was applied is undone as one.
+	var applied []dependencyChange
+	defer func() {
+		if len(applied) > 0 {
+			a.recordDependencies(t, applied)
+		}
+	}()
+	for _, field := range fields {
+		name, remove := strings.CutPrefix(field, "-")
+		blockerID, err := strconv.Atoi(strings.TrimPrefix(name, "#"))
+		if err != nil {
+			return fmt.Errorf("invalid ID %q", name)
+		}
+		change := dependencyChange{blockerID: blockerID, add: !remove}
+		updated, err := a.changeDependency(expecting(ctx, t), t.ID, change)
+		switch {
+		case errors.Is(err, todo.ErrDependencyNotPresent):
+			fmt.Fprintf(a.out, "Info: todo %d is not blocked by #%d.\n", t.ID, blockerID)
+			continue
+		case errors.Is(err, todo.ErrDependencyPresent):
+			fmt.Fprintf(a.out, "Info: todo %d is already blocked by #%d.\n", t.ID, blockerID)
+			continue
+		case err != nil:
+			return err
+		}
+		t = updated
+		applied = append(applied, change)
+		if remove {
+			fmt.Fprintf(a.out, "No longer blocked by #%d.\n", blockerID)
+		} else {
+			fmt.Fprintf(a.out, "Now blocked by #%d.\n", blockerID)
+		}
+	}
+	if len(applied) > 0 {
+		a.printTodos([]todo.Todo{t})
+	}
+	return nil
+}
+
+func (a *App) readPriority(ctx context.Context, prompt string) (todo.Priority, error) {
+	input, err := a.readLine(ctx, prompt)
+	if err != nil {
+		return todo.PriorityNone, err
+	}
+	return todo.ParsePriority(input)
+}
+
+// readRepeat prompts for a repeat schedule, with hint saying what a blank
+// one means, and returns it as a rule.
+func (a *App) readRepeat(ctx context.Context, hint string) (string, error) {
+	input, err := a.readLine(ctx, fmt.Sprintf("> Enter repeat schedule (e.g. %s, %s): ", repeatExample, hint))
+	if err != nil {
+		return "", err
+	}
+	return parseRepeat(input)
+}
+
+// readDue prompts for a due date and, if one was given, an optional due time.
+func (a *App) readDue(ctx context.Context, prompt string) (date, clock string, err error) {
+	date, err = a.readLine(ctx, prompt)
+	if err != nil || date == "" {
+		return "", "", err
+	}
+	clock, err = a.readLine(ctx, "> Enter due time (HH:MM, optional): ")
+	if err != nil {
+		return "", "", err
+	}
+	if err := todo.ValidateDue(date, clock); err != nil {
+		return "", "", err
+	}
+	return date, clock, nil
+}
@@ -6,11 +6,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 
+	"github.com/amharshit45/todos-cli-/storage"
 	"github.com/amharshit45/todos-cli-/todo"
 )
 
@@ -19,87 +22,28 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-type mockStorage struct {
-	todos  []todo.Todo
-	nextID int
+// watchingStorage is a MemoryStorage that also streams the events fed into
+// its channel.
+type watchingStorage struct {
+	*storage.MemoryStorage
+	events chan todo.Event
 }
 
-func newMockStorage() *mockStorage {
-	return &mockStorage{nextID: 1}
+func (w *watchingStorage) Watch(context.Context) (<-chan todo.Event, error) {
+	return w.events, nil
 }
 
-func (m *mockStorage) Add(_ context.Context, title, description string) error {
-	if err := todo.ValidateTitle(title); err != nil {
-		return err
-	}
-	m.todos = append(m.todos, todo.Todo{ID: m.nextID, Title: title, Description: description})
-	m.nextID++
-	return nil
-}
-
-func (m *mockStorage) List(_ context.Context) ([]todo.Todo, error) {
-	result := make([]todo.Todo, len(m.todos))
-	copy(result, m.todos)
-	return result, nil
-}
-
-func (m *mockStorage) Delete(_ context.Context, id int) error {
-	for i, t := range m.todos {
-		if t.ID == id {
-			m.todos = append(m.todos[:i], m.todos[i+1:]...)
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
+// testNow is the fixed clock used by every test App: Wednesday 2026-03-04 12:00 UTC.
+var testNow = time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
 
-func (m *mockStorage) SetCompleted(_ context.Context, id int, completed bool) error {
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Completed == completed {
-				if completed {
-					return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
-				}
-				return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
-			}
-			m.todos[i].Completed = completed
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
-
-func (m *mockStorage) EditTitle(_ context.Context, id int, title string) error {
-	if err := todo.ValidateTitle(title); err != nil {
-		return err
-	}
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Title == title {
-				return fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged)
-			}
-			m.todos[i].Title = title
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
-
-func (m *mockStorage) EditDescription(_ context.Context, id int, description string) error {
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Description == description {
-				return fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged)
-			}
-			m.todos[i].Description = description
-			return nil
-		}
+// listTodos returns every todo in store.
+func listTodos(t *testing.T, store todo.Storage) []todo.Todo {
+	t.Helper()
+	todos, _, err := store.List(context.Background(), todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
 	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
-
-func (m *mockStorage) Close(_ context.Context) error {
-	return nil
+	return todos
 }
 
 func runApp(t *testing.T, store todo.Storage, input string) string {
@@ -107,6 +51,7 @@ func runApp(t *testing.T, store todo.Storage, input string) string {
 	var buf bytes.Buffer
 	scanner := bufio.NewScanner(strings.NewReader(input))
 	app := New(store, scanner, &buf)
+	app.now = func() time.Time { return testNow }
 	if err := app.Run(context.Background()); err != nil {
 		t.Fatalf("Run: %v", err)
 	}
@@ -114,8 +59,8 @@ func runApp(t *testing.T, store todo.Storage, input string) string {
 }
 
 func TestExit(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "q\n")
 
 	if !strings.Contains(output, "===== Todo CLI =====") {
 		t.Fatal("expected menu header in output")
@@ -123,8 +68,8 @@ func TestExit(t *testing.T) {
 }
 
 func TestHelpMenu(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "0\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "0\nq\n")
 
 	count := strings.Count(output, "===== Todo CLI =====")
 	if count != 2 {
@@ -133,47 +78,292 @@ func TestHelpMenu(t *testing.T) {
 }
 
 func TestAddTodo(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nbuy milk\nfrom the store\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nbuy milk\nfrom the store\n\n\nq\n")
+	todos := listTodos(t, store)
 
-	if len(store.todos) != 1 {
-		t.Fatalf("expected 1 todo, got %d", len(store.todos))
+	if len(todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(todos))
 	}
-	if store.todos[0].Title != "buy milk" {
-		t.Fatalf("expected title 'buy milk', got %q", store.todos[0].Title)
+	if todos[0].Title != "buy milk" {
+		t.Fatalf("expected title 'buy milk', got %q", todos[0].Title)
 	}
-	if store.todos[0].Description != "from the store" {
-		t.Fatalf("expected description 'from the store', got %q", store.todos[0].Description)
+	if todos[0].Description != "from the store" {
+		t.Fatalf("expected description 'from the store', got %q", todos[0].Description)
 	}
-	if !strings.Contains(output, "Todo added successfully.") {
-		t.Fatalf("expected success message in output, got:\n%s", output)
+	if !strings.Contains(output, "Added #1") || !strings.Contains(output, "[ ] 1. buy milk - from the store") {
+		t.Fatalf("expected the added todo in output, got:\n%s", output)
 	}
 }
 
 func TestAddTodoNoDescription(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nbuy milk\n\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nbuy milk\n\n\n\nq\n")
+	todos := listTodos(t, store)
 
-	if len(store.todos) != 1 {
-		t.Fatalf("expected 1 todo, got %d", len(store.todos))
+	if len(todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(todos))
 	}
-	if store.todos[0].Title != "buy milk" {
-		t.Fatalf("expected title 'buy milk', got %q", store.todos[0].Title)
+	if todos[0].Title != "buy milk" {
+		t.Fatalf("expected title 'buy milk', got %q", todos[0].Title)
 	}
-	if store.todos[0].Description != "" {
-		t.Fatalf("expected empty description, got %q", store.todos[0].Description)
+	if todos[0].Description != "" {
+		t.Fatalf("expected empty description, got %q", todos[0].Description)
 	}
-	if !strings.Contains(output, "Todo added successfully.") {
+	if !strings.Contains(output, "Added #1") {
 		t.Fatalf("expected success message in output, got:\n%s", output)
 	}
 }
 
+func TestAddTodoWithDue(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nreport\n\n\n2026-03-05\n09:30\n\n2\nq\n")
+	todos := listTodos(t, store)
+
+	if todos[0].DueDate != "2026-03-05" || todos[0].DueTime != "09:30" {
+		t.Fatalf("unexpected due: %+v", todos[0])
+	}
+	if !strings.Contains(output, "report (due 2026-03-05 09:30)") {
+		t.Fatalf("expected due date in list output, got:\n%s", output)
+	}
+}
+
+func TestAddTodoInvalidDue(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nreport\n\n\nnext week\n\nq\n")
+	todos := listTodos(t, store)
+
+	if len(todos) != 0 {
+		t.Fatalf("expected no todos, got %d", len(todos))
+	}
+	if !strings.Contains(output, "Error: invalid due date") {
+		t.Fatalf("expected due date error, got:\n%s", output)
+	}
+}
+
+func TestAddTodoWithPriority(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nfix prod\n\nUrgent\n\n2\nq\n")
+	todos := listTodos(t, store)
+
+	if todos[0].Priority != todo.PriorityUrgent {
+		t.Fatalf("expected urgent priority, got %v", todos[0].Priority)
+	}
+	if !strings.Contains(output, "1. [urgent] fix prod") {
+		t.Fatalf("expected priority marker in list output, got:\n%s", output)
+	}
+}
+
+func TestAddTodoInvalidPriority(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nfix prod\n\nasap\nq\n")
+	todos := listTodos(t, store)
+
+	if len(todos) != 0 {
+		t.Fatalf("expected no todos, got %d", len(todos))
+	}
+	if !strings.Contains(output, "Error: invalid priority") {
+		t.Fatalf("expected priority error, got:\n%s", output)
+	}
+}
+
+func TestListByPriority(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "plain"},
+		todo.Todo{ID: 2, Title: "low one", Priority: todo.PriorityLow},
+		todo.Todo{ID: 3, Title: "urgent one", Priority: todo.PriorityUrgent},
+		todo.Todo{ID: 4, Title: "high one", Priority: todo.PriorityHigh},
+		todo.Todo{ID: 5, Title: "another low", Priority: todo.PriorityLow},
+	)
+	output := runApp(t, store, "10\nq\n")
+
+	order := []string{"urgent one", "high one", "low one", "another low", "plain"}
+	last := -1
+	for _, title := range order {
+		idx := strings.Index(output, title)
+		if idx <= last {
+			t.Fatalf("expected %q after previous entries, got:\n%s", title, output)
+		}
+		last = idx
+	}
+}
+
+// timedStorage holds todos 1 to 3, created in that order, of which 1 was
+// changed since and 3 completed.
+func timedStorage() *storage.MemoryStorage {
+	return storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "changed", Version: 2, CreatedAt: testNow.Add(-72 * time.Hour), UpdatedAt: testNow.Add(-time.Hour)},
+		todo.Todo{ID: 2, Title: "finished", Completed: true, Version: 2,
+			CreatedAt: testNow.Add(-48 * time.Hour), UpdatedAt: testNow.Add(-2 * time.Hour), CompletedAt: testNow.Add(-2 * time.Hour)},
+		todo.Todo{ID: 3, Title: "fresh", Version: 1, CreatedAt: testNow.Add(-30 * time.Minute), UpdatedAt: testNow.Add(-30 * time.Minute)},
+	)
+}
+
+func TestListShowsAges(t *testing.T) {
+	output := runApp(t, timedStorage(), "2\nq\n")
+
+	for _, want := range []string{
+		"1. changed (updated 1h ago)\n",
+		"2. finished (done 2h ago)\n",
+		"3. fresh (added 30m ago)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestListByTime(t *testing.T) {
+	tests := []struct {
+		choice string
+		order  []string
+	}{
+		{"c", []string{"fresh", "finished", "changed"}},
+		{"u", []string{"fresh", "changed", "finished"}},
+		// Todos never completed follow in ID order.
+		{"d", []string{"finished", "changed", "fresh"}},
+	}
+	for _, tt := range tests {
+		output := runApp(t, timedStorage(), "17\n"+tt.choice+"\nq\n")
+		last := -1
+		for _, title := range tt.order {
+			idx := strings.Index(output, ". "+title)
+			if idx <= last {
+				t.Fatalf("sort %q: expected %q after previous entries, got:\n%s", tt.choice, title, output)
+			}
+			last = idx
+		}
+	}
+
+	output := runApp(t, timedStorage(), "17\nx\nq\n")
+	if !strings.Contains(output, `Error: invalid choice "x", enter 'c', 'u', or 'd'.`) {
+		t.Fatalf("expected invalid choice error, got:\n%s", output)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{-time.Hour, "just now"},
+		{59 * time.Second, "just now"},
+		{90 * time.Second, "1m ago"},
+		{5 * time.Hour, "5h ago"},
+		{3*24*time.Hour + time.Hour, "3d ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+	}
+	for _, tt := range tests {
+		if got := relativeAge(testNow.Add(-tt.ago), testNow); got != tt.want {
+			t.Errorf("relativeAge(%v ago) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestListByTag(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "deploy", Tags: []string{"work", "ops"}},
+		todo.Todo{ID: 2, Title: "review", Tags: []string{"work"}},
+		todo.Todo{ID: 3, Title: "groceries", Tags: []string{"home"}},
+	)
+	output := runApp(t, store, "11\n#work, ops\nq\n")
+
+	if !strings.Contains(output, "1. deploy #work #ops") {
+		t.Fatalf("expected tagged todo in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "review") || strings.Contains(output, "groceries") {
+		t.Fatalf("expected only todos carrying both tags, got:\n%s", output)
+	}
+}
+
+func TestListByTagEmptyInput(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "11\n\nq\n")
+
+	if !strings.Contains(output, "Error: invalid tag") {
+		t.Fatalf("expected invalid tag error, got:\n%s", output)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "deploy api"},
+		todo.Todo{ID: 2, Title: "deploy docs", Completed: true},
+		todo.Todo{ID: 3, Title: "groceries"},
+	)
+	output := runApp(t, store, "12\ncompleted=false AND title~\"Deploy\"\nq\n")
+
+	if !strings.Contains(output, "1. deploy api") {
+		t.Fatalf("expected matching todo in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "deploy docs") || strings.Contains(output, "groceries") {
+		t.Fatalf("expected only matching todos, got:\n%s", output)
+	}
+}
+
+func TestSearchInvalidFilter(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "12\ntitle<\"x\"\nq\n")
+
+	if !strings.Contains(output, "Error: invalid filter") {
+		t.Fatalf("expected invalid filter error, got:\n%s", output)
+	}
+}
+
+func TestDueViews(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "late", DueDate: "2026-03-03"},
+		todo.Todo{ID: 2, Title: "this morning", DueDate: "2026-03-04", DueTime: "08:00"},
+		todo.Todo{ID: 3, Title: "tonight", DueDate: "2026-03-04", DueTime: "20:00"},
+		todo.Todo{ID: 4, Title: "sunday", DueDate: "2026-03-08"},
+		todo.Todo{ID: 5, Title: "next month", DueDate: "2026-04-01"},
+		todo.Todo{ID: 6, Title: "done late", DueDate: "2026-03-01", Completed: true},
+		todo.Todo{ID: 7, Title: "no due"},
+	)
+
+	tests := []struct {
+		name    string
+		option  string
+		want    []string
+		notWant []string
+	}{
+		{"overdue", "7", []string{"late", "this morning"}, []string{"tonight", "sunday", "done late", "no due"}},
+		{"today", "8", []string{"tonight"}, []string{"late", "this morning", "sunday"}},
+		{"this week", "9", []string{"tonight", "sunday"}, []string{"late", "next month", "no due"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := runApp(t, store, tt.option+"\nq\n")
+			for _, w := range tt.want {
+				if !strings.Contains(output, ". "+w+" ") {
+					t.Fatalf("expected %q in output, got:\n%s", w, output)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(output, ". "+w+" ") {
+					t.Fatalf("did not expect %q in output, got:\n%s", w, output)
+				}
+			}
+		})
+	}
+}
+
+func TestDueViewEmpty(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "7\nq\n")
+
+	if !strings.Contains(output, "No todos overdue.") {
+		t.Fatalf("expected empty overdue message, got:\n%s", output)
+	}
+}
+
 func TestListTodos(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\ntask one\ndetails\n1\ntask two\n\n2\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask one\ndetails\n\n\n1\ntask two\n\n\n\n2\nq\n")
+	todos := listTodos(t, store)
 
-	if len(store.todos) != 2 {
-		t.Fatalf("expected 2 todos, got %d", len(store.todos))
+	if len(todos) != 2 {
+		t.Fatalf("expected 2 todos, got %d", len(todos))
 	}
 	if !strings.Contains(output, "task one - details") {
 		t.Fatalf("expected 'task one - details' in list output, got:\n%s", output)
@@ -183,26 +373,400 @@ func TestListTodos(t *testing.T) {
 	}
 }
 
+// seededStorage returns a store holding n todos titled "task 01" onwards.
+func seededStorage(n int) *storage.MemoryStorage {
+	seed := make([]todo.Todo, n)
+	for i := range seed {
+		seed[i] = todo.Todo{ID: i + 1, Title: fmt.Sprintf("task %02d", i+1)}
+	}
+	return storage.NewMemoryStorage(seed...)
+}
+
+func TestListPaging(t *testing.T) {
+	store := seededStorage(25)
+	output := runApp(t, store, "2\nn\nq\n")
+
+	first := strings.Index(output, "task 20")
+	prompt := strings.Index(output, "> Enter n for the next page")
+	second := strings.Index(output, "task 21")
+	if first < 0 || prompt < first || second < prompt {
+		t.Fatalf("expected page 1, next-page prompt, then page 2, got:\n%s", output)
+	}
+	if strings.Count(output, "> Enter n for the next page") != 1 {
+		t.Fatalf("expected a single next-page prompt, got:\n%s", output)
+	}
+}
+
+func TestListPagingStop(t *testing.T) {
+	store := seededStorage(25)
+	output := runApp(t, store, "2\n\nq\n")
+
+	if strings.Contains(output, "task 21") {
+		t.Fatalf("expected listing to stop after the first page, got:\n%s", output)
+	}
+}
+
+func TestDeleteFromSecondPage(t *testing.T) {
+	store := seededStorage(25)
+	output := runApp(t, store, "3\nn\n23\nq\n")
+	todos := listTodos(t, store)
+
+	if !strings.Contains(output, "> Enter todo ID to delete (n for next page): ") {
+		t.Fatalf("expected paged delete prompt, got:\n%s", output)
+	}
+	if len(todos) != 24 {
+		t.Fatalf("expected 24 todos, got %d", len(todos))
+	}
+	for _, td := range todos {
+		if td.ID == 23 {
+			t.Fatal("expected todo 23 to be deleted")
+		}
+	}
+}
+
 func TestDeleteTodo(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nto delete\n\n1\nto keep\n\n3\n1\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nto delete\n\n\n\n1\nto keep\n\n\n\n3\n1\nq\n")
+	todos := listTodos(t, store)
 
-	if len(store.todos) != 1 {
-		t.Fatalf("expected 1 todo, got %d", len(store.todos))
+	if len(todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(todos))
 	}
-	if store.todos[0].Title != "to keep" {
-		t.Fatalf("expected 'to keep', got %q", store.todos[0].Title)
+	if todos[0].Title != "to keep" {
+		t.Fatalf("expected 'to keep', got %q", todos[0].Title)
 	}
-	if !strings.Contains(output, "Todo deleted successfully.") {
+	if !strings.Contains(output, "Todo moved to the trash.") {
 		t.Fatalf("expected delete message in output, got:\n%s", output)
 	}
 }
 
+// treeStorage holds todo 1 with subtasks 2 and 4, of which 2 is done and has
+// a subtask 3 of its own.
+func treeStorage() *storage.MemoryStorage {
+	return storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "release", Version: 1},
+		todo.Todo{ID: 2, Title: "changelog", Completed: true, ParentID: 1, Version: 1},
+		todo.Todo{ID: 3, Title: "proofread", ParentID: 2, Version: 1},
+		todo.Todo{ID: 4, Title: "tag", ParentID: 1, Version: 1},
+		todo.Todo{ID: 5, Title: "other", Version: 1},
+	)
+}
+
+func TestListTree(t *testing.T) {
+	output := runApp(t, treeStorage(), "2\nq\n")
+
+	want := "[ ] 1. release (1/2 done)\n" +
+		"    [✓] 2. changelog (0/1 done)\n" +
+		"        [ ] 3. proofread\n" +
+		"    [ ] 4. tag\n" +
+		"[ ] 5. other\n"
+	if !strings.Contains(output, want) {
+		t.Fatalf("expected tree\n%s\ngot:\n%s", want, output)
+	}
+}
+
+func TestListTreeWithoutParent(t *testing.T) {
+	// A subtask whose parent is filtered out is shown at the top level.
+	output := runApp(t, treeStorage(), "12\ncompleted=false\nq\n")
+
+	if !strings.Contains(output, "[ ] 1. release (0/1 done)\n    [ ] 4. tag\n[ ] 3. proofread\n") {
+		t.Fatalf("expected orphaned subtask at the top level, got:\n%s", output)
+	}
+}
+
+func TestAddSubtask(t *testing.T) {
+	store := treeStorage()
+	output := runApp(t, store, "18\n4\npush\n\n\n\nq\n")
+
+	if !strings.Contains(output, "Added #6") {
+		t.Fatalf("expected add message, got:\n%s", output)
+	}
+	if got := listTodos(t, store)[5]; got.Title != "push" || got.ParentID != 4 {
+		t.Fatalf("expected subtask of 4, got %+v", got)
+	}
+}
+
+func TestEditMove(t *testing.T) {
+	store := treeStorage()
+	output := runApp(t, store, "6\n5\nm\n4\n6\n1\nm\n3\n6\n3\nm\n\nq\n")
+
+	if !strings.Contains(output, "Todo moved.") {
+		t.Fatalf("expected move message, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Error: todo 1: parent would create a cycle") {
+		t.Fatalf("expected cycle error, got:\n%s", output)
+	}
+	todos := listTodos(t, store)
+	if todos[4].ParentID != 4 || todos[2].ParentID != 0 {
+		t.Fatalf("expected 5 under 4 and 3 at the top level, got %+v", todos)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "inbox task", Version: 1})
+	output := runApp(t, store, "20\nc\nWork\n20\ns\nwork\n0\n1\nreport\n\n\n\n2\n20\ns\n0\n2\nq\n")
+
+	for _, want := range []string{"Project Work created as #1.", "Switched to project Work.", "Project: Work", "Added #2", "Switched to project Inbox."} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q, got:\n%s", want, output)
+		}
+	}
+	todos, _, err := store.List(context.Background(), todo.ListOptions{ProjectID: 1})
+	if err != nil || len(todos) != 1 || todos[0].Title != "report" {
+		t.Fatalf("expected report in Work, got %+v, %v", todos, err)
+	}
+	// Each listing shows only the active project's todos.
+	work, inbox, _ := strings.Cut(output[strings.Index(output, "Added #2"):], "Switched to project Inbox.")
+	if strings.Contains(work, "inbox task") || !strings.Contains(inbox, "inbox task") || strings.Contains(inbox, "report") {
+		t.Fatalf("expected listings scoped to the active project, got:\n%s", output)
+	}
+}
+
+func TestProjectChanges(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	for _, name := range []string{"Work", "Home"} {
+		if _, err := store.CreateProject(context.Background(), name); err != nil {
+			t.Fatalf("CreateProject: %v", err)
+		}
+	}
+	output := runApp(t, store, "20\nn\nWork\nOffice\n20\na\n2\n20\nl\n20\nd\nhome\ny\n20\nc\ninbox\nq\n")
+
+	for _, want := range []string{"Project renamed to Office.", "Project Home archived.", "1. Office", "2. Home (archived)", "Project Home deleted.", "Error: project name already taken"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q, got:\n%s", want, output)
+		}
+	}
+	projects, err := store.ListProjects(context.Background())
+	if err != nil || len(projects) != 1 || projects[0].Name != "Office" {
+		t.Fatalf("expected only Office left, got %+v, %v", projects, err)
+	}
+}
+
+func TestEditMoveToProject(t *testing.T) {
+	store := treeStorage()
+	if _, err := store.CreateProject(context.Background(), "Work"); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	output := runApp(t, store, "6\n4\nj\nWork\n6\n1\nj\n1\n6\n5\nj\ninbox\nu\nq\n")
+
+	for _, want := range []string{"Todo moved to project Work.", "has subtasks: move its subtasks out first", "Info: todo is already there.", "Undid the project move of #4."} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q, got:\n%s", want, output)
+		}
+	}
+	if got := listTodos(t, store)[3]; got.ParentID != 1 || got.ProjectID != todo.InboxID {
+		t.Fatalf("expected the move undone, got %+v", got)
+	}
+}
+
+func TestDeleteWithSubtasks(t *testing.T) {
+	store := treeStorage()
+	output := runApp(t, store, "3\n2\nn\n3\n1\ny\nq\n")
+
+	if !strings.Contains(output, "Todo left as it was.") {
+		t.Fatalf("expected the first delete to be declined, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Todo and 3 subtask(s) moved to the trash.") {
+		t.Fatalf("expected cascade message, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 1 || todos[0].ID != 5 {
+		t.Fatalf("expected only todo 5 left, got %+v", todos)
+	}
+}
+
+// blockedStorage holds a done todo 1 and todo 3, which todo 2 waits on, and
+// todo 4, which waits on todo 2.
+func blockedStorage() *storage.MemoryStorage {
+	return storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "design", Completed: true, Version: 1},
+		todo.Todo{ID: 2, Title: "build", BlockedBy: []int{1, 3}, Version: 1},
+		todo.Todo{ID: 3, Title: "review", Version: 1},
+		todo.Todo{ID: 4, Title: "ship", BlockedBy: []int{2}, Version: 1},
+	)
+}
+
+func TestListBlocked(t *testing.T) {
+	output := runApp(t, blockedStorage(), "2\nq\n")
+
+	if !strings.Contains(output, "[ ] 2. build (blocked by #3)\n") || !strings.Contains(output, "[ ] 4. ship (blocked by #2)\n") {
+		t.Fatalf("expected open blockers only, got:\n%s", output)
+	}
+}
+
+func TestReady(t *testing.T) {
+	store := blockedStorage()
+	output := runApp(t, store, "19\n4\n3\n19\nq\n")
+
+	first, rest, _ := strings.Cut(output, "> Choose an option")
+	first, _, _ = strings.Cut(rest, "> Choose an option")
+	_, second, _ := strings.Cut(output, "Todo marked as completed.")
+	if !strings.Contains(first, "[ ] 3. review") || strings.Contains(first, "2. build") || strings.Contains(first, "4. ship") {
+		t.Fatalf("expected only todo 3 to be ready, got:\n%s", first)
+	}
+	if !strings.Contains(second, "[ ] 2. build") || strings.Contains(second, "4. ship") {
+		t.Fatalf("expected todo 2 to be ready once 3 is done, got:\n%s", second)
+	}
+}
+
+func TestReadyNothing(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "done", Completed: true, Version: 1})
+	output := runApp(t, store, "19\nq\n")
+
+	if !strings.Contains(output, "No todos are ready to work on.") {
+		t.Fatalf("expected nothing ready, got:\n%s", output)
+	}
+}
+
+func TestCompleteBlocked(t *testing.T) {
+	output := runApp(t, blockedStorage(), "4\n2\nq\n")
+
+	if !strings.Contains(output, "Error: todo 2: blocked by open todos: #3") {
+		t.Fatalf("expected blocked error, got:\n%s", output)
+	}
+}
+
+func TestEditBlockers(t *testing.T) {
+	store := blockedStorage()
+	output := runApp(t, store, "6\n4\nk\n3 -2 -1\n6\n3\nk\n4\nq\n")
+
+	for _, want := range []string{
+		"Now blocked by #3.",
+		"No longer blocked by #2.",
+		"Info: todo 4 is not blocked by #1.",
+		"Error: todo 3: dependency would create a cycle",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if got := listTodos(t, store)[3]; !slices.Equal(got.BlockedBy, []int{3}) {
+		t.Fatalf("expected todo 4 to wait on 3 only, got %+v", got)
+	}
+}
+
+func TestRecurringTodo(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	// Add a todo due 2099-03-02 that repeats every 2 weeks, then complete it.
+	output := runApp(t, store, "1\nwater plants\n\n\n2099-03-02\n\nevery 2 weeks\n4\n1\nq\n")
+
+	for _, want := range []string{
+		"water plants (due 2099-03-02) (every 2 weeks)",
+		"Next occurrence added as #2.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	todos := listTodos(t, store)
+	if len(todos) != 2 || todos[0].NextID != 2 {
+		t.Fatalf("expected todo 1 to point at its next occurrence, got %+v", todos)
+	}
+	if next := todos[1]; next.Completed || next.DueDate != "2099-03-16" || next.Recurrence != "FREQ=WEEKLY;INTERVAL=2" {
+		t.Fatalf("unexpected next occurrence: %+v", next)
+	}
+}
+
+func TestAddTodoInvalidRepeat(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\n\n\n2099-03-02\n\nnow and then\nq\n")
+
+	if !strings.Contains(output, "Error: invalid recurrence rule") {
+		t.Fatalf("expected recurrence error, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 0 {
+		t.Fatalf("expected no todo added, got %+v", todos)
+	}
+}
+
+func TestEditRepeat(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "rent", DueDate: "2099-03-01", Version: 1})
+	output := runApp(t, store, "6\n1\nr\nmonthly on the 1st\n6\n1\nr\nevery month on the 1st\nq\n")
+
+	for _, want := range []string{
+		"Repeat schedule updated successfully.",
+		"rent (due 2099-03-01) (every month on the 1st)",
+		"Info: repeat schedule is already the same.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if got := listTodos(t, store)[0]; got.Recurrence != "FREQ=MONTHLY;BYMONTHDAY=1" {
+		t.Fatalf("unexpected recurrence: %q", got.Recurrence)
+	}
+
+	runApp(t, store, "6\n1\nr\n\nq\n")
+	if got := listTodos(t, store)[0]; got.Recurrence != "" {
+		t.Fatalf("expected recurrence cleared, got %q", got.Recurrence)
+	}
+}
+
+// trashedStorage holds a live todo 1 and todos 2 and 3 in the trash.
+func trashedStorage() *storage.MemoryStorage {
+	deletedAt := testNow.Add(-time.Hour)
+	return storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "live", Version: 1},
+		todo.Todo{ID: 2, Title: "binned", Version: 2, DeletedAt: deletedAt},
+		todo.Todo{ID: 3, Title: "also binned", Version: 2, DeletedAt: deletedAt},
+	)
+}
+
+func TestTrashBrowse(t *testing.T) {
+	output := runApp(t, trashedStorage(), "14\nb\nq\n")
+
+	if !strings.Contains(output, "[ ] 2. binned (deleted 2026-03-04 11:00)") {
+		t.Fatalf("expected trashed todo in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "1. live") {
+		t.Fatalf("expected live todos to be left out, got:\n%s", output)
+	}
+}
+
+func TestTrashRestore(t *testing.T) {
+	store := trashedStorage()
+	output := runApp(t, store, "14\nr\n2\nq\n")
+
+	if !strings.Contains(output, "Todo restored.") || !strings.Contains(output, "[ ] 2. binned\n") {
+		t.Fatalf("expected restore message and restored todo in output, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 2 || todos[1].ID != 2 {
+		t.Fatalf("expected todo 2 to be live again, got %+v", todos)
+	}
+}
+
+func TestTrashRestoreOnlyOffersTrash(t *testing.T) {
+	output := runApp(t, trashedStorage(), "14\nr\n1\nq\n")
+
+	if !strings.Contains(output, "Error: todo with id 1: not found") {
+		t.Fatalf("expected not found error, got:\n%s", output)
+	}
+}
+
+func TestTrashEmpty(t *testing.T) {
+	store := trashedStorage()
+	output := runApp(t, store, "14\ne\nn\n14\ne\ny\nq\n")
+
+	if !strings.Contains(output, "Trash left as it was.") {
+		t.Fatalf("expected the first empty to be declined, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Trash emptied: 2 todo(s) permanently deleted.") {
+		t.Fatalf("expected purge message, got:\n%s", output)
+	}
+	trashed, _, err := store.List(context.Background(), todo.ListOptions{Trashed: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(trashed) != 0 || len(listTodos(t, store)) != 1 {
+		t.Fatalf("expected an empty trash and the live todo kept, got %+v", trashed)
+	}
+}
+
 func TestMarkCompleted(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nmy task\n\n4\n1\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nmy task\n\n\n\n4\n1\nq\n")
+	todos := listTodos(t, store)
 
-	if !store.todos[0].Completed {
+	if !todos[0].Completed {
 		t.Fatal("expected todo to be completed")
 	}
 	if !strings.Contains(output, "Todo marked as completed.") {
@@ -211,12 +775,11 @@ func TestMarkCompleted(t *testing.T) {
 }
 
 func TestMarkIncomplete(t *testing.T) {
-	store := newMockStorage()
-	store.todos = append(store.todos, todo.Todo{ID: 1, Title: "done task", Completed: true})
-	store.nextID = 2
-	output := runApp(t, store, "5\n1\n7\n")
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "done task", Completed: true})
+	output := runApp(t, store, "5\n1\nq\n")
+	todos := listTodos(t, store)
 
-	if store.todos[0].Completed {
+	if todos[0].Completed {
 		t.Fatal("expected todo to be incomplete")
 	}
 	if !strings.Contains(output, "Todo marked as incomplete.") {
@@ -225,10 +788,8 @@ func TestMarkIncomplete(t *testing.T) {
 }
 
 func TestAlreadyCompleted(t *testing.T) {
-	store := newMockStorage()
-	store.todos = append(store.todos, todo.Todo{ID: 1, Title: "done", Completed: true})
-	store.nextID = 2
-	output := runApp(t, store, "4\n1\n7\n")
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "done", Completed: true})
+	output := runApp(t, store, "4\n1\nq\n")
 
 	if !strings.Contains(output, "Info: todo 1 is already completed.") {
 		t.Fatalf("expected info message, got:\n%s", output)
@@ -236,50 +797,150 @@ func TestAlreadyCompleted(t *testing.T) {
 }
 
 func TestEditTitle(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\noriginal\n\n6\n1\nt\nupdated\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\noriginal\n\n\n\n6\n1\nt\nupdated\nq\n")
+	todos := listTodos(t, store)
 
-	if store.todos[0].Title != "updated" {
-		t.Fatalf("expected 'updated', got %q", store.todos[0].Title)
+	if todos[0].Title != "updated" {
+		t.Fatalf("expected 'updated', got %q", todos[0].Title)
 	}
-	if !strings.Contains(output, "Title updated successfully.") {
-		t.Fatalf("expected title update message in output, got:\n%s", output)
+	if !strings.Contains(output, "Title updated successfully.") || !strings.Contains(output, "[ ] 1. updated") {
+		t.Fatalf("expected title update message and updated todo in output, got:\n%s", output)
 	}
 }
 
 func TestEditBoth(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nold title\nold desc\n6\n1\nb\nnew title\nnew desc\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nold title\nold desc\n\n\n6\n1\nb\nnew title\nnew desc\nq\n")
+	todos := listTodos(t, store)
 
-	if store.todos[0].Title != "new title" {
-		t.Fatalf("expected title 'new title', got %q", store.todos[0].Title)
+	if todos[0].Title != "new title" {
+		t.Fatalf("expected title 'new title', got %q", todos[0].Title)
 	}
-	if store.todos[0].Description != "new desc" {
-		t.Fatalf("expected description 'new desc', got %q", store.todos[0].Description)
+	if todos[0].Description != "new desc" {
+		t.Fatalf("expected description 'new desc', got %q", todos[0].Description)
 	}
-	if !strings.Contains(output, "Title updated successfully.") {
-		t.Fatalf("expected title update message in output, got:\n%s", output)
+	if !strings.Contains(output, "Title and description updated successfully.") || !strings.Contains(output, "[ ] 1. new title - new desc") {
+		t.Fatalf("expected update message and updated todo in output, got:\n%s", output)
 	}
-	if !strings.Contains(output, "Description updated successfully.") {
-		t.Fatalf("expected description update message in output, got:\n%s", output)
+}
+
+func TestEditBothIsAllOrNothing(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "old title", Description: "old desc"})
+	output := runApp(t, store, "6\n1\nb\nnew title\n"+strings.Repeat("x", todo.MaxDescriptionLength+1)+"\nq\n")
+	todos := listTodos(t, store)
+
+	if !strings.Contains(output, "Error: description exceeds maximum length") {
+		t.Fatalf("expected description error, got:\n%s", output)
+	}
+	if todos[0].Title != "old title" {
+		t.Fatalf("expected title to stay 'old title', got %q", todos[0].Title)
+	}
+}
+
+func TestEditBothUnchanged(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "same", Description: "same desc"})
+	output := runApp(t, store, "6\n1\nb\nsame\nsame desc\nq\n")
+
+	if !strings.Contains(output, "Info: title and description are already the same.") {
+		t.Fatalf("expected info message, got:\n%s", output)
+	}
+}
+
+// interferingStorage changes a todo's description elsewhere just before
+// every title edit, so the edit is made at a stale version.
+type interferingStorage struct {
+	*storage.MemoryStorage
+}
+
+func (s interferingStorage) EditTitle(ctx context.Context, id int, title string) (todo.Todo, error) {
+	if _, err := s.MemoryStorage.EditDescription(context.Background(), id, "changed elsewhere"); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.MemoryStorage.EditTitle(ctx, id, title)
+}
+
+func TestEditConflict(t *testing.T) {
+	store := interferingStorage{storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "mine", Version: 1})}
+	output := runApp(t, store, "6\n1\nt\ntheirs\ny\nq\n")
+	todos := listTodos(t, store)
+
+	if todos[0].Title != "mine" {
+		t.Fatalf("expected title to stay 'mine', got %q", todos[0].Title)
+	}
+	if !strings.Contains(output, "Todo 1 was changed by someone else, so your change was not applied.") {
+		t.Fatalf("expected conflict message, got:\n%s", output)
+	}
+	if !strings.Contains(output, "[ ] 1. mine - changed elsewhere") {
+		t.Fatalf("expected the reloaded todo in output, got:\n%s", output)
 	}
 }
 
 func TestEditDescription(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nmy task\nold desc\n6\n1\nd\nnew desc\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nmy task\nold desc\n\n\n6\n1\nd\nnew desc\nq\n")
+	todos := listTodos(t, store)
 
-	if store.todos[0].Description != "new desc" {
-		t.Fatalf("expected 'new desc', got %q", store.todos[0].Description)
+	if todos[0].Description != "new desc" {
+		t.Fatalf("expected 'new desc', got %q", todos[0].Description)
 	}
 	if !strings.Contains(output, "Description updated successfully.") {
 		t.Fatalf("expected description update message in output, got:\n%s", output)
 	}
 }
 
+func TestEditDue(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\n\n\n2026-03-05\n\n\n6\n1\nu\n\nq\n")
+	todos := listTodos(t, store)
+
+	if todos[0].DueDate != "" {
+		t.Fatalf("expected due date cleared, got %q", todos[0].DueDate)
+	}
+	if !strings.Contains(output, "Due date updated successfully.") {
+		t.Fatalf("expected due update message in output, got:\n%s", output)
+	}
+}
+
+func TestEditPriority(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\n\nlow\n\n6\n1\np\nhigh\n6\n1\np\nhigh\nq\n")
+	todos := listTodos(t, store)
+
+	if todos[0].Priority != todo.PriorityHigh {
+		t.Fatalf("expected high priority, got %v", todos[0].Priority)
+	}
+	if !strings.Contains(output, "Priority updated successfully.") {
+		t.Fatalf("expected priority update message in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Info: priority is already the same.") {
+		t.Fatalf("expected unchanged info message in output, got:\n%s", output)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "task", Tags: []string{"home"}})
+	output := runApp(t, store, "6\n1\ng\nWork -home -misc work\nq\n")
+	todos := listTodos(t, store)
+
+	if len(todos[0].Tags) != 1 || todos[0].Tags[0] != "work" {
+		t.Fatalf("expected tags [work], got %v", todos[0].Tags)
+	}
+	for _, want := range []string{
+		`Tag "Work" added.`,
+		`Tag "home" removed.`,
+		`Info: todo 1 has no tag "misc".`,
+		`Info: todo 1 already has tag "work".`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestEditTitleUnchanged(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nsame\n\n6\n1\nt\nsame\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nsame\n\n\n\n6\n1\nt\nsame\nq\n")
 
 	if !strings.Contains(output, "Info: title is already the same.") {
 		t.Fatalf("expected info message, got:\n%s", output)
@@ -287,8 +948,8 @@ func TestEditTitleUnchanged(t *testing.T) {
 }
 
 func TestEditDescriptionUnchanged(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\ntask\nsame desc\n6\n1\nd\nsame desc\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\nsame desc\n\n\n6\n1\nd\nsame desc\nq\n")
 
 	if !strings.Contains(output, "Info: description is already the same.") {
 		t.Fatalf("expected info message, got:\n%s", output)
@@ -296,8 +957,8 @@ func TestEditDescriptionUnchanged(t *testing.T) {
 }
 
 func TestEditBothStopsOnTitleError(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\nold title\nold desc\n6\n1\nb\n\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nold title\nold desc\n\n\n6\n1\nb\n\nq\n")
 
 	if !strings.Contains(output, "Error: title cannot be empty") {
 		t.Fatalf("expected title error, got:\n%s", output)
@@ -308,26 +969,26 @@ func TestEditBothStopsOnTitleError(t *testing.T) {
 }
 
 func TestEditInvalidFieldChoice(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "1\ntask\n\n6\n1\nx\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\n\n\n\n6\n1\nx\nq\n")
 
-	if !strings.Contains(output, "Error: invalid choice") || !strings.Contains(output, "enter 't', 'd', or 'b'") {
+	if !strings.Contains(output, "Error: invalid choice") || !strings.Contains(output, "enter 't', 'd', 'u', 'p', 'g', 'k', 'r', 'm', 'j', or 'b'") {
 		t.Fatalf("expected invalid choice error, got:\n%s", output)
 	}
 }
 
 func TestInvalidMenuChoice(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "99\nabc\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "99\nabc\nq\n")
 
-	if !strings.Contains(output, "Error: please enter a number between 0 and 7.") {
+	if !strings.Contains(output, "Error: please enter a number between 0 and 20, or q to exit.") {
 		t.Fatalf("expected invalid choice error in output, got:\n%s", output)
 	}
 }
 
 func TestDeleteFromEmptyList(t *testing.T) {
-	store := newMockStorage()
-	output := runApp(t, store, "3\n7\n")
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "3\nq\n")
 
 	if !strings.Contains(output, "No todos found.") {
 		t.Fatalf("expected 'No todos found.' in output, got:\n%s", output)
@@ -338,14 +999,58 @@ func TestDeleteFromEmptyList(t *testing.T) {
 }
 
 func TestContextCancellation(t *testing.T) {
-	store := newMockStorage()
+	store := storage.NewMemoryStorage()
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
 	var buf bytes.Buffer
-	scanner := bufio.NewScanner(strings.NewReader("7\n"))
+	scanner := bufio.NewScanner(strings.NewReader("q\n"))
 	app := New(store, scanner, &buf)
 	if err := app.Run(ctx); err != nil {
 		t.Fatalf("Run: %v", err)
 	}
 }
+
+func TestLiveList(t *testing.T) {
+	store := &watchingStorage{
+		MemoryStorage: storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "buy milk"}),
+		events:        make(chan todo.Event, 2),
+	}
+	store.events <- todo.Event{Type: todo.EventUpdated, ID: 1}
+	close(store.events)
+	output := runApp(t, store, "13\nq\n")
+
+	if got := strings.Count(output, "===== Live list (press Enter to stop) ====="); got != 2 {
+		t.Fatalf("expected the list drawn twice, got %d times:\n%s", got, output)
+	}
+	if !strings.Contains(output, "Todo 1 was updated.") {
+		t.Fatalf("expected the change in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Live updates ended.") {
+		t.Fatalf("expected end of stream message, got:\n%s", output)
+	}
+}
+
+func TestLiveListStopsOnEnter(t *testing.T) {
+	store := &watchingStorage{
+		MemoryStorage: storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "buy milk"}),
+		events:        make(chan todo.Event),
+	}
+	output := runApp(t, store, "13\n\nq\n")
+
+	if !strings.Contains(output, "1. buy milk") {
+		t.Fatalf("expected the list in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "Error:") {
+		t.Fatalf("expected no error, got:\n%s", output)
+	}
+}
+
+func TestLiveListUnsupported(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "13\nq\n")
+
+	if !strings.Contains(output, "Error: live updates are not supported by this storage") {
+		t.Fatalf("expected unsupported error in output, got:\n%s", output)
+	}
+}
@@ -0,0 +1,675 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// Exit statuses returned by Exec. They group the domain errors the same way
+// the server groups them into gRPC codes, so scripts can tell a missing todo
+// from a rejected input or a no-op.
+const (
+	ExitOK           = 0
+	ExitFailure      = 1 // unexpected or transport error
+	ExitUsage        = 2 // unknown subcommand, bad flag or missing argument
+	ExitNotFound     = 3 // todo.ErrNotFound or todo.ErrProjectNotFound
+	ExitInvalid      = 4 // input rejected by validation
+	ExitPrecondition = 5 // todo already in the requested state, full, or blocked by its subtasks or parent
+	ExitConflict     = 6 // todo.ErrVersionConflict: changed since -if-version
+	ExitAuth         = 7 // todo.ErrUnauthenticated: missing, unknown or revoked token
+)
+
+// usageError reports a malformed command line.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// errReported marks a flag parsing error the flag package has already
+// printed together with the command's usage.
+var errReported = errors.New("reported")
+
+type command struct {
+	name     string
+	synopsis string
+	summary  string
+	run      func(a *App, ctx context.Context, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"add", "TITLE [-d DESCRIPTION] [-p PRIORITY] [-due YYYY-MM-DD [-at HH:MM]] [-repeat SCHEDULE] [-t TAG]... [-parent ID] [-project PROJECT]", "Add a todo", (*App).cmdAdd},
+	{"list", "[-json] [-trash] [-ready] [-filter EXPR] [-t TAG]... [-sort created|updated|completed] [-project PROJECT]", "List todos", (*App).cmdList},
+	{"done", "ID [-if-version N]", "Mark a todo as completed", func(a *App, ctx context.Context, fs *flag.FlagSet, args []string) error {
+		return a.cmdSetCompleted(ctx, fs, args, true)
+	}},
+	{"undone", "ID [-if-version N]", "Mark a todo as incomplete", func(a *App, ctx context.Context, fs *flag.FlagSet, args []string) error {
+		return a.cmdSetCompleted(ctx, fs, args, false)
+	}},
+	{"rm", "ID [-r] [-if-version N]", "Move a todo to the trash", (*App).cmdDelete},
+	{"restore", "ID [-if-version N]", "Restore a todo from the trash", (*App).cmdRestore},
+	{"purge", "", "Permanently delete every todo in the trash", (*App).cmdPurge},
+	{"block", "ID BLOCKER_ID [-if-version N]", "Make a todo wait on another", func(a *App, ctx context.Context, fs *flag.FlagSet, args []string) error {
+		return a.cmdDependency(ctx, fs, args, true)
+	}},
+	{"unblock", "ID BLOCKER_ID [-if-version N]", "Stop a todo waiting on another", func(a *App, ctx context.Context, fs *flag.FlagSet, args []string) error {
+		return a.cmdDependency(ctx, fs, args, false)
+	}},
+	{"edit", "ID [-title TITLE] [-desc DESCRIPTION] [-p PRIORITY] [-due YYYY-MM-DD [-at HH:MM]] [-repeat SCHEDULE] [-parent ID] [-project PROJECT] [-if-version N]", "Edit a todo", (*App).cmdEdit},
+	{"project", "[-json] list | add NAME | rename PROJECT NAME | archive PROJECT | unarchive PROJECT | rm PROJECT", "Manage projects", (*App).cmdProject},
+}
+
+// Exec runs the subcommand named in args[1], as in `todos add "Buy milk"`,
+// against store and returns the process exit status. args[0] is the program
+// name. Results go to stdout, errors and usage to stderr.
+func Exec(ctx context.Context, store todo.Storage, args []string, stdout, stderr io.Writer) int {
+	return New(store, nil, stdout).exec(ctx, args, stderr)
+}
+
+func (a *App) exec(ctx context.Context, args []string, stderr io.Writer) int {
+	prog := filepath.Base(args[0])
+	if len(args) < 2 {
+		printUsage(stderr, prog)
+		return ExitUsage
+	}
+	name := args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(a.out, prog)
+		return ExitOK
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "Error: unknown command %q\n", name)
+		printUsage(stderr, prog)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet(prog+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s %s %s\n", prog, cmd.name, cmd.synopsis)
+		fs.PrintDefaults()
+	}
+	err := cmd.run(a, ctx, fs, args[2:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errReported):
+		return ExitUsage
+	}
+	fmt.Fprintf(stderr, "Error: %s\n", errorText(err))
+	code := exitCode(err)
+	if code == ExitUsage {
+		fmt.Fprintf(stderr, "Usage: %s %s %s\n", prog, cmd.name, cmd.synopsis)
+	}
+	return code
+}
+
+func printUsage(w io.Writer, prog string) {
+	fmt.Fprintf(w, "Usage: %s [COMMAND]\n\nWithout a command, %s starts the interactive menu.\n\nCommands:\n", prog, prog)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-7s %s\n", c.name, c.summary)
+	}
+	// The client handles these before Exec: they act on its saved token,
+	// not on a store.
+	fmt.Fprintf(w, "  %-7s %s\n", "login", "Check an API token and save it for later runs")
+	fmt.Fprintf(w, "  %-7s %s\n", "logout", "Forget the saved API token")
+	fmt.Fprintf(w, "\nRun '%s COMMAND -h' for the flags of a command.\n", prog)
+}
+
+// exitCode maps err to an exit status.
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.Is(err, todo.ErrNotFound),
+		errors.Is(err, todo.ErrProjectNotFound):
+		return ExitNotFound
+	case errors.Is(err, todo.ErrVersionConflict):
+		return ExitConflict
+	case errors.Is(err, todo.ErrUnauthenticated):
+		return ExitAuth
+	case errors.Is(err, todo.ErrAlreadyCompleted),
+		errors.Is(err, todo.ErrAlreadyIncomplete),
+		errors.Is(err, todo.ErrTitleUnchanged),
+		errors.Is(err, todo.ErrDescriptionUnchanged),
+		errors.Is(err, todo.ErrDueUnchanged),
+		errors.Is(err, todo.ErrPriorityUnchanged),
+		errors.Is(err, todo.ErrTodoUnchanged),
+		errors.Is(err, todo.ErrNotInTrash),
+		errors.Is(err, todo.ErrParentNotFound),
+		errors.Is(err, todo.ErrParentCycle),
+		errors.Is(err, todo.ErrHasSubtasks),
+		errors.Is(err, todo.ErrBlocked),
+		errors.Is(err, todo.ErrBlockerNotFound),
+		errors.Is(err, todo.ErrDependencyCycle),
+		errors.Is(err, todo.ErrDependencyPresent),
+		errors.Is(err, todo.ErrDependencyNotPresent),
+		errors.Is(err, todo.ErrTagAlreadyPresent),
+		errors.Is(err, todo.ErrTagNotPresent),
+		errors.Is(err, todo.ErrTooManyTags),
+		errors.Is(err, todo.ErrProjectNameTaken),
+		errors.Is(err, todo.ErrProjectArchived),
+		errors.Is(err, todo.ErrProjectNotEmpty),
+		errors.Is(err, todo.ErrProjectUnchanged):
+		return ExitPrecondition
+	case errors.Is(err, todo.ErrInvalidID),
+		errors.Is(err, todo.ErrEmptyTitle),
+		errors.Is(err, todo.ErrTitleTooLong),
+		errors.Is(err, todo.ErrDescriptionTooLong),
+		errors.Is(err, todo.ErrInvalidDueDate),
+		errors.Is(err, todo.ErrInvalidDueTime),
+		errors.Is(err, todo.ErrDueTimeWithoutDate),
+		errors.Is(err, todo.ErrInvalidPriority),
+		errors.Is(err, todo.ErrInvalidTag),
+		errors.Is(err, todo.ErrInvalidRecurrence),
+		errors.Is(err, todo.ErrInvalidProjectName),
+		errors.Is(err, todo.ErrInvalidFilter),
+		errors.Is(err, todo.ErrInvalidPageSize),
+		errors.Is(err, todo.ErrInvalidPageToken),
+		errors.Is(err, todo.ErrInvalidUpdateMask):
+		return ExitInvalid
+	default:
+		return ExitFailure
+	}
+}
+
+// reportedError is an error that carries the todo and the limits a server
+// reported with it, like *grpcclient.Error.
+type reportedError interface {
+	ID() (int, bool)
+	Limit(key string) (int, bool)
+}
+
+// errorText returns err's message followed by the todo and the limit the
+// server reported with it, if it reported either.
+func errorText(err error) string {
+	var reported reportedError
+	if !errors.As(err, &reported) {
+		return err.Error()
+	}
+	var details []string
+	if id, ok := reported.ID(); ok {
+		details = append(details, fmt.Sprintf("todo #%d", id))
+	}
+	if key := limitKey(err); key != "" {
+		if limit, ok := reported.Limit(key); ok {
+			details = append(details, fmt.Sprintf("limit %d", limit))
+		}
+	}
+	if details == nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v [%s]", err, strings.Join(details, ", "))
+}
+
+// limitKey returns the ErrorInfo metadata key a server reports the limit err
+// is about under, or "" if err is about none.
+func limitKey(err error) string {
+	switch {
+	case errors.Is(err, todo.ErrTooManyTags):
+		return "max_tags"
+	case errors.Is(err, todo.ErrTitleTooLong):
+		return "max_title_length"
+	case errors.Is(err, todo.ErrDescriptionTooLong):
+		return "max_description_length"
+	case errors.Is(err, todo.ErrInvalidTag):
+		return "max_tag_length"
+	case errors.Is(err, todo.ErrInvalidProjectName):
+		return "max_project_name_length"
+	case errors.Is(err, todo.ErrInvalidFilter):
+		return "max_filter_length"
+	case errors.Is(err, todo.ErrInvalidPageSize):
+		return "max_page_size"
+	default:
+		return ""
+	}
+}
+
+// parseArgs parses flags and positional arguments in any order, so that
+// `add "Buy milk" -d "2 litres"` works as well as `add -d "2 litres" "Buy milk"`.
+// Everything after "--" is positional. It returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errReported
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// parseID parses the single positional argument of a command taking an ID.
+func parseID(fs *flag.FlagSet, args []string) (int, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(positional) != 1 {
+		return 0, usageError("expected exactly one todo ID")
+	}
+	id, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return 0, usageError(fmt.Sprintf("invalid ID %q", positional[0]))
+	}
+	return id, nil
+}
+
+// parseIDPair parses the two positional arguments of a command taking a
+// todo ID and a blocker ID.
+func parseIDPair(fs *flag.FlagSet, args []string) (int, int, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(positional) != 2 {
+		return 0, 0, usageError("expected a todo ID and a blocker ID")
+	}
+	var ids [2]int
+	for i, arg := range positional {
+		if ids[i], err = strconv.Atoi(arg); err != nil {
+			return 0, 0, usageError(fmt.Sprintf("invalid ID %q", arg))
+		}
+	}
+	return ids[0], ids[1], nil
+}
+
+// versionFlag registers -if-version, which makes a change conditional on
+// the todo's version as printed by list -json.
+func versionFlag(fs *flag.FlagSet) *int {
+	return fs.Int("if-version", 0, "only change the todo if it is still at this version (see list -json)")
+}
+
+// projectFlag registers -project, which names a project by name or ID.
+func projectFlag(fs *flag.FlagSet, usage string) *string {
+	return fs.String("project", "", usage+", by name or ID (default the Inbox)")
+}
+
+// tagsFlag collects a repeatable -t flag; each value may hold several
+// comma- or space-separated tags.
+type tagsFlag []string
+
+func (f *tagsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *tagsFlag) Set(value string) error {
+	*f = append(*f, splitTags(value)...)
+	return nil
+}
+
+func (a *App) cmdAdd(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	var (
+		draft    todo.Draft
+		priority string
+		repeat   string
+		tags     tagsFlag
+	)
+	fs.StringVar(&draft.Description, "d", "", "description")
+	fs.StringVar(&priority, "p", "", "priority: none, low, medium, high or urgent")
+	fs.StringVar(&draft.DueDate, "due", "", "due date (YYYY-MM-DD)")
+	fs.StringVar(&draft.DueTime, "at", "", "due time (HH:MM), requires -due")
+	fs.StringVar(&repeat, "repeat", "", "repeat schedule, e.g. \"every 2 weeks on mon\" or an RRULE")
+	fs.Var(&tags, "t", "tag; repeat or separate with commas for several")
+	fs.IntVar(&draft.ParentID, "parent", 0, "add the todo as a subtask of the todo with this ID")
+	projectName := projectFlag(fs, "add the todo to this project")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError("expected exactly one title; quote titles containing spaces")
+	}
+	if draft.Priority, err = todo.ParsePriority(priority); err != nil {
+		return err
+	}
+	if draft.Recurrence, err = parseRepeat(repeat); err != nil {
+		return err
+	}
+	if draft.ProjectID, err = a.projectID(ctx, *projectName); err != nil {
+		return err
+	}
+	draft.Title = positional[0]
+	draft.Tags = tags
+	added, err := a.store.Add(ctx, draft)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Added #%d\n", added.ID)
+	a.printTodos([]todo.Todo{added})
+	return nil
+}
+
+func (a *App) cmdList(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	var (
+		opts   todo.ListOptions
+		tags   tagsFlag
+		asJSON bool
+		ready  bool
+		sortBy string
+	)
+	fs.BoolVar(&asJSON, "json", false, "print todos as a JSON array")
+	fs.StringVar(&sortBy, "sort", "", "order by the time todos were created, updated or completed, latest first")
+	fs.BoolVar(&opts.Trashed, "trash", false, "list the todos in the trash instead")
+	fs.BoolVar(&ready, "ready", false, "only incomplete todos whose blockers are all completed")
+	fs.StringVar(&opts.Filter, "filter", "", "filter expression, as in the interactive search")
+	fs.Var(&tags, "t", "only todos carrying this tag; repeat for several")
+	projectName := projectFlag(fs, "list the todos of this project")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageError(fmt.Sprintf("unexpected argument %q", positional[0]))
+	}
+	at, ok := timeFields[sortBy]
+	if sortBy != "" && !ok {
+		return usageError(fmt.Sprintf("invalid -sort %q; use created, updated or completed", sortBy))
+	}
+	if ready && opts.Trashed {
+		return usageError("-ready cannot be combined with -trash")
+	}
+	if opts.ProjectID, err = a.projectID(ctx, *projectName); err != nil {
+		return err
+	}
+	opts.Tags = tags
+	todos, err := a.listAll(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if ready {
+		// Blockers may fall outside the filter or the project, so
+		// readiness is judged against every live todo.
+		all, err := a.listEveryProject(ctx)
+		if err != nil {
+			return err
+		}
+		todos = readyTodos(todos, all)
+	}
+	if at != nil {
+		sortByTime(todos, at)
+	}
+	if !asJSON {
+		a.printTodos(todos)
+		return nil
+	}
+	if todos == nil {
+		todos = []todo.Todo{}
+	}
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(todos)
+}
+
+func (a *App) cmdSetCompleted(ctx context.Context, fs *flag.FlagSet, args []string, completed bool) error {
+	version := versionFlag(fs)
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	updated, err := a.store.SetCompleted(todo.WithExpectedVersion(ctx, *version), id, completed)
+	if err != nil {
+		return err
+	}
+	action := "completed"
+	if !completed {
+		action = "incomplete"
+	}
+	fmt.Fprintf(a.out, "Todo %d marked as %s.\n", id, action)
+	a.printTodos([]todo.Todo{updated})
+	if updated.Recurred {
+		fmt.Fprintf(a.out, "Next occurrence is #%d.\n", updated.NextID)
+	}
+	return nil
+}
+
+func (a *App) cmdDependency(ctx context.Context, fs *flag.FlagSet, args []string, add bool) error {
+	version := versionFlag(fs)
+	id, blockerID, err := parseIDPair(fs, args)
+	if err != nil {
+		return err
+	}
+	c := dependencyChange{blockerID: blockerID, add: add}
+	updated, err := a.changeDependency(todo.WithExpectedVersion(ctx, *version), id, c)
+	if err != nil {
+		return err
+	}
+	if add {
+		fmt.Fprintf(a.out, "Todo %d now waits on #%d.\n", id, blockerID)
+	} else {
+		fmt.Fprintf(a.out, "Todo %d no longer waits on #%d.\n", id, blockerID)
+	}
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+func (a *App) cmdDelete(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	recursive := fs.Bool("r", false, "move the todo's subtasks to the trash too")
+	version := versionFlag(fs)
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	ctx = todo.WithExpectedVersion(ctx, *version)
+	if !*recursive {
+		if err := a.store.Delete(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Todo %d moved to the trash.\n", id)
+		return nil
+	}
+	n, err := a.store.DeleteTree(ctx, id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Todo %d and %d subtask(s) moved to the trash.\n", id, n-1)
+	return nil
+}
+
+func (a *App) cmdRestore(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	version := versionFlag(fs)
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	restored, err := a.store.Restore(todo.WithExpectedVersion(ctx, *version), id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Todo %d restored.\n", id)
+	a.printTodos([]todo.Todo{restored})
+	return nil
+}
+
+func (a *App) cmdPurge(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageError(fmt.Sprintf("unexpected argument %q", positional[0]))
+	}
+	n, err := a.store.PurgeTrash(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%d todo(s) permanently deleted.\n", n)
+	return nil
+}
+
+// cmdEdit applies every field given on the command line in a single
+// update, so either all of them change or none do. The edit fails only when
+// every field already has the given value.
+func (a *App) cmdEdit(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	var (
+		title, desc, priorityName, dueDate, dueTime, repeat string
+		parentID                                            int
+	)
+	fs.StringVar(&title, "title", "", "new title")
+	fs.StringVar(&desc, "desc", "", "new description")
+	fs.StringVar(&priorityName, "p", "", "new priority: none, low, medium, high or urgent")
+	fs.StringVar(&dueDate, "due", "", "new due date (YYYY-MM-DD), empty to clear")
+	fs.StringVar(&dueTime, "at", "", "new due time (HH:MM), requires -due")
+	fs.StringVar(&repeat, "repeat", "", "new repeat schedule, e.g. \"every 2 weeks on mon\", empty to stop repeating")
+	fs.IntVar(&parentID, "parent", 0, "make the todo a subtask of the todo with this ID, 0 for top level")
+	projectName := projectFlag(fs, "move the todo to this project, at the top level unless -parent is given")
+	version := versionFlag(fs)
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["at"] && !set["due"] {
+		return usageError("-at requires -due")
+	}
+	priority, err := todo.ParsePriority(priorityName)
+	if err != nil {
+		return err
+	}
+	rule, err := parseRepeat(repeat)
+	if err != nil {
+		return err
+	}
+	projectID, err := a.projectID(ctx, *projectName)
+	if err != nil {
+		return err
+	}
+
+	var mask []string
+	for _, e := range []struct {
+		flag   string
+		fields []string
+	}{
+		{"title", []string{todo.FieldTitle}},
+		{"desc", []string{todo.FieldDescription}},
+		{"due", []string{todo.FieldDueDate, todo.FieldDueTime}},
+		{"p", []string{todo.FieldPriority}},
+		{"repeat", []string{todo.FieldRecurrence}},
+		{"parent", []string{todo.FieldParentID}},
+		{"project", []string{todo.FieldProjectID}},
+	} {
+		if set[e.flag] {
+			mask = append(mask, e.fields...)
+		}
+	}
+	// A subtask stays in its parent's project, so a move to another
+	// project takes the todo to the top level there unless told otherwise.
+	if set["project"] && !set["parent"] {
+		mask = append(mask, todo.FieldParentID)
+	}
+	if len(mask) == 0 {
+		return usageError("nothing to edit; give at least one of -title, -desc, -p, -due, -repeat, -parent or -project")
+	}
+	patch := todo.Todo{ID: id, Title: title, Description: desc, DueDate: dueDate, DueTime: dueTime, Priority: priority, ParentID: parentID, ProjectID: projectID, Recurrence: rule}
+	updated, err := a.store.Update(todo.WithExpectedVersion(ctx, *version), patch, mask)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Todo %d updated.\n", id)
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
+
+// projectID returns the ID of the project named by a -project flag, or the
+// Inbox if the flag was not given.
+func (a *App) projectID(ctx context.Context, name string) (int, error) {
+	if name == "" {
+		return todo.InboxID, nil
+	}
+	p, err := a.findProject(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+// cmdProject runs the action named by its first argument on a project named
+// by the second, by name or ID.
+func (a *App) cmdProject(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	asJSON := fs.Bool("json", false, "print projects as a JSON array, for list")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usageError("expected an action: list, add, rename, archive, unarchive or rm")
+	}
+	action, operands := positional[0], positional[1:]
+	want := map[string]int{"list": 0, "add": 1, "rename": 2, "archive": 1, "unarchive": 1, "rm": 1}
+	n, ok := want[action]
+	if !ok {
+		return usageError(fmt.Sprintf("unknown action %q", action))
+	}
+	if len(operands) != n {
+		return usageError(fmt.Sprintf("%s expects %d argument(s), got %d", action, n, len(operands)))
+	}
+
+	switch action {
+	case "list":
+		projects, err := a.store.ListProjects(ctx)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			enc := json.NewEncoder(a.out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(projects)
+		}
+		a.printProjects(projects)
+		return nil
+	case "add":
+		p, err := a.store.CreateProject(ctx, operands[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Project %s created as #%d.\n", p.Name, p.ID)
+		return nil
+	}
+
+	p, err := a.findProject(ctx, operands[0])
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "rename":
+		renamed, err := a.store.RenameProject(ctx, p.ID, operands[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Project %s renamed to %s.\n", p.Name, renamed.Name)
+	case "archive", "unarchive":
+		if _, err := a.store.ArchiveProject(ctx, p.ID, action == "archive"); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Project %s %sd.\n", p.Name, action)
+	case "rm":
+		if err := a.store.DeleteProject(ctx, p.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Project %s deleted.\n", p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/grpcclient"
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func runCmd(t *testing.T, store todo.Storage, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = Exec(context.Background(), store, append([]string{"/usr/local/bin/todos"}, args...), &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func TestCmdAdd(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	out, stderr, code := runCmd(t, store, "add", "Buy milk", "-d", "2 litres", "-p", "high", "-t", "home,errand", "--due", "2026-03-05", "--at", "09:00")
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr)
+	}
+	if !strings.Contains(out, "Added #1") {
+		t.Errorf("expected success message, got: %s", out)
+	}
+	todos := listTodos(t, store)
+	if len(todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(todos))
+	}
+	got := todos[0]
+	if got.Title != "Buy milk" || got.Description != "2 litres" || got.Priority != todo.PriorityHigh ||
+		got.DueDate != "2026-03-05" || got.DueTime != "09:00" || strings.Join(got.Tags, ",") != "home,errand" {
+		t.Errorf("unexpected todo: %+v", got)
+	}
+}
+
+func TestCmdAddTitleAfterDoubleDash(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	if _, stderr, code := runCmd(t, store, "add", "--", "-5 degrees"); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr)
+	}
+	if todos := listTodos(t, store); len(todos) != 1 || todos[0].Title != "-5 degrees" {
+		t.Fatalf("unexpected todos: %+v", todos)
+	}
+}
+
+func TestCmdList(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "Task one", Tags: []string{"work"}},
+		todo.Todo{ID: 2, Title: "Task two", Completed: true},
+	)
+	out, _, code := runCmd(t, store, "list")
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	if !strings.Contains(out, "[ ] 1. Task one #work") || !strings.Contains(out, "[✓] 2. Task two") {
+		t.Errorf("expected both todos, got: %s", out)
+	}
+
+	out, _, _ = runCmd(t, store, "list", "-t", "work")
+	if strings.Contains(out, "Task two") {
+		t.Errorf("expected only tagged todos, got: %s", out)
+	}
+}
+
+func TestCmdListJSON(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "Task one"},
+		todo.Todo{ID: 2, Title: "Task two", Completed: true},
+	)
+	out, _, code := runCmd(t, store, "list", "--json", "--filter", "completed=true")
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	var todos []todo.Todo
+	if err := json.Unmarshal([]byte(out), &todos); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(todos) != 1 || todos[0].ID != 2 || !todos[0].Completed {
+		t.Errorf("unexpected todos: %+v", todos)
+	}
+
+	out, _, _ = runCmd(t, storage.NewMemoryStorage(), "list", "--json")
+	if strings.TrimSpace(out) != "[]" {
+		t.Errorf("expected an empty JSON array, got: %s", out)
+	}
+}
+
+func TestCmdListSort(t *testing.T) {
+	base := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "older", CreatedAt: base.Add(-time.Hour), UpdatedAt: base},
+		todo.Todo{ID: 2, Title: "newer", CreatedAt: base, UpdatedAt: base.Add(-time.Minute)},
+	)
+	out, _, code := runCmd(t, store, "list", "-sort", "created")
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	if strings.Index(out, "newer") > strings.Index(out, "older") {
+		t.Errorf("expected the newest todo first, got: %s", out)
+	}
+	out, _, _ = runCmd(t, store, "list", "-sort", "updated")
+	if strings.Index(out, "older") > strings.Index(out, "newer") {
+		t.Errorf("expected the latest change first, got: %s", out)
+	}
+
+	if _, stderr, code := runCmd(t, store, "list", "-sort", "due"); code != ExitUsage || !strings.Contains(stderr, `invalid -sort "due"`) {
+		t.Errorf("expected a usage error, got exit %d: %s", code, stderr)
+	}
+}
+
+func TestCmdDoneAndRm(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 3, Title: "Task"}, todo.Todo{ID: 4, Title: "Other"})
+	if out, _, code := runCmd(t, store, "done", "3"); code != ExitOK || !strings.Contains(out, "Todo 3 marked as completed.") {
+		t.Fatalf("done: exit %d, output: %s", code, out)
+	}
+	if out, _, code := runCmd(t, store, "rm", "4"); code != ExitOK || !strings.Contains(out, "Todo 4 moved to the trash.") {
+		t.Fatalf("rm: exit %d, output: %s", code, out)
+	}
+	todos := listTodos(t, store)
+	if len(todos) != 1 || !todos[0].Completed {
+		t.Fatalf("unexpected todos: %+v", todos)
+	}
+	if out, _, code := runCmd(t, store, "undone", "3"); code != ExitOK || !strings.Contains(out, "Todo 3 marked as incomplete.") {
+		t.Fatalf("undone: exit %d, output: %s", code, out)
+	}
+}
+
+func TestCmdTrash(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "Task", Version: 1})
+	if _, stderr, code := runCmd(t, store, "rm", "1"); code != ExitOK {
+		t.Fatalf("rm: exit %d: %s", code, stderr)
+	}
+	out, _, code := runCmd(t, store, "list", "-trash")
+	if code != ExitOK || !strings.Contains(out, "[ ] 1. Task (deleted ") {
+		t.Fatalf("list -trash: exit %d, output: %s", code, out)
+	}
+	if out, _, code := runCmd(t, store, "restore", "1", "-if-version", "2"); code != ExitOK || !strings.Contains(out, "Todo 1 restored.") {
+		t.Fatalf("restore: exit %d, output: %s", code, out)
+	}
+	if _, _, code := runCmd(t, store, "restore", "1"); code != ExitPrecondition {
+		t.Errorf("restore of a live todo: expected exit %d, got %d", ExitPrecondition, code)
+	}
+
+	if _, stderr, code := runCmd(t, store, "rm", "1"); code != ExitOK {
+		t.Fatalf("rm: exit %d: %s", code, stderr)
+	}
+	if out, _, code := runCmd(t, store, "purge"); code != ExitOK || !strings.Contains(out, "1 todo(s) permanently deleted.") {
+		t.Fatalf("purge: exit %d, output: %s", code, out)
+	}
+	if _, _, code := runCmd(t, store, "restore", "1"); code != ExitNotFound {
+		t.Errorf("restore after purge: expected exit %d, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCmdEdit(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 2, Title: "Old", Description: "same"})
+	out, stderr, code := runCmd(t, store, "edit", "2", "--title", "New", "--desc", "same", "-p", "low")
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr)
+	}
+	if !strings.Contains(out, "Todo 2 updated.") || !strings.Contains(out, "[ ] 2. [low] New - same") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	got := listTodos(t, store)[0]
+	if got.Title != "New" || got.Description != "same" || got.Priority != todo.PriorityLow {
+		t.Errorf("unexpected todo: %+v", got)
+	}
+
+	// An invalid field leaves the valid ones unapplied.
+	if _, _, code := runCmd(t, store, "edit", "2", "--title", "Newer", "--due", "tomorrow"); code != ExitInvalid {
+		t.Fatalf("expected exit %d, got %d", ExitInvalid, code)
+	}
+	if got := listTodos(t, store)[0]; got.Title != "New" {
+		t.Errorf("expected title to stay 'New', got %q", got.Title)
+	}
+}
+
+func TestCmdRepeat(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	if _, stderr, code := runCmd(t, store, "add", "Pay rent", "-due", "2099-03-01", "-repeat", "monthly on the 1st"); code != ExitOK {
+		t.Fatalf("add -repeat: exit %d: %s", code, stderr)
+	}
+	out, stderr, code := runCmd(t, store, "done", "1")
+	if code != ExitOK {
+		t.Fatalf("done: exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "Next occurrence is #2.") {
+		t.Errorf("expected next occurrence message, got: %s", out)
+	}
+	if next := listTodos(t, store)[1]; next.DueDate != "2099-04-01" || next.Recurrence != "FREQ=MONTHLY;BYMONTHDAY=1" {
+		t.Errorf("unexpected next occurrence: %+v", next)
+	}
+
+	// Completing it again after reopening it adds no other occurrence.
+	if out, stderr, code := runCmd(t, store, "undone", "1"); code != ExitOK || strings.Contains(out, "Next occurrence") {
+		t.Fatalf("undone: exit %d: %s%s", code, out, stderr)
+	}
+	if out, stderr, code := runCmd(t, store, "done", "1"); code != ExitOK || strings.Contains(out, "Next occurrence") {
+		t.Errorf("expected no next occurrence message, got exit %d: %s%s", code, out, stderr)
+	}
+	if todos := listTodos(t, store); len(todos) != 2 {
+		t.Errorf("expected no other occurrence, got %+v", todos)
+	}
+
+	if _, stderr, code := runCmd(t, store, "edit", "2", "-repeat", ""); code != ExitOK {
+		t.Fatalf("edit -repeat: exit %d: %s", code, stderr)
+	}
+	if got := listTodos(t, store)[1]; got.Recurrence != "" {
+		t.Errorf("expected recurrence cleared, got %q", got.Recurrence)
+	}
+}
+
+func TestCmdSubtasks(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "Release", Version: 1}, todo.Todo{ID: 2, Title: "Tag", Version: 1})
+	if _, stderr, code := runCmd(t, store, "add", "Write notes", "-parent", "1"); code != ExitOK {
+		t.Fatalf("add -parent: exit %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCmd(t, store, "edit", "2", "-parent", "1"); code != ExitOK {
+		t.Fatalf("edit -parent: exit %d: %s", code, stderr)
+	}
+	out, _, _ := runCmd(t, store, "list")
+	if !strings.Contains(out, "[ ] 1. Release (0/2 done)") || !strings.Contains(out, "\n    [ ] 2. Tag") {
+		t.Fatalf("expected a tree, got: %s", out)
+	}
+	if _, _, code := runCmd(t, store, "edit", "1", "-parent", "3"); code != ExitPrecondition {
+		t.Errorf("move under a subtask: expected exit %d, got %d", ExitPrecondition, code)
+	}
+
+	if _, stderr, code := runCmd(t, store, "rm", "1"); code != ExitPrecondition || !strings.Contains(stderr, "has subtasks") {
+		t.Fatalf("rm of a parent: exit %d: %s", code, stderr)
+	}
+	out, stderr, code := runCmd(t, store, "rm", "-r", "1")
+	if code != ExitOK || !strings.Contains(out, "Todo 1 and 2 subtask(s) moved to the trash.") {
+		t.Fatalf("rm -r: exit %d: %s%s", code, out, stderr)
+	}
+	if todos := listTodos(t, store); len(todos) != 0 {
+		t.Fatalf("expected the tree in the trash, got %+v", todos)
+	}
+}
+
+func TestCmdProjects(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "Inbox task", Version: 1})
+	out, stderr, code := runCmd(t, store, "project", "add", "Work")
+	if code != ExitOK || !strings.Contains(out, "Project Work created as #1.") {
+		t.Fatalf("project add: exit %d: %s%s", code, out, stderr)
+	}
+	if _, stderr, code := runCmd(t, store, "add", "Report", "-project", "work"); code != ExitOK {
+		t.Fatalf("add -project: exit %d: %s", code, stderr)
+	}
+	if out, _, _ := runCmd(t, store, "list", "-project", "1"); !strings.Contains(out, "2. Report") || strings.Contains(out, "Inbox task") {
+		t.Fatalf("expected only Work's todos, got: %s", out)
+	}
+	if out, _, _ := runCmd(t, store, "list"); strings.Contains(out, "Report") {
+		t.Fatalf("expected only the Inbox's todos, got: %s", out)
+	}
+
+	if _, stderr, code := runCmd(t, store, "edit", "1", "-project", "Work"); code != ExitOK {
+		t.Fatalf("edit -project: exit %d: %s", code, stderr)
+	}
+	if todos := listTodos(t, store); len(todos) != 0 {
+		t.Fatalf("expected the Inbox to be empty, got %+v", todos)
+	}
+	if _, stderr, code := runCmd(t, store, "project", "archive", "Work"); code != ExitOK {
+		t.Fatalf("project archive: exit %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCmd(t, store, "add", "x", "-project", "Work"); code != ExitPrecondition || !strings.Contains(stderr, "project is archived") {
+		t.Fatalf("add to archived project: exit %d: %s", code, stderr)
+	}
+	if out, _, _ := runCmd(t, store, "project", "list"); !strings.Contains(out, "0. Inbox") || !strings.Contains(out, "1. Work (archived)") {
+		t.Fatalf("expected the projects, got: %s", out)
+	}
+	out, stderr, code = runCmd(t, store, "project", "rename", "work", "Office")
+	if code != ExitOK || !strings.Contains(out, "Project Work renamed to Office.") {
+		t.Fatalf("project rename: exit %d: %s%s", code, out, stderr)
+	}
+	if _, _, code := runCmd(t, store, "project", "rm", "Office"); code != ExitPrecondition {
+		t.Errorf("rm of a project with todos: expected exit %d, got %d", ExitPrecondition, code)
+	}
+}
+
+func TestCmdDependencies(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "Design", Version: 1},
+		todo.Todo{ID: 2, Title: "Build", Version: 1},
+		todo.Todo{ID: 3, Title: "Docs", Tags: []string{"docs"}, Version: 1},
+	)
+	if _, stderr, code := runCmd(t, store, "block", "2", "1"); code != ExitOK {
+		t.Fatalf("block: exit %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCmd(t, store, "block", "3", "2"); code != ExitOK {
+		t.Fatalf("block: exit %d: %s", code, stderr)
+	}
+	if _, _, code := runCmd(t, store, "block", "1", "3"); code != ExitPrecondition {
+		t.Errorf("cycle: expected exit %d, got %d", ExitPrecondition, code)
+	}
+	if _, _, code := runCmd(t, store, "done", "2"); code != ExitPrecondition {
+		t.Errorf("done while blocked: expected exit %d, got %d", ExitPrecondition, code)
+	}
+
+	out, _, _ := runCmd(t, store, "list", "-ready")
+	if !strings.Contains(out, "1. Design") || strings.Contains(out, "2. Build") || strings.Contains(out, "3. Docs") {
+		t.Fatalf("expected only Design to be ready, got: %s", out)
+	}
+	// Build falls outside the filter but still blocks Docs.
+	if out, _, _ := runCmd(t, store, "list", "-ready", "-t", "docs"); strings.Contains(out, "3. Docs") {
+		t.Fatalf("expected Docs to stay blocked under a filter, got: %s", out)
+	}
+
+	out, stderr, code := runCmd(t, store, "unblock", "3", "2")
+	if code != ExitOK || !strings.Contains(out, "Todo 3 no longer waits on #2.") {
+		t.Fatalf("unblock: exit %d: %s%s", code, out, stderr)
+	}
+	if out, _, _ := runCmd(t, store, "list", "-ready", "-t", "docs"); !strings.Contains(out, "3. Docs") {
+		t.Fatalf("expected Docs to be ready, got: %s", out)
+	}
+}
+
+func TestCmdIfVersion(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "Task", Version: 3})
+	_, stderr, code := runCmd(t, store, "edit", "1", "-title", "New", "-if-version", "2")
+	if code != ExitConflict {
+		t.Fatalf("expected exit %d, got %d: %s", ExitConflict, code, stderr)
+	}
+	if !strings.Contains(stderr, "version conflict") {
+		t.Errorf("expected a conflict message, got: %s", stderr)
+	}
+	if got := listTodos(t, store)[0]; got.Title != "Task" {
+		t.Errorf("expected title to stay 'Task', got %q", got.Title)
+	}
+
+	if _, stderr, code := runCmd(t, store, "edit", "1", "-title", "New", "-if-version", "3"); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr)
+	}
+	if _, _, code := runCmd(t, store, "rm", "1", "-if-version", "3"); code != ExitConflict {
+		t.Errorf("expected exit %d after the edit, got %d", ExitConflict, code)
+	}
+}
+
+func TestCmdExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, ExitUsage},
+		{"help", []string{"help"}, ExitOK},
+		{"unknown command", []string{"frobnicate"}, ExitUsage},
+		{"unknown flag", []string{"list", "--yaml"}, ExitUsage},
+		{"missing title", []string{"add"}, ExitUsage},
+		{"non-numeric ID", []string{"done", "three"}, ExitUsage},
+		{"extra argument", []string{"rm", "1", "2"}, ExitUsage},
+		{"nothing to edit", []string{"edit", "1"}, ExitUsage},
+		{"time without date", []string{"edit", "1", "-at", "09:00"}, ExitUsage},
+		{"not found", []string{"rm", "99"}, ExitNotFound},
+		{"edit not found", []string{"edit", "99", "-title", "x"}, ExitNotFound},
+		{"invalid ID", []string{"done", "0"}, ExitInvalid},
+		{"empty title", []string{"add", ""}, ExitInvalid},
+		{"invalid priority", []string{"add", "x", "-p", "huge"}, ExitInvalid},
+		{"invalid filter", []string{"list", "-filter", "title~"}, ExitInvalid},
+		{"invalid repeat", []string{"add", "x", "-repeat", "every fortnight"}, ExitInvalid},
+		{"already completed", []string{"done", "1"}, ExitPrecondition},
+		{"missing parent", []string{"add", "x", "-parent", "99"}, ExitPrecondition},
+		{"one ID to block", []string{"block", "1"}, ExitUsage},
+		{"missing blocker", []string{"block", "1", "99"}, ExitPrecondition},
+		{"ready trash", []string{"list", "-ready", "-trash"}, ExitUsage},
+		{"title unchanged", []string{"edit", "1", "-title", "Task"}, ExitPrecondition},
+		{"stale version", []string{"undone", "1", "-if-version", "2"}, ExitConflict},
+		{"missing project", []string{"list", "-project", "Garden"}, ExitNotFound},
+		{"project without action", []string{"project"}, ExitUsage},
+		{"unknown project action", []string{"project", "frobnicate"}, ExitUsage},
+		{"rename without name", []string{"project", "rename", "1"}, ExitUsage},
+		{"invalid project name", []string{"project", "add", " "}, ExitInvalid},
+		{"taken project name", []string{"project", "add", "Inbox"}, ExitPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "Task", Completed: true})
+			if _, stderr, code := runCmd(t, store, tt.args...); code != tt.want {
+				t.Errorf("expected exit %d, got %d: %s", tt.want, code, stderr)
+			}
+		})
+	}
+}
+
+// lockedStorage rejects every listing the way a server does a call without
+// a valid token.
+type lockedStorage struct {
+	todo.Storage
+}
+
+func (lockedStorage) List(context.Context, todo.ListOptions) ([]todo.Todo, string, error) {
+	return nil, "", todo.ErrUnauthenticated
+}
+
+func TestCmdUnauthenticated(t *testing.T) {
+	_, stderr, code := runCmd(t, lockedStorage{storage.NewMemoryStorage()}, "list")
+	if code != ExitAuth {
+		t.Fatalf("expected exit %d, got %d: %s", ExitAuth, code, stderr)
+	}
+	if !strings.Contains(stderr, "unauthenticated") {
+		t.Errorf("expected the error on stderr, got: %s", stderr)
+	}
+}
+
+// limitError is an error reported the way a server reports one, with the
+// todo and the limit it concerns kept apart from its message.
+type limitError struct {
+	err      error
+	metadata map[string]int
+}
+
+func (e limitError) Error() string { return e.err.Error() }
+func (e limitError) Unwrap() error { return e.err }
+
+func (e limitError) ID() (int, bool) { return e.Limit("id") }
+
+func (e limitError) Limit(key string) (int, bool) {
+	n, ok := e.metadata[key]
+	return n, ok
+}
+
+var _ reportedError = (*grpcclient.Error)(nil)
+
+// limitedStorage rejects every update with err.
+type limitedStorage struct {
+	todo.Storage
+	err error
+}
+
+func (s limitedStorage) Update(context.Context, todo.Todo, []string) (todo.Todo, error) {
+	return todo.Todo{}, s.err
+}
+
+func TestCmdReportedError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"id and limit", limitError{todo.ErrTitleTooLong, map[string]int{"id": 1, "max_title_length": 200}}, "Error: title exceeds maximum length [todo #1, limit 200]\n"},
+		{"id only", limitError{todo.ErrVersionConflict, map[string]int{"id": 1}}, "Error: " + todo.ErrVersionConflict.Error() + " [todo #1]\n"},
+		{"another limit", limitError{todo.ErrTitleTooLong, map[string]int{"max_tags": 10}}, "Error: title exceeds maximum length\n"},
+		{"nothing reported", todo.ErrTitleTooLong, "Error: title exceeds maximum length\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := limitedStorage{storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "task", Version: 1}), tt.err}
+			if _, stderr, _ := runCmd(t, store, "edit", "1", "-title", "New"); stderr != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestCmdUsage(t *testing.T) {
+	_, stderr, _ := runCmd(t, storage.NewMemoryStorage(), "rm")
+	if !strings.Contains(stderr, "Error: expected exactly one todo ID") || !strings.Contains(stderr, "Usage: todos rm ID") {
+		t.Errorf("expected error and usage, got: %s", stderr)
+	}
+	out, _, _ := runCmd(t, storage.NewMemoryStorage(), "help")
+	for _, name := range []string{"add", "list", "done", "undone", "rm", "restore", "purge", "edit", "project", "login", "logout"} {
+		if !strings.Contains(out, "  "+name+" ") {
+			t.Errorf("expected %q in help, got: %s", name, out)
+		}
+	}
+}
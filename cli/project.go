@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// findProject returns the project named by input, either its ID or its name
+// in any case. "0" and "Inbox" name the Inbox.
+func (a *App) findProject(ctx context.Context, input string) (todo.Project, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return todo.Project{}, fmt.Errorf("%w: enter a project name or ID", todo.ErrInvalidProjectName)
+	}
+	projects, err := a.store.ListProjects(ctx)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	if id, err := strconv.Atoi(strings.TrimPrefix(input, "#")); err == nil {
+		if id == todo.InboxID {
+			return todo.Inbox(), nil
+		}
+		for _, p := range projects {
+			if p.ID == id {
+				return p, nil
+			}
+		}
+		return todo.Project{}, fmt.Errorf("project with id %d: %w", id, todo.ErrProjectNotFound)
+	}
+	if p, ok := todo.ProjectNamed(projects, input); ok {
+		return p, nil
+	}
+	return todo.Project{}, fmt.Errorf("project %q: %w", input, todo.ErrProjectNotFound)
+}
+
+// listEveryProject fetches the live todos of every project, for judging
+// whether todos are blocked, as their blockers may be in other projects.
+func (a *App) listEveryProject(ctx context.Context) ([]todo.Todo, error) {
+	projects, err := a.store.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all, err := a.listAll(ctx, todo.ListOptions{ProjectID: todo.InboxID})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		todos, err := a.listAll(ctx, todo.ListOptions{ProjectID: p.ID})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, todos...)
+	}
+	return all, nil
+}
+
+// printProjects prints the Inbox and projects, marking the active one.
+func (a *App) printProjects(projects []todo.Project) {
+	for _, p := range append([]todo.Project{todo.Inbox()}, projects...) {
+		var suffix string
+		if p.Archived {
+			suffix += " (archived)"
+		}
+		if p.ID == a.project.ID {
+			suffix += " (active)"
+		}
+		fmt.Fprintf(a.out, "%d. %s%s\n", p.ID, p.Name, suffix)
+	}
+}
+
+// handleProjects lists, switches between, creates and changes projects.
+func (a *App) handleProjects(ctx context.Context) error {
+	choice, err := a.readLine(ctx, "> (l)ist projects, (s)witch project, (c)reate, re(n)ame, (a)rchive or unarchive, or (d)elete a project? ")
+	if err != nil {
+		return a.handleErr(err)
+	}
+	switch strings.ToLower(choice) {
+	case "l", "list":
+		projects, err := a.store.ListProjects(ctx)
+		if err != nil {
+			return a.handleErr(err)
+		}
+		a.printProjects(projects)
+	case "s", "switch":
+		p, err := a.pickProject(ctx, "> Enter the name or ID of the project to switch to: ")
+		if err != nil {
+			return a.handleErr(err)
+		}
+		a.project = p
+		fmt.Fprintf(a.out, "Switched to project %s.\n", p.Name)
+	case "c", "create":
+		name, err := a.readLine(ctx, "> Enter the name of the new project: ")
+		if err != nil {
+			return a.handleErr(err)
+		}
+		p, err := a.store.CreateProject(ctx, name)
+		if err != nil {
+			return a.handleErr(err)
+		}
+		fmt.Fprintf(a.out, "Project %s created as #%d. Switch to it to add todos.\n", p.Name, p.ID)
+	case "n", "rename":
+		if err := a.renameProject(ctx); err != nil {
+			return a.handleErr(err)
+		}
+	case "a", "archive", "unarchive":
+		if err := a.archiveProject(ctx); err != nil {
+			return a.handleErr(err)
+		}
+	case "d", "delete":
+		if err := a.deleteProject(ctx); err != nil {
+			return a.handleErr(err)
+		}
+	default:
+		fmt.Fprintf(a.out, "Error: invalid choice %q, enter 'l', 's', 'c', 'n', 'a', or 'd'.\n", choice)
+	}
+	return nil
+}
+
+// pickProject shows the projects and asks for one by name or ID.
+func (a *App) pickProject(ctx context.Context, prompt string) (todo.Project, error) {
+	projects, err := a.store.ListProjects(ctx)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	a.printProjects(projects)
+	input, err := a.readLine(ctx, prompt)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	return a.findProject(ctx, input)
+}
+
+// renameProject prompts for a project and its new name and renames it.
+func (a *App) renameProject(ctx context.Context) error {
+	p, err := a.pickProject(ctx, "> Enter the name or ID of the project to rename: ")
+	if err != nil {
+		return err
+	}
+	name, err := a.readLine(ctx, "> Enter the new name: ")
+	if err != nil {
+		return err
+	}
+	renamed, err := a.store.RenameProject(ctx, p.ID, name)
+	if errors.Is(err, todo.ErrProjectUnchanged) {
+		fmt.Fprintln(a.out, "Info: project already has that name.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if renamed.ID == a.project.ID {
+		a.project = renamed
+	}
+	fmt.Fprintf(a.out, "Project renamed to %s.\n", renamed.Name)
+	return nil
+}
+
+// archiveProject prompts for a project and archives it, or unarchives it if
+// it is archived.
+func (a *App) archiveProject(ctx context.Context) error {
+	p, err := a.pickProject(ctx, "> Enter the name or ID of the project to archive or unarchive: ")
+	if err != nil {
+		return err
+	}
+	updated, err := a.store.ArchiveProject(ctx, p.ID, !p.Archived)
+	if err != nil {
+		return err
+	}
+	if updated.ID == a.project.ID {
+		a.project = updated
+	}
+	if updated.Archived {
+		fmt.Fprintf(a.out, "Project %s archived. Its todos are kept, but no new ones can be added.\n", updated.Name)
+	} else {
+		fmt.Fprintf(a.out, "Project %s unarchived.\n", updated.Name)
+	}
+	return nil
+}
+
+// deleteProject prompts for a project and deletes it once confirmed. Only a
+// project without todos, in the trash or not, can be deleted.
+func (a *App) deleteProject(ctx context.Context) error {
+	p, err := a.pickProject(ctx, "> Enter the name or ID of the project to delete: ")
+	if err != nil {
+		return err
+	}
+	if p.ID == todo.InboxID {
+		return todo.ValidateProjectID(p.ID)
+	}
+	answer, err := a.readLine(ctx, fmt.Sprintf("> Delete project %s? (y/n): ", p.Name))
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		fmt.Fprintln(a.out, "Project left as it was.")
+		return nil
+	}
+	err = a.store.DeleteProject(ctx, p.ID)
+	if errors.Is(err, todo.ErrProjectNotEmpty) {
+		return fmt.Errorf("%w: move or purge its todos first, including those in the trash", err)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Project %s deleted.\n", p.Name)
+	if p.ID == a.project.ID {
+		a.project = todo.Inbox()
+		fmt.Fprintf(a.out, "Switched to project %s.\n", a.project.Name)
+	}
+	return nil
+}
+
+// doMoveToProject prompts for a project and moves the todo to it, at the top
+// level.
+// Returns nil on success (including "unchanged" info), or an error.
+func (a *App) doMoveToProject(ctx context.Context, t todo.Todo) error {
+	p, err := a.pickProject(ctx, "> Enter the name or ID of the project to move to: ")
+	if err != nil {
+		return err
+	}
+	mask := []string{todo.FieldProjectID, todo.FieldParentID}
+	updated, err := a.store.Update(expecting(ctx, t), todo.Todo{ID: t.ID, ProjectID: p.ID}, mask)
+	if err != nil {
+		if errors.Is(err, todo.ErrTodoUnchanged) {
+			fmt.Fprintln(a.out, "Info: todo is already there.")
+			return nil
+		}
+		if errors.Is(err, todo.ErrHasSubtasks) {
+			return fmt.Errorf("%w: move its subtasks out first", err)
+		}
+		return err
+	}
+	a.recordEdit("project move", t, updated, mask)
+	fmt.Fprintf(a.out, "Todo moved to project %s.\n", p.Name)
+	a.printTodos([]todo.Todo{updated})
+	return nil
+}
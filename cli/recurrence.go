@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// repeatExample shows the phrases parseRepeat accepts, for prompts and
+// errors.
+const repeatExample = "every 2 weeks on mon"
+
+var weekdayWords = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var ordinalWords = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1}
+
+var unitWords = map[string]todo.Frequency{
+	"day": todo.Daily, "days": todo.Daily,
+	"week": todo.Weekly, "weeks": todo.Weekly,
+	"month": todo.Monthly, "months": todo.Monthly,
+	"year": todo.Yearly, "years": todo.Yearly,
+}
+
+var adverbs = map[string]todo.Frequency{
+	"daily": todo.Daily, "weekly": todo.Weekly, "monthly": todo.Monthly, "yearly": todo.Yearly, "annually": todo.Yearly,
+}
+
+// workweek is the BYDAY of "every weekday".
+var workweek = []todo.RuleDay{{Day: time.Monday}, {Day: time.Tuesday}, {Day: time.Wednesday}, {Day: time.Thursday}, {Day: time.Friday}}
+
+// parseRepeat turns a schedule such as "every 2 weeks on mon", "monthly on
+// the last fri" or "every weekday until 2026-12-31", or a rule in RRULE
+// syntax, into a rule in canonical RRULE syntax. A blank schedule returns
+// no rule.
+func parseRepeat(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", nil
+	}
+	if strings.Contains(strings.ToUpper(input), "FREQ=") {
+		rule, err := todo.ParseRule(input)
+		if err != nil {
+			return "", err
+		}
+		return rule.String(), nil
+	}
+	p := &phrase{words: strings.Fields(strings.ToLower(strings.ReplaceAll(input, ",", " ")))}
+	rule, err := p.parse()
+	if errors.Is(err, todo.ErrInvalidRecurrence) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %v (e.g. %q)", todo.ErrInvalidRecurrence, input, err, repeatExample)
+	}
+	return rule.String(), nil
+}
+
+// phrase parses the words of a schedule, lower-cased and without commas.
+type phrase struct {
+	words []string
+}
+
+func (p *phrase) peek() string {
+	if len(p.words) == 0 {
+		return ""
+	}
+	return p.words[0]
+}
+
+func (p *phrase) next() string {
+	w := p.peek()
+	if len(p.words) > 0 {
+		p.words = p.words[1:]
+	}
+	return w
+}
+
+// skip drops the filler words "the" and "and".
+func (p *phrase) skip() {
+	for p.peek() == "the" || p.peek() == "and" {
+		p.next()
+	}
+}
+
+func (p *phrase) parse() (todo.Rule, error) {
+	r := todo.Rule{Interval: 1}
+	word := p.next()
+	if freq, ok := adverbs[word]; ok {
+		r.Freq = freq
+	} else if word != "every" {
+		return todo.Rule{}, errors.New(`start with "every" or e.g. "weekly"`)
+	} else if err := p.parseEvery(&r); err != nil {
+		return todo.Rule{}, err
+	}
+	for len(p.words) > 0 {
+		switch word := p.next(); {
+		case word == "on":
+			if err := p.parseOn(&r); err != nil {
+				return todo.Rule{}, err
+			}
+		case word == "until":
+			until, err := time.Parse(todo.DueDateLayout, p.next())
+			if err != nil {
+				return todo.Rule{}, errors.New(`"until" needs a date (YYYY-MM-DD)`)
+			}
+			r.Until = until.Format(todo.DueDateLayout)
+		case isNumber(word) && (p.peek() == "times" || p.peek() == "time"):
+			p.next()
+			r.Count, _ = strconv.Atoi(word)
+		default:
+			return todo.Rule{}, fmt.Errorf("unexpected %q", word)
+		}
+	}
+	// Let the rule's own checks catch combinations such as a count with an
+	// end date.
+	return todo.ParseRule(r.String())
+}
+
+// parseEvery parses what follows "every": an optional interval and a unit,
+// "weekday", or a list of weekdays.
+func (p *phrase) parseEvery(r *todo.Rule) error {
+	if p.peek() == "other" {
+		p.next()
+		r.Interval = 2
+	} else if isNumber(p.peek()) {
+		r.Interval, _ = strconv.Atoi(p.next())
+		if r.Interval < 1 {
+			return errors.New("the interval must be at least 1")
+		}
+	}
+	word := p.next()
+	if freq, ok := unitWords[word]; ok {
+		r.Freq = freq
+		return nil
+	}
+	if word == "weekday" || word == "weekdays" {
+		r.Freq, r.ByDay = todo.Weekly, slices.Clone(workweek)
+		return nil
+	}
+	if _, ok := weekdayWords[word]; ok {
+		p.words = append([]string{word}, p.words...)
+		r.Freq = todo.Weekly
+		return p.parseOn(r)
+	}
+	if word == "" {
+		return errors.New(`say how often, e.g. "every week"`)
+	}
+	return fmt.Errorf("unknown unit %q", word)
+}
+
+// parseOn parses the days that follow "on": weekdays for a daily or weekly
+// schedule, and for a monthly one days of the month ("the 1st and 15th",
+// "the last day", "day -2") or numbered weekdays ("the 2nd tue", "the last
+// fri").
+func (p *phrase) parseOn(r *todo.Rule) error {
+	found := false
+	for {
+		p.skip()
+		word := p.peek()
+		if day, ok := weekdayWords[word]; ok {
+			if r.Freq == todo.Yearly {
+				return errors.New("a yearly schedule cannot name weekdays")
+			}
+			p.next()
+			r.ByDay = append(r.ByDay, todo.RuleDay{Day: day})
+			found = true
+			continue
+		}
+		if r.Freq != todo.Monthly {
+			break
+		}
+		if word == "day" && len(p.words) > 1 && isNumber(strings.TrimPrefix(p.words[1], "-")) {
+			p.next()
+			day, _ := strconv.Atoi(p.next())
+			r.ByMonthDay = append(r.ByMonthDay, day)
+			found = true
+			continue
+		}
+		n, ok := ordinal(word)
+		if !ok {
+			break
+		}
+		p.next()
+		if day, ok := weekdayWords[p.peek()]; ok {
+			p.next()
+			r.ByDay = append(r.ByDay, todo.RuleDay{N: n, Day: day})
+		} else {
+			if p.peek() == "day" {
+				p.next()
+			}
+			r.ByMonthDay = append(r.ByMonthDay, n)
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf(`"on" needs days, e.g. "on mon" or "on the 15th"`)
+	}
+	return nil
+}
+
+// ordinal parses "1st", "22nd", "first" or "last".
+func ordinal(word string) (int, bool) {
+	if n, ok := ordinalWords[word]; ok {
+		return n, true
+	}
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if digits, ok := strings.CutSuffix(word, suffix); ok && isNumber(digits) {
+			n, _ := strconv.Atoi(digits)
+			return n, n >= 1 && n <= 31
+		}
+	}
+	return 0, false
+}
+
+func isNumber(word string) bool {
+	_, err := strconv.Atoi(word)
+	return err == nil && !strings.HasPrefix(word, "+")
+}
+
+// describeRule says in words how often a todo with the given rule recurs,
+// in a form parseRepeat reads back, e.g. "every 2 weeks on Mon, Thu". A
+// rule it cannot parse is returned as is.
+func describeRule(rule string) string {
+	r, err := todo.ParseRule(rule)
+	if err != nil {
+		return rule
+	}
+	units := map[todo.Frequency]string{todo.Daily: "day", todo.Weekly: "week", todo.Monthly: "month", todo.Yearly: "year"}
+	var b strings.Builder
+	days := r.ByDay
+	switch {
+	case r.Freq == todo.Weekly && r.Interval == 1 && slices.Equal(days, workweek):
+		b.WriteString("every weekday")
+		days = nil
+	case r.Interval == 1:
+		b.WriteString("every " + units[r.Freq])
+	default:
+		fmt.Fprintf(&b, "every %d %ss", r.Interval, units[r.Freq])
+	}
+	var on []string
+	for _, d := range r.ByMonthDay {
+		switch {
+		case d == -1:
+			on = append(on, "the last day")
+		case d < 0:
+			on = append(on, fmt.Sprintf("day %d", d))
+		default:
+			on = append(on, "the "+ordinalString(d))
+		}
+	}
+	for _, d := range days {
+		name := d.Day.String()[:3]
+		switch {
+		case d.N == -1:
+			name = "the last " + name
+		case d.N != 0:
+			name = "the " + ordinalString(d.N) + " " + name
+		}
+		on = append(on, name)
+	}
+	if len(on) > 0 {
+		b.WriteString(" on " + strings.Join(on, ", "))
+	}
+	if r.Count > 0 {
+		fmt.Fprintf(&b, ", %d times", r.Count)
+	}
+	if r.Until != "" {
+		b.WriteString(", until " + r.Until)
+	}
+	return b.String()
+}
+
+// ordinalString returns n as "1st", "2nd", "3rd", "11th" and so on.
+func ordinalString(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
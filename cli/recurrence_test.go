@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func TestParseRepeat(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"daily", "FREQ=DAILY"},
+		{"every day", "FREQ=DAILY"},
+		{"Every 3 Days", "FREQ=DAILY;INTERVAL=3"},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2"},
+		{"every 2 weeks on mon", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO"},
+		{"every mon, thu", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"weekly on tuesday and friday", "FREQ=WEEKLY;BYDAY=TU,FR"},
+		{"every weekday", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{"monthly on the 1st and 15th", "FREQ=MONTHLY;BYMONTHDAY=1,15"},
+		{"every month on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{"every month on day -3", "FREQ=MONTHLY;BYMONTHDAY=-3"},
+		{"every month on the 2nd tue", "FREQ=MONTHLY;BYDAY=2TU"},
+		{"every 3 months on the last fri", "FREQ=MONTHLY;INTERVAL=3;BYDAY=-1FR"},
+		{"annually", "FREQ=YEARLY"},
+		{"every day, 5 times", "FREQ=DAILY;COUNT=5"},
+		{"every week until 2026-12-31", "FREQ=WEEKLY;UNTIL=20261231"},
+		{"RRULE:freq=weekly;byday=mo", "FREQ=WEEKLY;BYDAY=MO"},
+	}
+	for _, tt := range tests {
+		got, err := parseRepeat(tt.input)
+		if err != nil {
+			t.Errorf("parseRepeat(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRepeat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseRepeatInvalid(t *testing.T) {
+	for _, input := range []string{
+		"sometimes",
+		"every",
+		"every 0 days",
+		"every fortnight",
+		"every week on the 15th",
+		"every year on mon",
+		"every day until soon",
+		"every day, 3 times until 2026-12-31",
+		"FREQ=HOURLY",
+	} {
+		if _, err := parseRepeat(input); !errors.Is(err, todo.ErrInvalidRecurrence) {
+			t.Errorf("parseRepeat(%q): expected ErrInvalidRecurrence, got %v", input, err)
+		}
+	}
+}
+
+func TestDescribeRule(t *testing.T) {
+	tests := []struct {
+		rule, want string
+	}{
+		{"FREQ=DAILY", "every day"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", "every 2 weeks on Mon, Thu"},
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "every weekday"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "every month on the 1st, the last day"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-2", "every month on day -2"},
+		{"FREQ=MONTHLY;BYDAY=3WE,-1FR", "every month on the 3rd Wed, the last Fri"},
+		{"FREQ=YEARLY;COUNT=3", "every year, 3 times"},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=20261231", "every 2 days, until 2026-12-31"},
+	}
+	for _, tt := range tests {
+		got := describeRule(tt.rule)
+		if got != tt.want {
+			t.Errorf("describeRule(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+		// What describeRule says must read back as the same rule.
+		if back, err := parseRepeat(got); err != nil || back != tt.rule {
+			t.Errorf("parseRepeat(%q) = %q, %v; want %q", got, back, err, tt.rule)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// step makes one change through the store and returns the todo's version
+// afterwards. ctx carries the version the todo must be at.
+type step func(ctx context.Context) (int, error)
+
+// change is a successful change made in this session to the todo with ID
+// id, together with the steps that revert and reapply it. Both run at the
+// version this session last left the todo at, so undoing never overwrites
+// someone else's change.
+type change struct {
+	// label names the change in messages, e.g. "title edit of #3".
+	label  string
+	id     int
+	revert step
+	apply  step
+}
+
+// record makes a change that left the todo with the given ID at version
+// undoable. It forgets every undone change, which can no longer be redone.
+func (a *App) record(label string, id, version int, revert, apply step) {
+	a.history = append(a.history, change{label: label, id: id, revert: revert, apply: apply})
+	a.undone = nil
+	a.versions[id] = version
+}
+
+func (a *App) handleUndo(ctx context.Context) error {
+	return a.replay(ctx, &a.history, &a.undone, "undo", "Undid", func(c change) step { return c.revert })
+}
+
+func (a *App) handleRedo(ctx context.Context) error {
+	return a.replay(ctx, &a.undone, &a.history, "redo", "Redid", func(c change) step { return c.apply })
+}
+
+// replay pops the latest change from from, runs the step of it chosen by
+// which, and pushes it onto to. A change that fails to replay, e.g. because
+// someone else changed the todo since, is dropped.
+func (a *App) replay(ctx context.Context, from, to *[]change, verb, done string, which func(change) step) error {
+	if len(*from) == 0 {
+		fmt.Fprintf(a.out, "Nothing to %s.\n", verb)
+		return nil
+	}
+	c := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+
+	version, err := which(c)(todo.WithExpectedVersion(ctx, a.versions[c.id]))
+	if version != 0 {
+		// Even a step that failed may have moved the todo on.
+		a.versions[c.id] = version
+	}
+	if err != nil {
+		return a.handleErr(fmt.Errorf("cannot %s the %s: %w", verb, c.label, err))
+	}
+	a.versions[c.id] = version
+	*to = append(*to, c)
+	fmt.Fprintf(a.out, "%s the %s.\n", done, c.label)
+	return nil
+}
+
+// versionOf returns the version of the todo a store method returned.
+func versionOf(t todo.Todo, err error) (int, error) {
+	return t.Version, err
+}
+
+// recordAdd makes adding t undoable by moving it to the trash and back.
+func (a *App) recordAdd(t todo.Todo) {
+	a.record(fmt.Sprintf("add of #%d", t.ID), t.ID, t.Version, a.deleteStep(t.ID), a.restoreStep(t.ID))
+}
+
+// recordDelete makes deleting the todo with the given ID, which left it at
+// version, undoable.
+func (a *App) recordDelete(id, version int) {
+	a.record(fmt.Sprintf("delete of #%d", id), id, version, a.restoreStep(id), a.deleteStep(id))
+}
+
+// recordRestore makes restoring t from the trash undoable.
+func (a *App) recordRestore(t todo.Todo) {
+	a.record(fmt.Sprintf("restore of #%d", t.ID), t.ID, t.Version, a.deleteStep(t.ID), a.restoreStep(t.ID))
+}
+
+// recordSetCompleted makes completing or reopening t undoable. nextID is the
+// next occurrence completing a recurring todo added, or 0.
+func (a *App) recordSetCompleted(t todo.Todo, nextID int) {
+	label := fmt.Sprintf("completion of #%d", t.ID)
+	if !t.Completed {
+		label = fmt.Sprintf("reopening of #%d", t.ID)
+	}
+	revert, apply := a.occurrenceSteps(nextID,
+		func(ctx context.Context) (int, error) {
+			return versionOf(a.store.SetCompleted(ctx, t.ID, !t.Completed))
+		},
+		func(ctx context.Context) (int, error) { return versionOf(a.store.SetCompleted(ctx, t.ID, t.Completed)) },
+	)
+	a.record(label, t.ID, t.Version, revert, apply)
+}
+
+// recordEdit makes an edit of the fields named by mask, from their values
+// in before to those in after, undoable.
+func (a *App) recordEdit(label string, before, after todo.Todo, mask []string) {
+	revert, apply := a.occurrenceSteps(spawned(before, after),
+		func(ctx context.Context) (int, error) { return versionOf(a.store.Update(ctx, before, mask)) },
+		func(ctx context.Context) (int, error) { return versionOf(a.store.Update(ctx, after, mask)) },
+	)
+	a.record(fmt.Sprintf("%s of #%d", label, after.ID), after.ID, after.Version, revert, apply)
+}
+
+// occurrenceSteps extends the steps of a change that added the next
+// occurrence nextID of a recurring todo to also move it to the trash and
+// back. The todo keeps its NextID, so reapplying the change adds no other
+// occurrence. This session does not track the occurrence's version, so it
+// is moved whatever its version.
+func (a *App) occurrenceSteps(nextID int, revert, apply step) (step, step) {
+	if nextID == 0 {
+		return revert, apply
+	}
+	return func(ctx context.Context) (int, error) {
+			version, err := revert(ctx)
+			if err != nil {
+				return 0, err
+			}
+			_, err = a.deleteStep(nextID)(todo.WithExpectedVersion(ctx, 0))
+			return version, err
+		}, func(ctx context.Context) (int, error) {
+			version, err := apply(ctx)
+			if err != nil {
+				return 0, err
+			}
+			_, err = a.restoreStep(nextID)(todo.WithExpectedVersion(ctx, 0))
+			return version, err
+		}
+}
+
+// dependencyChange adds or removes one blocker of a todo.
+type dependencyChange struct {
+	blockerID int
+	add       bool
+}
+
+func (a *App) changeDependency(ctx context.Context, id int, c dependencyChange) (todo.Todo, error) {
+	if c.add {
+		return a.store.AddDependency(ctx, id, c.blockerID)
+	}
+	return a.store.RemoveDependency(ctx, id, c.blockerID)
+}
+
+// inverse returns the changes that take back changes, in the order to make
+// them.
+func inverse(changes []dependencyChange) []dependencyChange {
+	inverted := make([]dependencyChange, len(changes))
+	for i, c := range changes {
+		inverted[len(changes)-1-i] = dependencyChange{blockerID: c.blockerID, add: !c.add}
+	}
+	return inverted
+}
+
+// recordDependencies makes the blocker changes applied, in order, to the
+// todo with after.ID undoable as one. after is the todo they left.
+func (a *App) recordDependencies(after todo.Todo, applied []dependencyChange) {
+	a.record(fmt.Sprintf("blocker edit of #%d", after.ID), after.ID, after.Version,
+		a.dependencySteps(after.ID, inverse(applied)), a.dependencySteps(after.ID, applied))
+}
+
+// dependencySteps returns a step that makes changes to the todo with the
+// given ID in turn, each at the version the previous one left. If one
+// fails, the step takes back those made before it and returns the version
+// that left along with the error, so the todo is changed all or not at all.
+// Should taking them back fail too, the error says the todo was left partly
+// changed.
+func (a *App) dependencySteps(id int, changes []dependencyChange) step {
+	return func(ctx context.Context) (int, error) {
+		version := todo.ExpectedVersion(ctx)
+		for i, c := range changes {
+			t, err := a.changeDependency(todo.WithExpectedVersion(ctx, version), id, c)
+			if err != nil {
+				return a.takeBackDependencies(ctx, id, version, changes[:i], err)
+			}
+			version = t.Version
+		}
+		return version, nil
+	}
+}
+
+// takeBackDependencies takes back the changes made to the todo with the
+// given ID, which left it at version, after the next one failed with err.
+func (a *App) takeBackDependencies(ctx context.Context, id, version int, made []dependencyChange, err error) (int, error) {
+	for _, c := range inverse(made) {
+		t, backErr := a.changeDependency(todo.WithExpectedVersion(ctx, version), id, c)
+		if backErr != nil {
+			return 0, fmt.Errorf("%w; #%d was left with only some of its blockers changed", err, id)
+		}
+		version = t.Version
+	}
+	return version, err
+}
+
+// deleteStep moves the todo to the trash together with any subtasks, which
+// restoreStep brings back with it.
+func (a *App) deleteStep(id int) step {
+	return func(ctx context.Context) (int, error) {
+		if _, err := a.store.DeleteTree(ctx, id); err != nil {
+			return 0, err
+		}
+		// Delete returns no todo, but like every change it moves the
+		// version on by one.
+		return todo.ExpectedVersion(ctx) + 1, nil
+	}
+}
+
+func (a *App) restoreStep(id int) step {
+	return func(ctx context.Context) (int, error) { return versionOf(a.store.Restore(ctx, id)) }
+}
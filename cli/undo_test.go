@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func TestUndoRedoAdd(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\ntask\n\n\n\nu\nq\n")
+	if !strings.Contains(output, "Undid the add of #1.") {
+		t.Fatalf("expected undo message, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 0 {
+		t.Fatalf("expected the added todo to be gone, got %+v", todos)
+	}
+
+	output = runApp(t, store, "1\nother\n\n\n\nu\nr\nq\n")
+	if !strings.Contains(output, "Redid the add of #2.") {
+		t.Fatalf("expected redo message, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 1 || todos[0].ID != 2 {
+		t.Fatalf("expected the redone todo to keep its ID, got %+v", todos)
+	}
+}
+
+func TestUndoRedoSequence(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "old", Description: "desc", Version: 1})
+	// Edit the title, complete the todo, undo both from the menu, then
+	// redo the title edit with the shortcut.
+	output := runApp(t, store, "6\n1\nt\nnew\n4\n1\n15\n15\nr\nq\n")
+
+	for _, want := range []string{
+		"Undid the completion of #1.",
+		"Undid the title edit of #1.",
+		"Redid the title edit of #1.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	got := listTodos(t, store)[0]
+	if got.Title != "new" || got.Completed || got.Description != "desc" {
+		t.Fatalf("unexpected todo: %+v", got)
+	}
+}
+
+func TestUndoDelete(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "keep me", Version: 1})
+	output := runApp(t, store, "3\n1\nu\nq\n")
+
+	if !strings.Contains(output, "Undid the delete of #1.") {
+		t.Fatalf("expected undo message, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 1 || todos[0].Title != "keep me" {
+		t.Fatalf("expected the todo to be back, got %+v", todos)
+	}
+}
+
+func TestUndoDeleteTree(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "parent", Version: 1},
+		todo.Todo{ID: 2, Title: "child", ParentID: 1, Version: 1},
+	)
+	runApp(t, store, "3\n1\ny\nu\nq\n")
+	if todos := listTodos(t, store); len(todos) != 2 {
+		t.Fatalf("expected the tree to be back, got %+v", todos)
+	}
+
+	runApp(t, store, "3\n1\ny\nu\nr\nq\n")
+	if todos := listTodos(t, store); len(todos) != 0 {
+		t.Fatalf("expected the redo to trash the tree again, got %+v", todos)
+	}
+}
+
+func TestUndoEditBoth(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "old", Description: "old desc", Version: 1})
+	runApp(t, store, "6\n1\nb\nnew\nnew desc\nu\nq\n")
+
+	if got := listTodos(t, store)[0]; got.Title != "old" || got.Description != "old desc" {
+		t.Fatalf("expected both fields to be reverted, got %+v", got)
+	}
+}
+
+func TestUndoTagsAndPriority(t *testing.T) {
+	store := storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "task", Tags: []string{"home"}, Version: 1})
+	output := runApp(t, store, "6\n1\ng\nwork -home\n6\n1\np\nhigh\nu\nq\n")
+
+	if !strings.Contains(output, "Undid the priority edit of #1.") {
+		t.Fatalf("expected undo message, got:\n%s", output)
+	}
+	got := listTodos(t, store)[0]
+	if got.Priority != todo.PriorityNone || strings.Join(got.Tags, ",") != "work" {
+		t.Fatalf("expected only the priority edit undone, got %+v", got)
+	}
+
+	runApp(t, store, "6\n1\ng\nurgent\nu\nq\n")
+	if got := listTodos(t, store)[0]; strings.Join(got.Tags, ",") != "work" {
+		t.Fatalf("expected the tag edit undone, got %+v", got)
+	}
+}
+
+func TestUndoBlockers(t *testing.T) {
+	store := storage.NewMemoryStorage(
+		todo.Todo{ID: 1, Title: "first", Version: 1},
+		todo.Todo{ID: 2, Title: "second", Version: 1},
+		todo.Todo{ID: 3, Title: "third", BlockedBy: []int{1}, Version: 1},
+	)
+	output := runApp(t, store, "6\n3\nk\n2 -1\nu\nq\n")
+
+	if !strings.Contains(output, "Undid the blocker edit of #3.") {
+		t.Fatalf("expected undo message, got:\n%s", output)
+	}
+	if got := listTodos(t, store)[2]; !slices.Equal(got.BlockedBy, []int{1}) {
+		t.Fatalf("expected the blockers reverted, got %+v", got)
+	}
+
+	runApp(t, store, "6\n3\nk\n2 -1\nu\nr\nq\n")
+	if got := listTodos(t, store)[2]; !slices.Equal(got.BlockedBy, []int{2}) {
+		t.Fatalf("expected the redo to reapply both changes, got %+v", got)
+	}
+}
+
+// blockingStorage fails the RemoveDependency calls that fail picks out.
+type blockingStorage struct {
+	*storage.MemoryStorage
+	fail func(blockerID int) bool
+}
+
+func (s blockingStorage) RemoveDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if s.fail(blockerID) {
+		return todo.Todo{}, fmt.Errorf("todo %d: %w", id, todo.ErrDependencyNotPresent)
+	}
+	return s.MemoryStorage.RemoveDependency(ctx, id, blockerID)
+}
+
+func TestUndoBlockersFailing(t *testing.T) {
+	seed := []todo.Todo{
+		{ID: 1, Title: "first", Version: 1},
+		{ID: 2, Title: "second", Version: 1},
+		{ID: 3, Title: "third", BlockedBy: []int{1}, Version: 1},
+	}
+	removals := 0
+	tests := []struct {
+		name     string
+		fail     func(blockerID int) bool
+		want     string
+		blockers []int
+	}{
+		// Undoing re-adds 1, then fails to remove 2 and removes 1 again.
+		{"taken back", func(blockerID int) bool { return blockerID == 2 }, "", []int{2}},
+		// The edit removes 1; undoing re-adds 1, then can neither remove 2
+		// nor 1.
+		{"partial", func(int) bool { removals++; return removals > 1 }, "; #3 was left with only some of its blockers changed", []int{2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := blockingStorage{storage.NewMemoryStorage(seed...), tt.fail}
+			output := runApp(t, store, "6\n3\nt\nrenamed\n6\n3\nk\n2 -1\nu\nu\nq\n")
+
+			if !strings.Contains(output, "Error: cannot undo the blocker edit of #3: todo 3: dependency not present"+tt.want+"\n") {
+				t.Fatalf("expected undo error, got:\n%s", output)
+			}
+			got := listTodos(t, store)[2]
+			if !slices.Equal(got.BlockedBy, tt.blockers) {
+				t.Fatalf("expected blockers %v, got %+v", tt.blockers, got)
+			}
+			// Once the blocker edit is taken back whole, the title edit
+			// before it can still be undone.
+			undone := got.Title == "third"
+			if undone != (tt.want == "") {
+				t.Fatalf("expected the title edit undone only after a full take-back, got:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestUndoNothing(t *testing.T) {
+	output := runApp(t, storage.NewMemoryStorage(), "u\nr\nq\n")
+
+	if !strings.Contains(output, "Nothing to undo.") || !strings.Contains(output, "Nothing to redo.") {
+		t.Fatalf("expected nothing to undo or redo, got:\n%s", output)
+	}
+}
+
+func TestNewChangeClearsRedo(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	output := runApp(t, store, "1\nfirst\n\n\n\nu\n1\nsecond\n\n\n\nr\nq\n")
+
+	if !strings.Contains(output, "Nothing to redo.") {
+		t.Fatalf("expected the redo stack to be cleared, got:\n%s", output)
+	}
+	if todos := listTodos(t, store); len(todos) != 1 || todos[0].Title != "second" {
+		t.Fatalf("expected only the second todo, got %+v", todos)
+	}
+}
+
+func TestUndoRecurringCompletion(t *testing.T) {
+	standup := todo.Todo{ID: 1, Title: "standup", DueDate: "2099-03-02", Recurrence: "FREQ=DAILY", Version: 1}
+	store := storage.NewMemoryStorage(standup)
+	output := runApp(t, store, "4\n1\nu\nq\n")
+
+	if !strings.Contains(output, "Undid the completion of #1.") {
+		t.Fatalf("expected undo message, got:\n%s", output)
+	}
+	todos := listTodos(t, store)
+	if len(todos) != 1 || todos[0].Completed {
+		t.Fatalf("expected only the reopened todo to be left, got %+v", todos)
+	}
+
+	// Redoing brings the same occurrence back rather than adding another.
+	store = storage.NewMemoryStorage(standup)
+	output = runApp(t, store, "4\n1\nu\nr\nq\n")
+	if !strings.Contains(output, "Redid the completion of #1.") {
+		t.Fatalf("expected redo message, got:\n%s", output)
+	}
+	todos = listTodos(t, store)
+	if len(todos) != 2 || !todos[0].Completed || todos[0].NextID != 2 || todos[1].DueDate != "2099-03-03" {
+		t.Fatalf("expected the completed todo and its next occurrence, got %+v", todos)
+	}
+}
+
+// meddlingStorage changes a todo's description elsewhere just before every
+// Update, as someone else might between a change and its undo.
+type meddlingStorage struct {
+	*storage.MemoryStorage
+}
+
+func (s meddlingStorage) Update(ctx context.Context, patch todo.Todo, mask []string) (todo.Todo, error) {
+	if _, err := s.MemoryStorage.EditDescription(context.Background(), patch.ID, "changed elsewhere"); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.MemoryStorage.Update(ctx, patch, mask)
+}
+
+func TestUndoConflict(t *testing.T) {
+	store := meddlingStorage{storage.NewMemoryStorage(todo.Todo{ID: 1, Title: "old", Version: 1})}
+	output := runApp(t, store, "6\n1\nt\nnew\nu\nu\nq\n")
+
+	if !strings.Contains(output, "Error: cannot undo the title edit of #1: todo 1: version conflict") {
+		t.Fatalf("expected conflict error, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Nothing to undo.") {
+		t.Fatalf("expected the failed change to be dropped, got:\n%s", output)
+	}
+	if got := listTodos(t, store)[0]; got.Title != "new" {
+		t.Fatalf("expected title to stay 'new', got %q", got.Title)
+	}
+}
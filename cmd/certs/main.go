@@ -0,0 +1,153 @@
+// Command certs creates a local certificate authority and issues the server
+// and client certificates the todo server and client need for TLS and
+// mutual TLS, so the whole flow can be tried offline.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amharshit45/todos-cli-/certs"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// hostList is a flag that may be given more than once.
+type hostList []string
+
+func (h *hostList) String() string { return strings.Join(*h, ",") }
+
+func (h *hostList) Set(host string) error {
+	*h = append(*h, host)
+	return nil
+}
+
+func main() {
+	dir := flag.String("dir", "tls", "directory to write certificates and keys to")
+	var hosts hostList
+	flag.Var(&hosts, "host", "DNS name or IP address the server certificate is valid for; repeat for more (default localhost, 127.0.0.1 and ::1)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [USER...]\n\n"+
+			"Creates a CA in DIR unless it already holds one, issues a server certificate,\n"+
+			"and issues a client certificate for each USER other than ca and server.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(hosts) == 0 {
+		hosts = hostList{"localhost", "127.0.0.1", "::1"}
+	}
+	if err := run(*dir, hosts, flag.Args(), os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Names of the pairs the CA and the server certificate are kept under.
+const (
+	caName     = "ca"
+	serverName = "server"
+)
+
+// checkUser reports whether a client certificate can be issued to user. Its
+// pair is kept next to the CA's and the server's, so the names of those are
+// refused rather than overwriting them.
+func checkUser(user string) error {
+	if err := todo.ValidateUser(user); err != nil {
+		return fmt.Errorf("user %q: %w", user, err)
+	}
+	if strings.EqualFold(user, caName) || strings.EqualFold(user, serverName) {
+		return fmt.Errorf("user %q: the name is taken by the %s certificate", user, strings.ToLower(user))
+	}
+	return nil
+}
+
+// run issues a server certificate for hosts and a client certificate for each
+// of users into dir, from the CA kept there, and reports the files it wrote
+// to out.
+func run(dir string, hosts, users []string, out io.Writer) error {
+	for _, user := range users {
+		if err := checkUser(user); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	ca, err := loadCA(dir, out)
+	if err != nil {
+		return err
+	}
+
+	certPEM, keyPEM, err := ca.IssueServer(hosts...)
+	if err != nil {
+		return err
+	}
+	if err := writePair(dir, serverName, certPEM, keyPEM, out); err != nil {
+		return err
+	}
+	for _, user := range users {
+		certPEM, keyPEM, err := ca.IssueClient(user)
+		if err != nil {
+			return err
+		}
+		if err := writePair(dir, user, certPEM, keyPEM, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCA returns the CA kept in dir, creating it first if dir holds none.
+func loadCA(dir string, out io.Writer) (*certs.CA, error) {
+	certPath, keyPath := pairPaths(dir, caName)
+	certPEM, err := os.ReadFile(certPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		ca, err := certs.NewCA("todos-cli local CA")
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := ca.KeyPEM()
+		if err != nil {
+			return nil, err
+		}
+		return ca, writePair(dir, caName, ca.CertPEM(), keyPEM, out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := certs.ParseCA(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certPath, err)
+	}
+	fmt.Fprintf(out, "Using the CA in %s\n", certPath)
+	return ca, nil
+}
+
+// pairPaths returns where the certificate and key called name are kept.
+func pairPaths(dir, name string) (certPath, keyPath string) {
+	return filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+}
+
+// writePair writes the certificate and key called name to dir, the key
+// readable only by its owner, and reports them to out.
+func writePair(dir, name string, certPEM, keyPEM []byte, out io.Writer) error {
+	certPath, keyPath := pairPaths(dir, name)
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s and %s\n", certPath, keyPath)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+// readCert parses the certificate kept under name in dir.
+func readCert(t *testing.T, dir, name string) *x509.Certificate {
+	t.Helper()
+	certPath, _ := pairPaths(dir, name)
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("%s holds no PEM block", certPath)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// fileMissing reports whether nothing exists at path.
+func fileMissing(path string) bool {
+	_, err := os.Stat(path)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := run(dir, []string{"localhost"}, []string{"alice"}, io.Discard); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(readCert(t, dir, caName))
+	alice := readCert(t, dir, "alice")
+	if alice.Subject.CommonName != "alice" {
+		t.Errorf("expected alice's certificate to name her, got %q", alice.Subject.CommonName)
+	}
+	if _, err := alice.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("expected alice's certificate to verify against the CA: %v", err)
+	}
+	if _, err := readCert(t, dir, serverName).Verify(x509.VerifyOptions{Roots: roots, DNSName: "localhost"}); err != nil {
+		t.Errorf("expected the server certificate to verify against the CA: %v", err)
+	}
+
+	// A second run reuses the CA, so certificates issued before still verify.
+	var out bytes.Buffer
+	if err := run(dir, []string{"localhost"}, []string{"bob"}, &out); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Using the CA in ")) {
+		t.Errorf("expected the CA reused, got: %s", out.String())
+	}
+	if _, err := readCert(t, dir, "bob").Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("expected bob's certificate to verify against the first CA: %v", err)
+	}
+}
+
+func TestRunReservedNames(t *testing.T) {
+	dir := t.TempDir()
+	if err := run(dir, []string{"localhost"}, nil, io.Discard); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	caPath, caKeyPath := pairPaths(dir, caName)
+	caPEM, _ := os.ReadFile(caPath)
+	caKeyPEM, _ := os.ReadFile(caKeyPath)
+
+	for _, user := range []string{"ca", "server", "CA"} {
+		if err := run(dir, []string{"localhost"}, []string{"alice", user}, io.Discard); err == nil {
+			t.Errorf("expected user %q refused", user)
+		}
+	}
+	if alicePath, _ := pairPaths(dir, "alice"); !fileMissing(alicePath) {
+		t.Error("expected nothing issued once a name is refused")
+	}
+	if got, _ := os.ReadFile(caPath); !bytes.Equal(got, caPEM) {
+		t.Error("expected the CA certificate untouched")
+	}
+	if got, _ := os.ReadFile(caKeyPath); !bytes.Equal(got, caKeyPEM) {
+		t.Error("expected the CA key untouched")
+	}
+}
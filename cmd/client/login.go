@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amharshit45/todos-cli-/cli"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// tokenPath returns where login keeps the token: todos-cli/token in the
+// user's configuration directory.
+func tokenPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "todos-cli", "token"), nil
+}
+
+// loadToken returns the token every call carries: $TODO_TOKEN, else the one
+// login saved, else "".
+func loadToken() (string, error) {
+	if token := os.Getenv("TODO_TOKEN"); token != "" {
+		return token, nil
+	}
+	path, err := tokenPath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+// readToken returns the token given on the login command line or, without
+// one, the first line of in.
+func readToken(args []string, in io.Reader, out io.Writer) (string, error) {
+	switch len(args) {
+	case 0:
+		fmt.Fprint(out, "API token: ")
+		scanner := bufio.NewScanner(in)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", errors.New("no token given")
+		}
+		if token := strings.TrimSpace(scanner.Text()); token != "" {
+			return token, nil
+		}
+		return "", errors.New("no token given")
+	case 1:
+		if !strings.HasPrefix(args[0], "-") {
+			return args[0], nil
+		}
+	}
+	return "", errors.New("usage: login [TOKEN]")
+}
+
+// login checks the token by listing projects with it, which every valid
+// token may do, and saves it for later runs. store must already send it.
+// It returns the process exit status, cli.ExitAuth for a rejected token.
+func login(ctx context.Context, store todo.Storage, token string, stdout, stderr io.Writer) int {
+	if _, err := store.ListProjects(ctx); err != nil {
+		fmt.Fprintf(stderr, "Login failed: %v\n", err)
+		if errors.Is(err, todo.ErrUnauthenticated) {
+			return cli.ExitAuth
+		}
+		return cli.ExitFailure
+	}
+	path, err := tokenPath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, []byte(token+"\n"), 0o600)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error saving token: %v\n", err)
+		return cli.ExitFailure
+	}
+	fmt.Fprintf(stdout, "Logged in; token saved to %s.\n", path)
+	return cli.ExitOK
+}
+
+// logout forgets the token login saved. $TODO_TOKEN, if set, still applies.
+func logout(stdout, stderr io.Writer) int {
+	path, err := tokenPath()
+	if err == nil {
+		err = os.Remove(path)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(stderr, "Error removing token: %v\n", err)
+		return cli.ExitFailure
+	}
+	fmt.Fprintln(stdout, "Logged out.")
+	return cli.ExitOK
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/cli"
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// configDir points the user's configuration directory at a fresh temporary
+// one and returns it.
+func configDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("TODO_TOKEN", "")
+	path, err := tokenPath()
+	if err != nil {
+		t.Fatalf("tokenPath: %v", err)
+	}
+	if !strings.HasPrefix(path, dir) {
+		t.Skipf("configuration directory %s is not under HOME", path)
+	}
+	return dir
+}
+
+// failingStorage rejects every call to ListProjects with err.
+type failingStorage struct {
+	todo.Storage
+	err error
+}
+
+func (s failingStorage) ListProjects(context.Context) ([]todo.Project, error) {
+	return nil, s.err
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name  string
+		store todo.Storage
+		want  int
+		saved bool
+	}{
+		{"valid token", storage.NewMemoryStorage(), cli.ExitOK, true},
+		{"rejected token", failingStorage{err: todo.ErrUnauthenticated}, cli.ExitAuth, false},
+		{"unreachable server", failingStorage{err: errors.New("connection refused")}, cli.ExitFailure, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir(t)
+			var out, errOut bytes.Buffer
+			if code := login(context.Background(), tt.store, "todo_secret", &out, &errOut); code != tt.want {
+				t.Fatalf("expected exit %d, got %d: %s", tt.want, code, errOut.String())
+			}
+			token, err := loadToken()
+			if err != nil {
+				t.Fatalf("loadToken: %v", err)
+			}
+			if saved := token == "todo_secret"; saved != tt.saved {
+				t.Fatalf("expected saved=%v, got token %q", tt.saved, token)
+			}
+			if !tt.saved && !strings.HasPrefix(errOut.String(), "Login failed: ") {
+				t.Errorf("expected the failure on stderr, got: %s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	configDir(t)
+	var out, errOut bytes.Buffer
+	if code := login(context.Background(), storage.NewMemoryStorage(), "todo_secret", &out, &errOut); code != cli.ExitOK {
+		t.Fatalf("login: exit %d: %s", code, errOut.String())
+	}
+	path, _ := tokenPath()
+	for name, want := range map[string]os.FileMode{path: 0o600, filepath.Dir(path): 0o700} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("expected %s to have mode %v, got %v", name, want, got)
+		}
+	}
+
+	// $TODO_TOKEN wins over the saved token.
+	t.Setenv("TODO_TOKEN", "todo_other")
+	if token, err := loadToken(); err != nil || token != "todo_other" {
+		t.Errorf("expected $TODO_TOKEN, got %q, %v", token, err)
+	}
+	t.Setenv("TODO_TOKEN", "")
+
+	// Logging out twice is fine.
+	for range 2 {
+		if code := logout(&out, &errOut); code != cli.ExitOK {
+			t.Fatalf("logout: exit %d: %s", code, errOut.String())
+		}
+	}
+	if token, err := loadToken(); err != nil || token != "" {
+		t.Errorf("expected no token after logout, got %q, %v", token, err)
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"argument", []string{"todo_arg"}, "", "todo_arg", false},
+		{"prompt", nil, "  todo_typed \n", "todo_typed", false},
+		{"empty line", nil, "\n", "", true},
+		{"no input", nil, "", "", true},
+		{"two arguments", []string{"a", "b"}, "", "", true},
+		{"help flag", []string{"-h"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := readToken(tt.args, strings.NewReader(tt.in), &out)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("expected %q (error %v), got %q, %v", tt.want, tt.wantErr, got, err)
+			}
+			if prompted := out.String() == "API token: "; prompted != (len(tt.args) == 0) {
+				t.Errorf("unexpected prompt %q", out.String())
+			}
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"localhost:50051", true},
+		{":50051", true},
+		{"127.0.0.1:50051", true},
+		{"[::1]:50051", true},
+		{"dns:///localhost:50051", true},
+		{"todo.example.com:50051", false},
+		{"dns:///todo.example.com:50051", false},
+		{"192.168.1.10:50051", false},
+		{"localhost.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopback(tt.addr); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
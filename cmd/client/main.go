@@ -4,16 +4,21 @@ import (
 	"bufio"
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/amharshit45/todos-cli-/certs"
 	"github.com/amharshit45/todos-cli-/cli"
 	"github.com/amharshit45/todos-cli-/grpcclient"
+	"github.com/amharshit45/todos-cli-/todo"
 )
 
 func main() {
@@ -26,8 +31,45 @@ func main() {
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	// On a server without authentication, every call names this user, and
+	// the server only shows it their own todos. Without one, the client
+	// works on the shared list.
+	ctx = todo.WithOwner(ctx, os.Getenv("TODO_USER"))
 
-	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	if command == "logout" {
+		os.Exit(logout(os.Stdout, os.Stderr))
+	}
+
+	// A server with authentication turned on rejects calls without the
+	// bearer token of an API key.
+	token, err := loadToken()
+	if command == "login" {
+		token, err = readToken(os.Args[2:], os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Error reading token: %v", err)
+	}
+	transport, err := transportCredentials()
+	if err != nil {
+		log.Fatalf("Error loading TLS certificates: %v", err)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+	switch {
+	case token == "":
+	case transport.Info().SecurityProtocol == "tls":
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcclient.TokenCredentials(token)))
+	case isLoopback(serverAddr):
+		// The token stays on this machine, so plaintext will do.
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcclient.LocalTokenCredentials(token)))
+	default:
+		log.Fatalf("Refusing to send the API token in plaintext to %s; set TLS_CA to connect with TLS", serverAddr)
+	}
+
+	conn, err := grpc.NewClient(serverAddr, opts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to server at %s: %v", serverAddr, err)
 	}
@@ -39,6 +81,22 @@ func main() {
 		}
 	}()
 
+	// With a subcommand, run it once and exit with its status instead of
+	// starting the interactive menu.
+	if command != "" {
+		var code int
+		if command == "login" {
+			code = login(ctx, store, token, os.Stdout, os.Stderr)
+		} else {
+			code = cli.Exec(ctx, store, os.Args, os.Stdout, os.Stderr)
+		}
+		if err := store.Close(ctx); err != nil {
+			log.Printf("Error closing connection: %v", err)
+		}
+		stop()
+		os.Exit(code)
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 	app := cli.New(store, scanner, os.Stdout)
 
@@ -46,3 +104,37 @@ func main() {
 		log.Fatalf("Error: %v", err)
 	}
 }
+
+// transportCredentials returns TLS credentials if $TLS_CA or
+// $TLS_CLIENT_CERT is set, and plaintext ones otherwise. Without $TLS_CA the
+// server's certificate is verified against the system's CAs; with
+// $TLS_CLIENT_CERT and $TLS_CLIENT_KEY the client presents that
+// certificate to a server requiring one.
+func transportCredentials() (credentials.TransportCredentials, error) {
+	caFile, certFile := os.Getenv("TLS_CA"), os.Getenv("TLS_CLIENT_CERT")
+	if caFile == "" && certFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	config, err := certs.ClientConfig(caFile, certFile, os.Getenv("TLS_CLIENT_KEY"))
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// isLoopback reports whether the dial target addr, e.g. "localhost:50051"
+// or "dns:///127.0.0.1:50051", names this machine.
+func isLoopback(addr string) bool {
+	if i := strings.LastIndex(addr, "/"); i >= 0 {
+		addr = addr[i+1:]
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "" || host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
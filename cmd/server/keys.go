@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/cli"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+const keysUsage = "Usage: server keys create USER | list | revoke ID"
+
+// runKeys runs the keys admin command with args, the words after "keys",
+// against keys and returns the process exit status, one of the client's.
+func runKeys(ctx context.Context, keys todo.KeyStore, args []string, stdout, stderr io.Writer) int {
+	err := keysCommand(ctx, keys, args, stdout)
+	if err == nil {
+		return cli.ExitOK
+	}
+	fmt.Fprintf(stderr, "Error: %v\n", err)
+	var usage usageError
+	switch {
+	case errors.As(err, &usage):
+		fmt.Fprintln(stderr, keysUsage)
+		return cli.ExitUsage
+	case errors.Is(err, todo.ErrKeyNotFound):
+		return cli.ExitNotFound
+	case errors.Is(err, todo.ErrInvalidUser):
+		return cli.ExitInvalid
+	}
+	return cli.ExitFailure
+}
+
+// usageError reports a malformed keys command line.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func keysCommand(ctx context.Context, keys todo.KeyStore, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("missing action")
+	}
+	switch action, args := args[0], args[1:]; action {
+	case "create":
+		if len(args) != 1 {
+			return usageError("create takes one user name")
+		}
+		token, hash, err := todo.NewToken()
+		if err != nil {
+			return err
+		}
+		key, err := keys.AddKey(ctx, args[0], hash)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Created API key %d for %s. Its token is shown only this once:\n%s\n", key.ID, key.User, token)
+	case "list":
+		if len(args) != 0 {
+			return usageError("list takes no arguments")
+		}
+		list, err := keys.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range list {
+			fmt.Fprintf(stdout, "%d. %s, created %s\n", key.ID, key.User, key.CreatedAt.Local().Format(time.DateTime))
+		}
+	case "revoke":
+		if len(args) != 1 {
+			return usageError("revoke takes one key ID")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return usageError(fmt.Sprintf("invalid key ID %q", args[0]))
+		}
+		if err := keys.RevokeKey(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Revoked API key %d.\n", id)
+	default:
+		return usageError(fmt.Sprintf("unknown action %q", action))
+	}
+	return nil
+}
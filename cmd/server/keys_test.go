@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/cli"
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func runKeysCmd(t *testing.T, keys todo.KeyStore, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = runKeys(context.Background(), keys, args, &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func TestKeys(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	ctx := context.Background()
+
+	out, stderr, code := runKeysCmd(t, store, "create", "alice")
+	if code != cli.ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", cli.ExitOK, code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if !strings.Contains(lines[0], "Created API key 1 for alice") {
+		t.Errorf("expected success message, got: %s", out)
+	}
+	token := lines[len(lines)-1]
+	if !strings.HasPrefix(token, todo.TokenPrefix) {
+		t.Fatalf("expected the token on the last line, got: %s", out)
+	}
+	key, err := store.KeyByHash(ctx, todo.HashToken(token))
+	if err != nil || key.User != "alice" {
+		t.Fatalf("expected the printed token to be alice's key, got %+v, %v", key, err)
+	}
+
+	if out, _, _ := runKeysCmd(t, store, "list"); !strings.Contains(out, "1. alice, created ") || strings.Contains(out, token) {
+		t.Errorf("expected the key listed without its token, got: %s", out)
+	}
+
+	if out, stderr, code := runKeysCmd(t, store, "revoke", "1"); code != cli.ExitOK || !strings.Contains(out, "Revoked API key 1.") {
+		t.Fatalf("expected exit %d and success message, got %d: %s%s", cli.ExitOK, code, out, stderr)
+	}
+	if _, err := store.KeyByHash(ctx, todo.HashToken(token)); !errors.Is(err, todo.ErrKeyNotFound) {
+		t.Errorf("expected the revoked key to be gone, got %v", err)
+	}
+	if out, _, _ := runKeysCmd(t, store, "list"); out != "" {
+		t.Errorf("expected no keys listed, got: %s", out)
+	}
+}
+
+func TestKeysExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no action", nil, cli.ExitUsage},
+		{"unknown action", []string{"frobnicate"}, cli.ExitUsage},
+		{"create without user", []string{"create"}, cli.ExitUsage},
+		{"create two users", []string{"create", "alice", "bob"}, cli.ExitUsage},
+		{"invalid user", []string{"create", "bad user"}, cli.ExitInvalid},
+		{"list with argument", []string{"list", "alice"}, cli.ExitUsage},
+		{"revoke without ID", []string{"revoke"}, cli.ExitUsage},
+		{"non-numeric ID", []string{"revoke", "one"}, cli.ExitUsage},
+		{"missing key", []string{"revoke", "99"}, cli.ExitNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, code := runKeysCmd(t, storage.NewMemoryStorage(), tt.args...)
+			if code != tt.want {
+				t.Errorf("expected exit %d, got %d: %s", tt.want, code, stderr)
+			}
+			if !strings.HasPrefix(stderr, "Error: ") {
+				t.Errorf("expected an error on stderr, got: %s", stderr)
+			}
+			if (code == cli.ExitUsage) != strings.Contains(stderr, keysUsage) {
+				t.Errorf("expected usage only for usage errors, got: %s", stderr)
+			}
+		})
+	}
+}
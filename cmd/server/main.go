@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net"
 	"os"
@@ -11,20 +14,33 @@ import (
 
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/amharshit45/todos-cli-/certs"
 	"github.com/amharshit45/todos-cli-/gen/todopb"
 	"github.com/amharshit45/todos-cli-/server"
 	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	mongoURI := os.Getenv("MONGO_URI")
-	mongoDB := os.Getenv("MONGO_DB")
-	if mongoURI == "" || mongoDB == "" {
-		log.Fatal("MONGO_URI and MONGO_DB must be set in environment")
+	driver := flag.String("storage", os.Getenv("STORAGE_DRIVER"),
+		"storage backend: mongo, sqlite or memory (defaults to $STORAGE_DRIVER, then mongo)")
+	noAuth := flag.Bool("no-auth", false,
+		"serve without API keys, trusting the user each call names; only for a server no one else can reach")
+	tlsCert := flag.String("tls-cert", os.Getenv("TLS_CERT"),
+		"server certificate file; with -tls-key, serves TLS (defaults to $TLS_CERT)")
+	tlsKey := flag.String("tls-key", os.Getenv("TLS_KEY"),
+		"server key file (defaults to $TLS_KEY)")
+	clientCA := flag.String("tls-client-ca", os.Getenv("TLS_CLIENT_CA"),
+		"CA certificates file; requires client certificates issued by them and authenticates calls by them (defaults to $TLS_CLIENT_CA)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [keys create USER | list | revoke ID]\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
 	listenAddr := os.Getenv("GRPC_ADDR")
 	if listenAddr == "" {
@@ -34,34 +50,128 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	store, err := storage.NewMongoStorage(ctx, mongoURI, mongoDB)
+	store, err := openStorage(ctx, *driver)
 	if err != nil {
-		log.Fatalf("Error connecting to MongoDB: %v", err)
+		log.Fatal(err)
 	}
-	defer func() {
+	closeStore := func() {
 		closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := store.Close(closeCtx); err != nil {
 			log.Printf("Error closing storage: %v", err)
 		}
-	}()
+	}
+	defer closeStore()
+
+	keys, persistent := store.(todo.KeyStore)
+	if _, ok := store.(*storage.MemoryStorage); ok {
+		// Keys kept in memory would be gone before anyone could use them.
+		persistent = false
+	}
+	if flag.Arg(0) == "keys" {
+		if !persistent {
+			log.Fatalf("The %s backend cannot keep API keys", *driver)
+		}
+		code := runKeys(ctx, keys, flag.Args()[1:], os.Stdout, os.Stderr)
+		closeStore()
+		os.Exit(code)
+	}
+	if flag.NArg() > 0 {
+		flag.Usage()
+		closeStore()
+		os.Exit(2)
+	}
+
+	var opts []grpc.ServerOption
+	if *tlsCert != "" || *tlsKey != "" || *clientCA != "" {
+		if *tlsCert == "" || *tlsKey == "" {
+			log.Fatal("TLS needs both a certificate and a key")
+		}
+		config, err := certs.ServerConfig(*tlsCert, *tlsKey, *clientCA)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(config)))
+	}
+
+	// Every call is scoped to the user its client certificate names, to the
+	// one its API key belongs to or, without authentication, to the one its
+	// metadata names.
+	unary, stream := server.UnaryInterceptor, server.StreamInterceptor
+	switch {
+	case *clientCA != "":
+		if *noAuth {
+			log.Fatal("-no-auth cannot be combined with client certificates, which authenticate every call")
+		}
+		unary, stream = server.CertUnaryInterceptor, server.CertStreamInterceptor
+	case *noAuth:
+		log.Println("Authentication is off; any caller can act as any user")
+	default:
+		if !persistent {
+			log.Fatalf("The %s backend cannot keep API keys; run with -no-auth", *driver)
+		}
+		auth := server.NewAuth(keys)
+		unary, stream = auth.UnaryInterceptor, auth.StreamInterceptor
+	}
 
 	lis, err := net.Listen("tcp", listenAddr)
 	if err != nil {
 		log.Fatalf("Failed to listen on %s: %v", listenAddr, err)
 	}
 
-	grpcServer := grpc.NewServer()
-	todopb.RegisterTodoServiceServer(grpcServer, server.New(store))
+	grpcServer := grpc.NewServer(append(opts,
+		grpc.ChainUnaryInterceptor(unary),
+		grpc.ChainStreamInterceptor(stream),
+	)...)
+	srv := server.New(store)
+	todopb.RegisterTodoServiceServer(grpcServer, srv)
 
 	go func() {
 		<-ctx.Done()
 		log.Println("Shutting down gRPC server...")
+		srv.Close()
 		grpcServer.GracefulStop()
 	}()
 
-	log.Printf("gRPC server listening on %s", listenAddr)
+	if opts != nil {
+		log.Printf("gRPC server listening on %s with TLS", listenAddr)
+	} else {
+		log.Printf("gRPC server listening on %s", listenAddr)
+	}
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// openStorage opens the named backend: "mongo" (the default), "sqlite" or
+// "memory".
+func openStorage(ctx context.Context, driver string) (todo.Storage, error) {
+	switch driver {
+	case "", "mongo":
+		mongoURI := os.Getenv("MONGO_URI")
+		mongoDB := os.Getenv("MONGO_DB")
+		if mongoURI == "" || mongoDB == "" {
+			return nil, errors.New("MONGO_URI and MONGO_DB must be set in environment")
+		}
+		store, err := storage.NewMongoStorage(ctx, mongoURI, mongoDB)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to MongoDB: %w", err)
+		}
+		return store, nil
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "todos.db"
+		}
+		store, err := storage.NewSQLiteStorage(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening SQLite database: %w", err)
+		}
+		return store, nil
+	case "memory":
+		log.Println("Using in-memory storage; todos will be lost on exit")
+		return storage.NewMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q (want mongo, sqlite or memory)", driver)
+	}
+}
@@ -9,6 +9,8 @@ package todopb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,31 +23,2204 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Priority ranks how pressing a todo is; higher values sort first.
+type Priority int32
+
+const (
+	Priority_PRIORITY_NONE   Priority = 0
+	Priority_PRIORITY_LOW    Priority = 1
+	Priority_PRIORITY_MEDIUM Priority = 2
+	Priority_PRIORITY_HIGH   Priority = 3
+	Priority_PRIORITY_URGENT Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_NONE",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_MEDIUM",
+		3: "PRIORITY_HIGH",
+		4: "PRIORITY_URGENT",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_NONE":   0,
+		"PRIORITY_LOW":    1,
+		"PRIORITY_MEDIUM": 2,
+		"PRIORITY_HIGH":   3,
+		"PRIORITY_URGENT": 4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_todo_v1_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_proto_todo_v1_todo_proto_enumTypes[0]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	EventType_EVENT_TYPE_CREATED     EventType = 1
+	EventType_EVENT_TYPE_UPDATED     EventType = 2
+	EventType_EVENT_TYPE_DELETED     EventType = 3
+	EventType_EVENT_TYPE_RESTORED    EventType = 4
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_CREATED",
+		2: "EVENT_TYPE_UPDATED",
+		3: "EVENT_TYPE_DELETED",
+		4: "EVENT_TYPE_RESTORED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED": 0,
+		"EVENT_TYPE_CREATED":     1,
+		"EVENT_TYPE_UPDATED":     2,
+		"EVENT_TYPE_DELETED":     3,
+		"EVENT_TYPE_RESTORED":    4,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_todo_v1_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_proto_todo_v1_todo_proto_enumTypes[1]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+// ErrorReason is the reason of the google.rpc.ErrorInfo attached to every
+// domain error returned by TodoService, whose domain is "todo.v1". The
+// ErrorInfo metadata carries the "id" of the todo the request addressed, if
+// any, and the limit a request exceeded, e.g. "max_title_length".
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED ErrorReason = 0
+	ErrorReason_NOT_FOUND                ErrorReason = 1
+	ErrorReason_ALREADY_COMPLETED        ErrorReason = 2
+	ErrorReason_ALREADY_INCOMPLETE       ErrorReason = 3
+	ErrorReason_TITLE_UNCHANGED          ErrorReason = 4
+	ErrorReason_DESCRIPTION_UNCHANGED    ErrorReason = 5
+	ErrorReason_DUE_UNCHANGED            ErrorReason = 6
+	ErrorReason_PRIORITY_UNCHANGED       ErrorReason = 7
+	ErrorReason_TAG_ALREADY_PRESENT      ErrorReason = 8
+	ErrorReason_TAG_NOT_PRESENT          ErrorReason = 9
+	ErrorReason_TOO_MANY_TAGS            ErrorReason = 10
+	ErrorReason_INVALID_ID               ErrorReason = 11
+	ErrorReason_EMPTY_TITLE              ErrorReason = 12
+	ErrorReason_TITLE_TOO_LONG           ErrorReason = 13
+	ErrorReason_DESCRIPTION_TOO_LONG     ErrorReason = 14
+	ErrorReason_INVALID_DUE_DATE         ErrorReason = 15
+	ErrorReason_INVALID_DUE_TIME         ErrorReason = 16
+	ErrorReason_DUE_TIME_WITHOUT_DATE    ErrorReason = 17
+	ErrorReason_INVALID_PRIORITY         ErrorReason = 18
+	ErrorReason_INVALID_TAG              ErrorReason = 19
+	ErrorReason_INVALID_FILTER           ErrorReason = 20
+	ErrorReason_INVALID_PAGE_SIZE        ErrorReason = 21
+	ErrorReason_INVALID_PAGE_TOKEN       ErrorReason = 22
+	ErrorReason_TODO_UNCHANGED           ErrorReason = 23
+	ErrorReason_INVALID_UPDATE_MASK      ErrorReason = 24
+	ErrorReason_VERSION_CONFLICT         ErrorReason = 25
+	ErrorReason_NOT_IN_TRASH             ErrorReason = 26
+	ErrorReason_PARENT_NOT_FOUND         ErrorReason = 27
+	ErrorReason_PARENT_CYCLE             ErrorReason = 28
+	ErrorReason_HAS_SUBTASKS             ErrorReason = 29
+	ErrorReason_BLOCKED                  ErrorReason = 30
+	ErrorReason_BLOCKER_NOT_FOUND        ErrorReason = 31
+	ErrorReason_DEPENDENCY_CYCLE         ErrorReason = 32
+	ErrorReason_DEPENDENCY_PRESENT       ErrorReason = 33
+	ErrorReason_DEPENDENCY_NOT_PRESENT   ErrorReason = 34
+	ErrorReason_INVALID_RECURRENCE       ErrorReason = 35
+	ErrorReason_PROJECT_NOT_FOUND        ErrorReason = 36
+	ErrorReason_PROJECT_NAME_TAKEN       ErrorReason = 37
+	ErrorReason_PROJECT_ARCHIVED         ErrorReason = 38
+	ErrorReason_PROJECT_NOT_EMPTY        ErrorReason = 39
+	ErrorReason_PROJECT_UNCHANGED        ErrorReason = 40
+	ErrorReason_INVALID_PROJECT_NAME     ErrorReason = 41
+	ErrorReason_UNAUTHENTICATED          ErrorReason = 42
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "NOT_FOUND",
+		2:  "ALREADY_COMPLETED",
+		3:  "ALREADY_INCOMPLETE",
+		4:  "TITLE_UNCHANGED",
+		5:  "DESCRIPTION_UNCHANGED",
+		6:  "DUE_UNCHANGED",
+		7:  "PRIORITY_UNCHANGED",
+		8:  "TAG_ALREADY_PRESENT",
+		9:  "TAG_NOT_PRESENT",
+		10: "TOO_MANY_TAGS",
+		11: "INVALID_ID",
+		12: "EMPTY_TITLE",
+		13: "TITLE_TOO_LONG",
+		14: "DESCRIPTION_TOO_LONG",
+		15: "INVALID_DUE_DATE",
+		16: "INVALID_DUE_TIME",
+		17: "DUE_TIME_WITHOUT_DATE",
+		18: "INVALID_PRIORITY",
+		19: "INVALID_TAG",
+		20: "INVALID_FILTER",
+		21: "INVALID_PAGE_SIZE",
+		22: "INVALID_PAGE_TOKEN",
+		23: "TODO_UNCHANGED",
+		24: "INVALID_UPDATE_MASK",
+		25: "VERSION_CONFLICT",
+		26: "NOT_IN_TRASH",
+		27: "PARENT_NOT_FOUND",
+		28: "PARENT_CYCLE",
+		29: "HAS_SUBTASKS",
+		30: "BLOCKED",
+		31: "BLOCKER_NOT_FOUND",
+		32: "DEPENDENCY_CYCLE",
+		33: "DEPENDENCY_PRESENT",
+		34: "DEPENDENCY_NOT_PRESENT",
+		35: "INVALID_RECURRENCE",
+		36: "PROJECT_NOT_FOUND",
+		37: "PROJECT_NAME_TAKEN",
+		38: "PROJECT_ARCHIVED",
+		39: "PROJECT_NOT_EMPTY",
+		40: "PROJECT_UNCHANGED",
+		41: "INVALID_PROJECT_NAME",
+		42: "UNAUTHENTICATED",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED": 0,
+		"NOT_FOUND":                1,
+		"ALREADY_COMPLETED":        2,
+		"ALREADY_INCOMPLETE":       3,
+		"TITLE_UNCHANGED":          4,
+		"DESCRIPTION_UNCHANGED":    5,
+		"DUE_UNCHANGED":            6,
+		"PRIORITY_UNCHANGED":       7,
+		"TAG_ALREADY_PRESENT":      8,
+		"TAG_NOT_PRESENT":          9,
+		"TOO_MANY_TAGS":            10,
+		"INVALID_ID":               11,
+		"EMPTY_TITLE":              12,
+		"TITLE_TOO_LONG":           13,
+		"DESCRIPTION_TOO_LONG":     14,
+		"INVALID_DUE_DATE":         15,
+		"INVALID_DUE_TIME":         16,
+		"DUE_TIME_WITHOUT_DATE":    17,
+		"INVALID_PRIORITY":         18,
+		"INVALID_TAG":              19,
+		"INVALID_FILTER":           20,
+		"INVALID_PAGE_SIZE":        21,
+		"INVALID_PAGE_TOKEN":       22,
+		"TODO_UNCHANGED":           23,
+		"INVALID_UPDATE_MASK":      24,
+		"VERSION_CONFLICT":         25,
+		"NOT_IN_TRASH":             26,
+		"PARENT_NOT_FOUND":         27,
+		"PARENT_CYCLE":             28,
+		"HAS_SUBTASKS":             29,
+		"BLOCKED":                  30,
+		"BLOCKER_NOT_FOUND":        31,
+		"DEPENDENCY_CYCLE":         32,
+		"DEPENDENCY_PRESENT":       33,
+		"DEPENDENCY_NOT_PRESENT":   34,
+		"INVALID_RECURRENCE":       35,
+		"PROJECT_NOT_FOUND":        36,
+		"PROJECT_NAME_TAKEN":       37,
+		"PROJECT_ARCHIVED":         38,
+		"PROJECT_NOT_EMPTY":        39,
+		"PROJECT_UNCHANGED":        40,
+		"INVALID_PROJECT_NAME":     41,
+		"UNAUTHENTICATED":          42,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_todo_v1_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_proto_todo_v1_todo_proto_enumTypes[2]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
 type Todo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Completed     bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Completed   bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	// due_date is an optional calendar date in YYYY-MM-DD form.
+	DueDate string `protobuf:"bytes,5,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	// due_time is an optional time of day in HH:MM form; it requires due_date.
+	DueTime  string   `protobuf:"bytes,6,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	Priority Priority `protobuf:"varint,7,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	Tags     []string `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// version starts at 1 and grows by one with every change to the todo. A
+	// request whose non-zero expected_version differs from it fails with
+	// ABORTED and reason VERSION_CONFLICT, so clients do not overwrite
+	// changes they have not seen.
+	Version int64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// deleted_at is set while the todo is in the trash.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// created_at is when the todo was added and updated_at when it last
+	// changed. Both are unset for todos stored before they were recorded.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// completed_at is when the todo was last completed; it is unset while the
+	// todo is incomplete.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// parent_id is the ID of the todo this one is a subtask of, or 0 for a
+	// top-level todo.
+	ParentId int32 `protobuf:"varint,14,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// blocked_by holds the IDs of the todos that must be completed before
+	// this one can be, in the order they were added.
+	BlockedBy []int32 `protobuf:"varint,15,rep,packed,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	// recurrence is the todo's recurrence rule in RFC 5545 RRULE syntax,
+	// e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", or empty for a one-off todo.
+	Recurrence string `protobuf:"bytes,16,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// next_id is the ID of the occurrence added when this recurring todo was
+	// completed, or 0 if it has not recurred.
+	NextId int32 `protobuf:"varint,17,opt,name=next_id,json=nextId,proto3" json:"next_id,omitempty"`
+	// project_id is the ID of the project the todo belongs to, or 0 for the
+	// Inbox. A subtask is always in its parent's project.
+	ProjectId int32 `protobuf:"varint,18,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// owner is the user the todo belongs to, as the server identified the
+	// caller that added it, or empty for the shared list.
+	Owner string `protobuf:"bytes,19,opt,name=owner,proto3" json:"owner,omitempty"`
+	// recurred is set on the todo a change returns when that change added its
+	// next occurrence, next_id.
+	Recurred      bool `protobuf:"varint,20,opt,name=recurred,proto3" json:"recurred,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Todo) Reset() {
+	*x = Todo{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Todo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Todo) ProtoMessage() {}
+
+func (x *Todo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
+func (*Todo) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Todo) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Todo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Todo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Todo) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Todo) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *Todo) GetDueTime() string {
+	if x != nil {
+		return x.DueTime
+	}
+	return ""
+}
+
+func (x *Todo) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (x *Todo) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Todo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Todo) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Todo) GetBlockedBy() []int32 {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *Todo) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Todo) GetNextId() int32 {
+	if x != nil {
+		return x.NextId
+	}
+	return 0
+}
+
+func (x *Todo) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *Todo) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Todo) GetRecurred() bool {
+	if x != nil {
+		return x.Recurred
+	}
+	return false
+}
+
+type AddRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	DueDate     string                 `protobuf:"bytes,3,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	DueTime     string                 `protobuf:"bytes,4,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	Priority    Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	Tags        []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// parent_id, if non-zero, adds the todo as a subtask of that todo.
+	ParentId int32 `protobuf:"varint,7,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// recurrence, if set, makes the todo recur by that RRULE.
+	Recurrence string `protobuf:"bytes,8,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// project_id, if non-zero, adds the todo to that project instead of the
+	// Inbox. The project must not be archived.
+	ProjectId     int32 `protobuf:"varint,9,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRequest) Reset() {
+	*x = AddRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRequest) ProtoMessage() {}
+
+func (x *AddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRequest.ProtoReflect.Descriptor instead.
+func (*AddRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AddRequest) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *AddRequest) GetDueTime() string {
+	if x != nil {
+		return x.DueTime
+	}
+	return ""
+}
+
+func (x *AddRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (x *AddRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddRequest) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *AddRequest) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *AddRequest) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+type AddResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the created todo, with its allocated ID.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddResponse) Reset() {
+	*x = AddResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddResponse) ProtoMessage() {}
+
+func (x *AddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddResponse.ProtoReflect.Descriptor instead.
+func (*AddResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tags, when set, restricts the result to todos carrying every listed tag.
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	// filter is an expression such as `completed=false AND title~"deploy"`;
+	// see package query for the full syntax.
+	Filter string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// page_size limits the number of todos returned; 0 returns every match.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of a previous response.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// trashed lists the todos in the trash instead of the live ones.
+	Trashed bool `protobuf:"varint,5,opt,name=trashed,proto3" json:"trashed,omitempty"`
+	// project_id restricts the result to the todos of that project; 0 lists
+	// the Inbox.
+	ProjectId     int32 `protobuf:"varint,6,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *ListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRequest) GetTrashed() bool {
+	if x != nil {
+		return x.Trashed
+	}
+	return false
+}
+
+func (x *ListRequest) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+type ListResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Todos []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+func (x *ListResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// cascade moves the todo's subtasks to the trash with it. Without it a
+	// todo with subtasks fails with FAILED_PRECONDITION and reason
+	// HAS_SUBTASKS.
+	Cascade       bool `protobuf:"varint,3,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+type DeleteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// deleted is the number of todos moved to the trash.
+	Deleted       int32 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteResponse) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type RestoreRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RestoreRequest) Reset() {
+	*x = RestoreRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRequest) ProtoMessage() {}
+
+func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestoreRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RestoreRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RestoreResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreResponse) Reset() {
+	*x = RestoreResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreResponse) ProtoMessage() {}
+
+func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreResponse.ProtoReflect.Descriptor instead.
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestoreResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type PurgeTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeTrashRequest) Reset() {
+	*x = PurgeTrashRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeTrashRequest) ProtoMessage() {}
+
+func (x *PurgeTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeTrashRequest.ProtoReflect.Descriptor instead.
+func (*PurgeTrashRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+}
+
+type PurgeTrashResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// purged is how many todos were permanently removed.
+	Purged        int32 `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeTrashResponse) Reset() {
+	*x = PurgeTrashResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeTrashResponse) ProtoMessage() {}
+
+func (x *PurgeTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeTrashResponse.ProtoReflect.Descriptor instead.
+func (*PurgeTrashResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PurgeTrashResponse) GetPurged() int32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+type SetCompletedRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Completed bool                   `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetCompletedRequest) Reset() {
+	*x = SetCompletedRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCompletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCompletedRequest) ProtoMessage() {}
+
+func (x *SetCompletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCompletedRequest.ProtoReflect.Descriptor instead.
+func (*SetCompletedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetCompletedRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SetCompletedRequest) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *SetCompletedRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type SetCompletedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCompletedResponse) Reset() {
+	*x = SetCompletedResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCompletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCompletedResponse) ProtoMessage() {}
+
+func (x *SetCompletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCompletedResponse.ProtoReflect.Descriptor instead.
+func (*SetCompletedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetCompletedResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type EditTitleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EditTitleRequest) Reset() {
+	*x = EditTitleRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditTitleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditTitleRequest) ProtoMessage() {}
+
+func (x *EditTitleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditTitleRequest.ProtoReflect.Descriptor instead.
+func (*EditTitleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EditTitleRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditTitleRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *EditTitleRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type EditTitleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditTitleResponse) Reset() {
+	*x = EditTitleResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditTitleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditTitleResponse) ProtoMessage() {}
+
+func (x *EditTitleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditTitleResponse.ProtoReflect.Descriptor instead.
+func (*EditTitleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EditTitleResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type EditDescriptionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EditDescriptionRequest) Reset() {
+	*x = EditDescriptionRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditDescriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditDescriptionRequest) ProtoMessage() {}
+
+func (x *EditDescriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditDescriptionRequest.ProtoReflect.Descriptor instead.
+func (*EditDescriptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EditDescriptionRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditDescriptionRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EditDescriptionRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type EditDescriptionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditDescriptionResponse) Reset() {
+	*x = EditDescriptionResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditDescriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditDescriptionResponse) ProtoMessage() {}
+
+func (x *EditDescriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditDescriptionResponse.ProtoReflect.Descriptor instead.
+func (*EditDescriptionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EditDescriptionResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type EditDueRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DueDate string                 `protobuf:"bytes,2,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	DueTime string                 `protobuf:"bytes,3,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EditDueRequest) Reset() {
+	*x = EditDueRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditDueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditDueRequest) ProtoMessage() {}
+
+func (x *EditDueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditDueRequest.ProtoReflect.Descriptor instead.
+func (*EditDueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EditDueRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditDueRequest) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *EditDueRequest) GetDueTime() string {
+	if x != nil {
+		return x.DueTime
+	}
+	return ""
+}
+
+func (x *EditDueRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type EditDueResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditDueResponse) Reset() {
+	*x = EditDueResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditDueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditDueResponse) ProtoMessage() {}
+
+func (x *EditDueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditDueResponse.ProtoReflect.Descriptor instead.
+func (*EditDueResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EditDueResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type EditPriorityRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Priority Priority               `protobuf:"varint,2,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EditPriorityRequest) Reset() {
+	*x = EditPriorityRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditPriorityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditPriorityRequest) ProtoMessage() {}
+
+func (x *EditPriorityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditPriorityRequest.ProtoReflect.Descriptor instead.
+func (*EditPriorityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EditPriorityRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditPriorityRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (x *EditPriorityRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type EditPriorityResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditPriorityResponse) Reset() {
+	*x = EditPriorityResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditPriorityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditPriorityResponse) ProtoMessage() {}
+
+func (x *EditPriorityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditPriorityResponse.ProtoReflect.Descriptor instead.
+func (*EditPriorityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EditPriorityResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type AddTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tag   string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddTagRequest) Reset() {
+	*x = AddTagRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTagRequest) ProtoMessage() {}
+
+func (x *AddTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTagRequest.ProtoReflect.Descriptor instead.
+func (*AddTagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AddTagRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddTagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *AddTagRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AddTagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTagResponse) Reset() {
+	*x = AddTagResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTagResponse) ProtoMessage() {}
+
+func (x *AddTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTagResponse.ProtoReflect.Descriptor instead.
+func (*AddTagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AddTagResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type RemoveTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tag   string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveTagRequest) Reset() {
+	*x = RemoveTagRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTagRequest) ProtoMessage() {}
+
+func (x *RemoveTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTagRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RemoveTagRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RemoveTagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *RemoveTagRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveTagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTagResponse) Reset() {
+	*x = RemoveTagResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTagResponse) ProtoMessage() {}
+
+func (x *RemoveTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTagResponse.ProtoReflect.Descriptor instead.
+func (*RemoveTagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RemoveTagResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type AddDependencyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the todo to block.
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// blocker_id is the todo that must be completed first.
+	BlockerId int32 `protobuf:"varint,2,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	// expected_version, if non-zero, must equal the version of todo id.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddDependencyRequest) Reset() {
+	*x = AddDependencyRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDependencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDependencyRequest) ProtoMessage() {}
+
+func (x *AddDependencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDependencyRequest.ProtoReflect.Descriptor instead.
+func (*AddDependencyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AddDependencyRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddDependencyRequest) GetBlockerId() int32 {
+	if x != nil {
+		return x.BlockerId
+	}
+	return 0
+}
+
+func (x *AddDependencyRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AddDependencyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the blocked todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDependencyResponse) Reset() {
+	*x = AddDependencyResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDependencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDependencyResponse) ProtoMessage() {}
+
+func (x *AddDependencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDependencyResponse.ProtoReflect.Descriptor instead.
+func (*AddDependencyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AddDependencyResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type RemoveDependencyRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BlockerId int32                  `protobuf:"varint,2,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	// expected_version, if non-zero, must equal the version of todo id.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveDependencyRequest) Reset() {
+	*x = RemoveDependencyRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDependencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDependencyRequest) ProtoMessage() {}
+
+func (x *RemoveDependencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDependencyRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDependencyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RemoveDependencyRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RemoveDependencyRequest) GetBlockerId() int32 {
+	if x != nil {
+		return x.BlockerId
+	}
+	return 0
+}
+
+func (x *RemoveDependencyRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveDependencyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDependencyResponse) Reset() {
+	*x = RemoveDependencyResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDependencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDependencyResponse) ProtoMessage() {}
+
+func (x *RemoveDependencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDependencyResponse.ProtoReflect.Descriptor instead.
+func (*RemoveDependencyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RemoveDependencyResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo carries the ID of the todo to update and the new field values.
+	Todo *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	// update_mask names the fields of todo to apply, e.g. "title" and
+	// "description". due_date and due_time must be named together; id cannot
+	// be named.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// expected_version, if non-zero, must equal the todo's version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateRequest) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo is the todo as stored after the change.
+	Todo          *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+// Project groups todos. The Inbox, with ID 0, holds every todo not added
+// to another project; it is built in and never listed.
+type Project struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// archived projects keep their todos but take no new ones.
+	Archived bool `protobuf:"varint,3,opt,name=archived,proto3" json:"archived,omitempty"`
+	// owner is the user the project belongs to; see Todo.owner.
+	Owner         string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Todo) Reset() {
-	*x = Todo{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[0]
+func (x *Project) Reset() {
+	*x = Project{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Todo) String() string {
+func (x *Project) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Todo) ProtoMessage() {}
+func (*Project) ProtoMessage() {}
 
-func (x *Todo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[0]
+func (x *Project) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,62 +2231,62 @@ func (x *Todo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
-func (*Todo) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use Project.ProtoReflect.Descriptor instead.
+func (*Project) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *Todo) GetId() int32 {
+func (x *Project) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *Todo) GetTitle() string {
+func (x *Project) GetName() string {
 	if x != nil {
-		return x.Title
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Todo) GetDescription() string {
+func (x *Project) GetArchived() bool {
 	if x != nil {
-		return x.Description
+		return x.Archived
 	}
-	return ""
+	return false
 }
 
-func (x *Todo) GetCompleted() bool {
+func (x *Project) GetOwner() string {
 	if x != nil {
-		return x.Completed
+		return x.Owner
 	}
-	return false
+	return ""
 }
 
-type AddRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+type CreateProjectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name must be unique regardless of case.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddRequest) Reset() {
-	*x = AddRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[1]
+func (x *CreateProjectRequest) Reset() {
+	*x = CreateProjectRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddRequest) String() string {
+func (x *CreateProjectRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddRequest) ProtoMessage() {}
+func (*CreateProjectRequest) ProtoMessage() {}
 
-func (x *AddRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[1]
+func (x *CreateProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -122,46 +2297,41 @@ func (x *AddRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddRequest.ProtoReflect.Descriptor instead.
-func (*AddRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *AddRequest) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
+// Deprecated: Use CreateProjectRequest.ProtoReflect.Descriptor instead.
+func (*CreateProjectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *AddRequest) GetDescription() string {
+func (x *CreateProjectRequest) GetName() string {
 	if x != nil {
-		return x.Description
+		return x.Name
 	}
 	return ""
 }
 
-type AddResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type CreateProjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// project is the created project, with its allocated ID.
+	Project       *Project `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddResponse) Reset() {
-	*x = AddResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[2]
+func (x *CreateProjectResponse) Reset() {
+	*x = CreateProjectResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddResponse) String() string {
+func (x *CreateProjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddResponse) ProtoMessage() {}
+func (*CreateProjectResponse) ProtoMessage() {}
 
-func (x *AddResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[2]
+func (x *CreateProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -172,32 +2342,39 @@ func (x *AddResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddResponse.ProtoReflect.Descriptor instead.
-func (*AddResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use CreateProjectResponse.ProtoReflect.Descriptor instead.
+func (*CreateProjectResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{33}
 }
 
-type ListRequest struct {
+func (x *CreateProjectResponse) GetProject() *Project {
+	if x != nil {
+		return x.Project
+	}
+	return nil
+}
+
+type ListProjectsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRequest) Reset() {
-	*x = ListRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[3]
+func (x *ListProjectsRequest) Reset() {
+	*x = ListProjectsRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRequest) String() string {
+func (x *ListProjectsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequest) ProtoMessage() {}
+func (*ListProjectsRequest) ProtoMessage() {}
 
-func (x *ListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[3]
+func (x *ListProjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,33 +2385,34 @@ func (x *ListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
-func (*ListRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListProjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListProjectsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{34}
 }
 
-type ListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+type ListProjectsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// projects are ordered by ID.
+	Projects      []*Project `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListResponse) Reset() {
-	*x = ListResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[4]
+func (x *ListProjectsResponse) Reset() {
+	*x = ListProjectsResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListResponse) String() string {
+func (x *ListProjectsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListResponse) ProtoMessage() {}
+func (*ListProjectsResponse) ProtoMessage() {}
 
-func (x *ListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[4]
+func (x *ListProjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,40 +2423,41 @@ func (x *ListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListProjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListProjectsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *ListResponse) GetTodos() []*Todo {
+func (x *ListProjectsResponse) GetProjects() []*Project {
 	if x != nil {
-		return x.Todos
+		return x.Projects
 	}
 	return nil
 }
 
-type DeleteRequest struct {
+type RenameProjectRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[5]
+func (x *RenameProjectRequest) Reset() {
+	*x = RenameProjectRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *RenameProjectRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*RenameProjectRequest) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[5]
+func (x *RenameProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -289,39 +2468,48 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RenameProjectRequest.ProtoReflect.Descriptor instead.
+func (*RenameProjectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *DeleteRequest) GetId() int32 {
+func (x *RenameProjectRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type DeleteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *RenameProjectRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameProjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// project is the project as stored after the change.
+	Project       *Project `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[6]
+func (x *RenameProjectResponse) Reset() {
+	*x = RenameProjectResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteResponse) String() string {
+func (x *RenameProjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*RenameProjectResponse) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[6]
+func (x *RenameProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,34 +2520,42 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RenameProjectResponse.ProtoReflect.Descriptor instead.
+func (*RenameProjectResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{37}
 }
 
-type SetCompletedRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Completed     bool                   `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+func (x *RenameProjectResponse) GetProject() *Project {
+	if x != nil {
+		return x.Project
+	}
+	return nil
+}
+
+type ArchiveProjectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// archived archives the project when true and unarchives it when false.
+	Archived      bool `protobuf:"varint,2,opt,name=archived,proto3" json:"archived,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetCompletedRequest) Reset() {
-	*x = SetCompletedRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[7]
+func (x *ArchiveProjectRequest) Reset() {
+	*x = ArchiveProjectRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetCompletedRequest) String() string {
+func (x *ArchiveProjectRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetCompletedRequest) ProtoMessage() {}
+func (*ArchiveProjectRequest) ProtoMessage() {}
 
-func (x *SetCompletedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[7]
+func (x *ArchiveProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -370,46 +2566,48 @@ func (x *SetCompletedRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetCompletedRequest.ProtoReflect.Descriptor instead.
-func (*SetCompletedRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ArchiveProjectRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveProjectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *SetCompletedRequest) GetId() int32 {
+func (x *ArchiveProjectRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *SetCompletedRequest) GetCompleted() bool {
+func (x *ArchiveProjectRequest) GetArchived() bool {
 	if x != nil {
-		return x.Completed
+		return x.Archived
 	}
 	return false
 }
 
-type SetCompletedResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type ArchiveProjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// project is the project as stored after the change.
+	Project       *Project `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetCompletedResponse) Reset() {
-	*x = SetCompletedResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[8]
+func (x *ArchiveProjectResponse) Reset() {
+	*x = ArchiveProjectResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetCompletedResponse) String() string {
+func (x *ArchiveProjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetCompletedResponse) ProtoMessage() {}
+func (*ArchiveProjectResponse) ProtoMessage() {}
 
-func (x *SetCompletedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[8]
+func (x *ArchiveProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -420,34 +2618,40 @@ func (x *SetCompletedResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetCompletedResponse.ProtoReflect.Descriptor instead.
-func (*SetCompletedResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ArchiveProjectResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveProjectResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{39}
 }
 
-type EditTitleRequest struct {
+func (x *ArchiveProjectResponse) GetProject() *Project {
+	if x != nil {
+		return x.Project
+	}
+	return nil
+}
+
+type DeleteProjectRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EditTitleRequest) Reset() {
-	*x = EditTitleRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[9]
+func (x *DeleteProjectRequest) Reset() {
+	*x = DeleteProjectRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EditTitleRequest) String() string {
+func (x *DeleteProjectRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditTitleRequest) ProtoMessage() {}
+func (*DeleteProjectRequest) ProtoMessage() {}
 
-func (x *EditTitleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[9]
+func (x *DeleteProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,46 +2662,39 @@ func (x *EditTitleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditTitleRequest.ProtoReflect.Descriptor instead.
-func (*EditTitleRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use DeleteProjectRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProjectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *EditTitleRequest) GetId() int32 {
+func (x *DeleteProjectRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *EditTitleRequest) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
-}
-
-type EditTitleResponse struct {
+type DeleteProjectResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EditTitleResponse) Reset() {
-	*x = EditTitleResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[10]
+func (x *DeleteProjectResponse) Reset() {
+	*x = DeleteProjectResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EditTitleResponse) String() string {
+func (x *DeleteProjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditTitleResponse) ProtoMessage() {}
+func (*DeleteProjectResponse) ProtoMessage() {}
 
-func (x *EditTitleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[10]
+func (x *DeleteProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,34 +2705,32 @@ func (x *EditTitleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditTitleResponse.ProtoReflect.Descriptor instead.
-func (*EditTitleResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use DeleteProjectResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProjectResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{41}
 }
 
-type EditDescriptionRequest struct {
+type WatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EditDescriptionRequest) Reset() {
-	*x = EditDescriptionRequest{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[11]
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EditDescriptionRequest) String() string {
+func (x *WatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditDescriptionRequest) ProtoMessage() {}
+func (*WatchRequest) ProtoMessage() {}
 
-func (x *EditDescriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[11]
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -546,46 +2741,35 @@ func (x *EditDescriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditDescriptionRequest.ProtoReflect.Descriptor instead.
-func (*EditDescriptionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *EditDescriptionRequest) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *EditDescriptionRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{42}
 }
 
-type EditDescriptionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type WatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=todo.v1.EventType" json:"type,omitempty"`
+	// id is the affected todo.
+	Id            int32 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EditDescriptionResponse) Reset() {
-	*x = EditDescriptionResponse{}
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[12]
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EditDescriptionResponse) String() string {
+func (x *WatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditDescriptionResponse) ProtoMessage() {}
+func (*WatchResponse) ProtoMessage() {}
 
-func (x *EditDescriptionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_todo_v1_todo_proto_msgTypes[12]
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_todo_v1_todo_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,51 +2780,275 @@ func (x *EditDescriptionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditDescriptionResponse.ProtoReflect.Descriptor instead.
-func (*EditDescriptionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_todo_v1_todo_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WatchResponse) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
 }
 
 var File_proto_todo_v1_todo_proto protoreflect.FileDescriptor
 
 const file_proto_todo_v1_todo_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/todo/v1/todo.proto\x12\atodo.v1\"l\n" +
+	"\x18proto/todo/v1/todo.proto\x12\atodo.v1\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb5\x05\n" +
 	"\x04Todo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1c\n" +
-	"\tcompleted\x18\x04 \x01(\bR\tcompleted\"D\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x12\x19\n" +
+	"\bdue_date\x18\x05 \x01(\tR\adueDate\x12\x19\n" +
+	"\bdue_time\x18\x06 \x01(\tR\adueTime\x12-\n" +
+	"\bpriority\x18\a \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x1b\n" +
+	"\tparent_id\x18\x0e \x01(\x05R\bparentId\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x0f \x03(\x05R\tblockedBy\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\x10 \x01(\tR\n" +
+	"recurrence\x12\x17\n" +
+	"\anext_id\x18\x11 \x01(\x05R\x06nextId\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x12 \x01(\x05R\tprojectId\x12\x14\n" +
+	"\x05owner\x18\x13 \x01(\tR\x05owner\x12\x1a\n" +
+	"\brecurred\x18\x14 \x01(\bR\brecurred\"\x99\x02\n" +
 	"\n" +
 	"AddRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"\r\n" +
-	"\vAddResponse\"\r\n" +
-	"\vListRequest\"3\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x19\n" +
+	"\bdue_date\x18\x03 \x01(\tR\adueDate\x12\x19\n" +
+	"\bdue_time\x18\x04 \x01(\tR\adueTime\x12-\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x1b\n" +
+	"\tparent_id\x18\a \x01(\x05R\bparentId\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\b \x01(\tR\n" +
+	"recurrence\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\t \x01(\x05R\tprojectId\"0\n" +
+	"\vAddResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\xae\x01\n" +
+	"\vListRequest\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06filter\x18\x02 \x01(\tR\x06filter\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x18\n" +
+	"\atrashed\x18\x05 \x01(\bR\atrashed\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x06 \x01(\x05R\tprojectId\"[\n" +
 	"\fListResponse\x12#\n" +
-	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\"\x1f\n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"d\n" +
 	"\rDeleteRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"\x10\n" +
-	"\x0eDeleteResponse\"C\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\x12\x18\n" +
+	"\acascade\x18\x03 \x01(\bR\acascade\"*\n" +
+	"\x0eDeleteResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x05R\adeleted\"K\n" +
+	"\x0eRestoreRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"4\n" +
+	"\x0fRestoreResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\x13\n" +
+	"\x11PurgeTrashRequest\",\n" +
+	"\x12PurgeTrashResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x05R\x06purged\"n\n" +
 	"\x13SetCompletedRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1c\n" +
-	"\tcompleted\x18\x02 \x01(\bR\tcompleted\"\x16\n" +
-	"\x14SetCompletedResponse\"8\n" +
+	"\tcompleted\x18\x02 \x01(\bR\tcompleted\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"9\n" +
+	"\x14SetCompletedResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"c\n" +
 	"\x10EditTitleRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\"\x13\n" +
-	"\x11EditTitleResponse\"J\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"6\n" +
+	"\x11EditTitleResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"u\n" +
 	"\x16EditDescriptionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"\x19\n" +
-	"\x17EditDescriptionResponse2\x96\x03\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"<\n" +
+	"\x17EditDescriptionResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\x81\x01\n" +
+	"\x0eEditDueRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
+	"\bdue_date\x18\x02 \x01(\tR\adueDate\x12\x19\n" +
+	"\bdue_time\x18\x03 \x01(\tR\adueTime\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"4\n" +
+	"\x0fEditDueResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\x7f\n" +
+	"\x13EditPriorityRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12-\n" +
+	"\bpriority\x18\x02 \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"9\n" +
+	"\x14EditPriorityResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\\\n" +
+	"\rAddTagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"3\n" +
+	"\x0eAddTagResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"_\n" +
+	"\x10RemoveTagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"6\n" +
+	"\x11RemoveTagResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"p\n" +
+	"\x14AddDependencyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x02 \x01(\x05R\tblockerId\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\":\n" +
+	"\x15AddDependencyResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"s\n" +
+	"\x17RemoveDependencyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x02 \x01(\x05R\tblockerId\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"=\n" +
+	"\x18RemoveDependencyResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\x9a\x01\n" +
+	"\rUpdateRequest\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"3\n" +
+	"\x0eUpdateResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"_\n" +
+	"\aProject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\barchived\x18\x03 \x01(\bR\barchived\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\"*\n" +
+	"\x14CreateProjectRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"C\n" +
+	"\x15CreateProjectResponse\x12*\n" +
+	"\aproject\x18\x01 \x01(\v2\x10.todo.v1.ProjectR\aproject\"\x15\n" +
+	"\x13ListProjectsRequest\"D\n" +
+	"\x14ListProjectsResponse\x12,\n" +
+	"\bprojects\x18\x01 \x03(\v2\x10.todo.v1.ProjectR\bprojects\":\n" +
+	"\x14RenameProjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"C\n" +
+	"\x15RenameProjectResponse\x12*\n" +
+	"\aproject\x18\x01 \x01(\v2\x10.todo.v1.ProjectR\aproject\"C\n" +
+	"\x15ArchiveProjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1a\n" +
+	"\barchived\x18\x02 \x01(\bR\barchived\"D\n" +
+	"\x16ArchiveProjectResponse\x12*\n" +
+	"\aproject\x18\x01 \x01(\v2\x10.todo.v1.ProjectR\aproject\"&\n" +
+	"\x14DeleteProjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x17\n" +
+	"\x15DeleteProjectResponse\"\x0e\n" +
+	"\fWatchRequest\"G\n" +
+	"\rWatchResponse\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.todo.v1.EventTypeR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id*l\n" +
+	"\bPriority\x12\x11\n" +
+	"\rPRIORITY_NONE\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_MEDIUM\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x03\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x04*\x88\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12EVENT_TYPE_CREATED\x10\x01\x12\x16\n" +
+	"\x12EVENT_TYPE_UPDATED\x10\x02\x12\x16\n" +
+	"\x12EVENT_TYPE_DELETED\x10\x03\x12\x17\n" +
+	"\x13EVENT_TYPE_RESTORED\x10\x04*\xbc\a\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tNOT_FOUND\x10\x01\x12\x15\n" +
+	"\x11ALREADY_COMPLETED\x10\x02\x12\x16\n" +
+	"\x12ALREADY_INCOMPLETE\x10\x03\x12\x13\n" +
+	"\x0fTITLE_UNCHANGED\x10\x04\x12\x19\n" +
+	"\x15DESCRIPTION_UNCHANGED\x10\x05\x12\x11\n" +
+	"\rDUE_UNCHANGED\x10\x06\x12\x16\n" +
+	"\x12PRIORITY_UNCHANGED\x10\a\x12\x17\n" +
+	"\x13TAG_ALREADY_PRESENT\x10\b\x12\x13\n" +
+	"\x0fTAG_NOT_PRESENT\x10\t\x12\x11\n" +
+	"\rTOO_MANY_TAGS\x10\n" +
+	"\x12\x0e\n" +
+	"\n" +
+	"INVALID_ID\x10\v\x12\x0f\n" +
+	"\vEMPTY_TITLE\x10\f\x12\x12\n" +
+	"\x0eTITLE_TOO_LONG\x10\r\x12\x18\n" +
+	"\x14DESCRIPTION_TOO_LONG\x10\x0e\x12\x14\n" +
+	"\x10INVALID_DUE_DATE\x10\x0f\x12\x14\n" +
+	"\x10INVALID_DUE_TIME\x10\x10\x12\x19\n" +
+	"\x15DUE_TIME_WITHOUT_DATE\x10\x11\x12\x14\n" +
+	"\x10INVALID_PRIORITY\x10\x12\x12\x0f\n" +
+	"\vINVALID_TAG\x10\x13\x12\x12\n" +
+	"\x0eINVALID_FILTER\x10\x14\x12\x15\n" +
+	"\x11INVALID_PAGE_SIZE\x10\x15\x12\x16\n" +
+	"\x12INVALID_PAGE_TOKEN\x10\x16\x12\x12\n" +
+	"\x0eTODO_UNCHANGED\x10\x17\x12\x17\n" +
+	"\x13INVALID_UPDATE_MASK\x10\x18\x12\x14\n" +
+	"\x10VERSION_CONFLICT\x10\x19\x12\x10\n" +
+	"\fNOT_IN_TRASH\x10\x1a\x12\x14\n" +
+	"\x10PARENT_NOT_FOUND\x10\x1b\x12\x10\n" +
+	"\fPARENT_CYCLE\x10\x1c\x12\x10\n" +
+	"\fHAS_SUBTASKS\x10\x1d\x12\v\n" +
+	"\aBLOCKED\x10\x1e\x12\x15\n" +
+	"\x11BLOCKER_NOT_FOUND\x10\x1f\x12\x14\n" +
+	"\x10DEPENDENCY_CYCLE\x10 \x12\x16\n" +
+	"\x12DEPENDENCY_PRESENT\x10!\x12\x1a\n" +
+	"\x16DEPENDENCY_NOT_PRESENT\x10\"\x12\x16\n" +
+	"\x12INVALID_RECURRENCE\x10#\x12\x15\n" +
+	"\x11PROJECT_NOT_FOUND\x10$\x12\x16\n" +
+	"\x12PROJECT_NAME_TAKEN\x10%\x12\x14\n" +
+	"\x10PROJECT_ARCHIVED\x10&\x12\x15\n" +
+	"\x11PROJECT_NOT_EMPTY\x10'\x12\x15\n" +
+	"\x11PROJECT_UNCHANGED\x10(\x12\x18\n" +
+	"\x14INVALID_PROJECT_NAME\x10)\x12\x13\n" +
+	"\x0fUNAUTHENTICATED\x10*2\xd3\v\n" +
 	"\vTodoService\x120\n" +
 	"\x03Add\x12\x13.todo.v1.AddRequest\x1a\x14.todo.v1.AddResponse\x123\n" +
 	"\x04List\x12\x14.todo.v1.ListRequest\x1a\x15.todo.v1.ListResponse\x129\n" +
-	"\x06Delete\x12\x16.todo.v1.DeleteRequest\x1a\x17.todo.v1.DeleteResponse\x12K\n" +
+	"\x06Delete\x12\x16.todo.v1.DeleteRequest\x1a\x17.todo.v1.DeleteResponse\x12<\n" +
+	"\aRestore\x12\x17.todo.v1.RestoreRequest\x1a\x18.todo.v1.RestoreResponse\x12E\n" +
+	"\n" +
+	"PurgeTrash\x12\x1a.todo.v1.PurgeTrashRequest\x1a\x1b.todo.v1.PurgeTrashResponse\x12K\n" +
 	"\fSetCompleted\x12\x1c.todo.v1.SetCompletedRequest\x1a\x1d.todo.v1.SetCompletedResponse\x12B\n" +
 	"\tEditTitle\x12\x19.todo.v1.EditTitleRequest\x1a\x1a.todo.v1.EditTitleResponse\x12T\n" +
-	"\x0fEditDescription\x12\x1f.todo.v1.EditDescriptionRequest\x1a .todo.v1.EditDescriptionResponseB.Z,github.com/amharshit45/todos-cli-/gen/todopbb\x06proto3"
+	"\x0fEditDescription\x12\x1f.todo.v1.EditDescriptionRequest\x1a .todo.v1.EditDescriptionResponse\x12<\n" +
+	"\aEditDue\x12\x17.todo.v1.EditDueRequest\x1a\x18.todo.v1.EditDueResponse\x12K\n" +
+	"\fEditPriority\x12\x1c.todo.v1.EditPriorityRequest\x1a\x1d.todo.v1.EditPriorityResponse\x129\n" +
+	"\x06AddTag\x12\x16.todo.v1.AddTagRequest\x1a\x17.todo.v1.AddTagResponse\x12B\n" +
+	"\tRemoveTag\x12\x19.todo.v1.RemoveTagRequest\x1a\x1a.todo.v1.RemoveTagResponse\x12N\n" +
+	"\rAddDependency\x12\x1d.todo.v1.AddDependencyRequest\x1a\x1e.todo.v1.AddDependencyResponse\x12W\n" +
+	"\x10RemoveDependency\x12 .todo.v1.RemoveDependencyRequest\x1a!.todo.v1.RemoveDependencyResponse\x129\n" +
+	"\x06Update\x12\x16.todo.v1.UpdateRequest\x1a\x17.todo.v1.UpdateResponse\x12N\n" +
+	"\rCreateProject\x12\x1d.todo.v1.CreateProjectRequest\x1a\x1e.todo.v1.CreateProjectResponse\x12K\n" +
+	"\fListProjects\x12\x1c.todo.v1.ListProjectsRequest\x1a\x1d.todo.v1.ListProjectsResponse\x12N\n" +
+	"\rRenameProject\x12\x1d.todo.v1.RenameProjectRequest\x1a\x1e.todo.v1.RenameProjectResponse\x12Q\n" +
+	"\x0eArchiveProject\x12\x1e.todo.v1.ArchiveProjectRequest\x1a\x1f.todo.v1.ArchiveProjectResponse\x12N\n" +
+	"\rDeleteProject\x12\x1d.todo.v1.DeleteProjectRequest\x1a\x1e.todo.v1.DeleteProjectResponse\x128\n" +
+	"\x05Watch\x12\x15.todo.v1.WatchRequest\x1a\x16.todo.v1.WatchResponse0\x01B.Z,github.com/amharshit45/todos-cli-/gen/todopbb\x06proto3"
 
 var (
 	file_proto_todo_v1_todo_proto_rawDescOnce sync.Once
@@ -654,41 +3062,134 @@ func file_proto_todo_v1_todo_proto_rawDescGZIP() []byte {
 	return file_proto_todo_v1_todo_proto_rawDescData
 }
 
-var file_proto_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_proto_todo_v1_todo_proto_goTypes = []any{
-	(*Todo)(nil),                    // 0: todo.v1.Todo
-	(*AddRequest)(nil),              // 1: todo.v1.AddRequest
-	(*AddResponse)(nil),             // 2: todo.v1.AddResponse
-	(*ListRequest)(nil),             // 3: todo.v1.ListRequest
-	(*ListResponse)(nil),            // 4: todo.v1.ListResponse
-	(*DeleteRequest)(nil),           // 5: todo.v1.DeleteRequest
-	(*DeleteResponse)(nil),          // 6: todo.v1.DeleteResponse
-	(*SetCompletedRequest)(nil),     // 7: todo.v1.SetCompletedRequest
-	(*SetCompletedResponse)(nil),    // 8: todo.v1.SetCompletedResponse
-	(*EditTitleRequest)(nil),        // 9: todo.v1.EditTitleRequest
-	(*EditTitleResponse)(nil),       // 10: todo.v1.EditTitleResponse
-	(*EditDescriptionRequest)(nil),  // 11: todo.v1.EditDescriptionRequest
-	(*EditDescriptionResponse)(nil), // 12: todo.v1.EditDescriptionResponse
+	(Priority)(0),                    // 0: todo.v1.Priority
+	(EventType)(0),                   // 1: todo.v1.EventType
+	(ErrorReason)(0),                 // 2: todo.v1.ErrorReason
+	(*Todo)(nil),                     // 3: todo.v1.Todo
+	(*AddRequest)(nil),               // 4: todo.v1.AddRequest
+	(*AddResponse)(nil),              // 5: todo.v1.AddResponse
+	(*ListRequest)(nil),              // 6: todo.v1.ListRequest
+	(*ListResponse)(nil),             // 7: todo.v1.ListResponse
+	(*DeleteRequest)(nil),            // 8: todo.v1.DeleteRequest
+	(*DeleteResponse)(nil),           // 9: todo.v1.DeleteResponse
+	(*RestoreRequest)(nil),           // 10: todo.v1.RestoreRequest
+	(*RestoreResponse)(nil),          // 11: todo.v1.RestoreResponse
+	(*PurgeTrashRequest)(nil),        // 12: todo.v1.PurgeTrashRequest
+	(*PurgeTrashResponse)(nil),       // 13: todo.v1.PurgeTrashResponse
+	(*SetCompletedRequest)(nil),      // 14: todo.v1.SetCompletedRequest
+	(*SetCompletedResponse)(nil),     // 15: todo.v1.SetCompletedResponse
+	(*EditTitleRequest)(nil),         // 16: todo.v1.EditTitleRequest
+	(*EditTitleResponse)(nil),        // 17: todo.v1.EditTitleResponse
+	(*EditDescriptionRequest)(nil),   // 18: todo.v1.EditDescriptionRequest
+	(*EditDescriptionResponse)(nil),  // 19: todo.v1.EditDescriptionResponse
+	(*EditDueRequest)(nil),           // 20: todo.v1.EditDueRequest
+	(*EditDueResponse)(nil),          // 21: todo.v1.EditDueResponse
+	(*EditPriorityRequest)(nil),      // 22: todo.v1.EditPriorityRequest
+	(*EditPriorityResponse)(nil),     // 23: todo.v1.EditPriorityResponse
+	(*AddTagRequest)(nil),            // 24: todo.v1.AddTagRequest
+	(*AddTagResponse)(nil),           // 25: todo.v1.AddTagResponse
+	(*RemoveTagRequest)(nil),         // 26: todo.v1.RemoveTagRequest
+	(*RemoveTagResponse)(nil),        // 27: todo.v1.RemoveTagResponse
+	(*AddDependencyRequest)(nil),     // 28: todo.v1.AddDependencyRequest
+	(*AddDependencyResponse)(nil),    // 29: todo.v1.AddDependencyResponse
+	(*RemoveDependencyRequest)(nil),  // 30: todo.v1.RemoveDependencyRequest
+	(*RemoveDependencyResponse)(nil), // 31: todo.v1.RemoveDependencyResponse
+	(*UpdateRequest)(nil),            // 32: todo.v1.UpdateRequest
+	(*UpdateResponse)(nil),           // 33: todo.v1.UpdateResponse
+	(*Project)(nil),                  // 34: todo.v1.Project
+	(*CreateProjectRequest)(nil),     // 35: todo.v1.CreateProjectRequest
+	(*CreateProjectResponse)(nil),    // 36: todo.v1.CreateProjectResponse
+	(*ListProjectsRequest)(nil),      // 37: todo.v1.ListProjectsRequest
+	(*ListProjectsResponse)(nil),     // 38: todo.v1.ListProjectsResponse
+	(*RenameProjectRequest)(nil),     // 39: todo.v1.RenameProjectRequest
+	(*RenameProjectResponse)(nil),    // 40: todo.v1.RenameProjectResponse
+	(*ArchiveProjectRequest)(nil),    // 41: todo.v1.ArchiveProjectRequest
+	(*ArchiveProjectResponse)(nil),   // 42: todo.v1.ArchiveProjectResponse
+	(*DeleteProjectRequest)(nil),     // 43: todo.v1.DeleteProjectRequest
+	(*DeleteProjectResponse)(nil),    // 44: todo.v1.DeleteProjectResponse
+	(*WatchRequest)(nil),             // 45: todo.v1.WatchRequest
+	(*WatchResponse)(nil),            // 46: todo.v1.WatchResponse
+	(*timestamppb.Timestamp)(nil),    // 47: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),    // 48: google.protobuf.FieldMask
 }
 var file_proto_todo_v1_todo_proto_depIdxs = []int32{
-	0,  // 0: todo.v1.ListResponse.todos:type_name -> todo.v1.Todo
-	1,  // 1: todo.v1.TodoService.Add:input_type -> todo.v1.AddRequest
-	3,  // 2: todo.v1.TodoService.List:input_type -> todo.v1.ListRequest
-	5,  // 3: todo.v1.TodoService.Delete:input_type -> todo.v1.DeleteRequest
-	7,  // 4: todo.v1.TodoService.SetCompleted:input_type -> todo.v1.SetCompletedRequest
-	9,  // 5: todo.v1.TodoService.EditTitle:input_type -> todo.v1.EditTitleRequest
-	11, // 6: todo.v1.TodoService.EditDescription:input_type -> todo.v1.EditDescriptionRequest
-	2,  // 7: todo.v1.TodoService.Add:output_type -> todo.v1.AddResponse
-	4,  // 8: todo.v1.TodoService.List:output_type -> todo.v1.ListResponse
-	6,  // 9: todo.v1.TodoService.Delete:output_type -> todo.v1.DeleteResponse
-	8,  // 10: todo.v1.TodoService.SetCompleted:output_type -> todo.v1.SetCompletedResponse
-	10, // 11: todo.v1.TodoService.EditTitle:output_type -> todo.v1.EditTitleResponse
-	12, // 12: todo.v1.TodoService.EditDescription:output_type -> todo.v1.EditDescriptionResponse
-	7,  // [7:13] is the sub-list for method output_type
-	1,  // [1:7] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	0,  // 0: todo.v1.Todo.priority:type_name -> todo.v1.Priority
+	47, // 1: todo.v1.Todo.deleted_at:type_name -> google.protobuf.Timestamp
+	47, // 2: todo.v1.Todo.created_at:type_name -> google.protobuf.Timestamp
+	47, // 3: todo.v1.Todo.updated_at:type_name -> google.protobuf.Timestamp
+	47, // 4: todo.v1.Todo.completed_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: todo.v1.AddRequest.priority:type_name -> todo.v1.Priority
+	3,  // 6: todo.v1.AddResponse.todo:type_name -> todo.v1.Todo
+	3,  // 7: todo.v1.ListResponse.todos:type_name -> todo.v1.Todo
+	3,  // 8: todo.v1.RestoreResponse.todo:type_name -> todo.v1.Todo
+	3,  // 9: todo.v1.SetCompletedResponse.todo:type_name -> todo.v1.Todo
+	3,  // 10: todo.v1.EditTitleResponse.todo:type_name -> todo.v1.Todo
+	3,  // 11: todo.v1.EditDescriptionResponse.todo:type_name -> todo.v1.Todo
+	3,  // 12: todo.v1.EditDueResponse.todo:type_name -> todo.v1.Todo
+	0,  // 13: todo.v1.EditPriorityRequest.priority:type_name -> todo.v1.Priority
+	3,  // 14: todo.v1.EditPriorityResponse.todo:type_name -> todo.v1.Todo
+	3,  // 15: todo.v1.AddTagResponse.todo:type_name -> todo.v1.Todo
+	3,  // 16: todo.v1.RemoveTagResponse.todo:type_name -> todo.v1.Todo
+	3,  // 17: todo.v1.AddDependencyResponse.todo:type_name -> todo.v1.Todo
+	3,  // 18: todo.v1.RemoveDependencyResponse.todo:type_name -> todo.v1.Todo
+	3,  // 19: todo.v1.UpdateRequest.todo:type_name -> todo.v1.Todo
+	48, // 20: todo.v1.UpdateRequest.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 21: todo.v1.UpdateResponse.todo:type_name -> todo.v1.Todo
+	34, // 22: todo.v1.CreateProjectResponse.project:type_name -> todo.v1.Project
+	34, // 23: todo.v1.ListProjectsResponse.projects:type_name -> todo.v1.Project
+	34, // 24: todo.v1.RenameProjectResponse.project:type_name -> todo.v1.Project
+	34, // 25: todo.v1.ArchiveProjectResponse.project:type_name -> todo.v1.Project
+	1,  // 26: todo.v1.WatchResponse.type:type_name -> todo.v1.EventType
+	4,  // 27: todo.v1.TodoService.Add:input_type -> todo.v1.AddRequest
+	6,  // 28: todo.v1.TodoService.List:input_type -> todo.v1.ListRequest
+	8,  // 29: todo.v1.TodoService.Delete:input_type -> todo.v1.DeleteRequest
+	10, // 30: todo.v1.TodoService.Restore:input_type -> todo.v1.RestoreRequest
+	12, // 31: todo.v1.TodoService.PurgeTrash:input_type -> todo.v1.PurgeTrashRequest
+	14, // 32: todo.v1.TodoService.SetCompleted:input_type -> todo.v1.SetCompletedRequest
+	16, // 33: todo.v1.TodoService.EditTitle:input_type -> todo.v1.EditTitleRequest
+	18, // 34: todo.v1.TodoService.EditDescription:input_type -> todo.v1.EditDescriptionRequest
+	20, // 35: todo.v1.TodoService.EditDue:input_type -> todo.v1.EditDueRequest
+	22, // 36: todo.v1.TodoService.EditPriority:input_type -> todo.v1.EditPriorityRequest
+	24, // 37: todo.v1.TodoService.AddTag:input_type -> todo.v1.AddTagRequest
+	26, // 38: todo.v1.TodoService.RemoveTag:input_type -> todo.v1.RemoveTagRequest
+	28, // 39: todo.v1.TodoService.AddDependency:input_type -> todo.v1.AddDependencyRequest
+	30, // 40: todo.v1.TodoService.RemoveDependency:input_type -> todo.v1.RemoveDependencyRequest
+	32, // 41: todo.v1.TodoService.Update:input_type -> todo.v1.UpdateRequest
+	35, // 42: todo.v1.TodoService.CreateProject:input_type -> todo.v1.CreateProjectRequest
+	37, // 43: todo.v1.TodoService.ListProjects:input_type -> todo.v1.ListProjectsRequest
+	39, // 44: todo.v1.TodoService.RenameProject:input_type -> todo.v1.RenameProjectRequest
+	41, // 45: todo.v1.TodoService.ArchiveProject:input_type -> todo.v1.ArchiveProjectRequest
+	43, // 46: todo.v1.TodoService.DeleteProject:input_type -> todo.v1.DeleteProjectRequest
+	45, // 47: todo.v1.TodoService.Watch:input_type -> todo.v1.WatchRequest
+	5,  // 48: todo.v1.TodoService.Add:output_type -> todo.v1.AddResponse
+	7,  // 49: todo.v1.TodoService.List:output_type -> todo.v1.ListResponse
+	9,  // 50: todo.v1.TodoService.Delete:output_type -> todo.v1.DeleteResponse
+	11, // 51: todo.v1.TodoService.Restore:output_type -> todo.v1.RestoreResponse
+	13, // 52: todo.v1.TodoService.PurgeTrash:output_type -> todo.v1.PurgeTrashResponse
+	15, // 53: todo.v1.TodoService.SetCompleted:output_type -> todo.v1.SetCompletedResponse
+	17, // 54: todo.v1.TodoService.EditTitle:output_type -> todo.v1.EditTitleResponse
+	19, // 55: todo.v1.TodoService.EditDescription:output_type -> todo.v1.EditDescriptionResponse
+	21, // 56: todo.v1.TodoService.EditDue:output_type -> todo.v1.EditDueResponse
+	23, // 57: todo.v1.TodoService.EditPriority:output_type -> todo.v1.EditPriorityResponse
+	25, // 58: todo.v1.TodoService.AddTag:output_type -> todo.v1.AddTagResponse
+	27, // 59: todo.v1.TodoService.RemoveTag:output_type -> todo.v1.RemoveTagResponse
+	29, // 60: todo.v1.TodoService.AddDependency:output_type -> todo.v1.AddDependencyResponse
+	31, // 61: todo.v1.TodoService.RemoveDependency:output_type -> todo.v1.RemoveDependencyResponse
+	33, // 62: todo.v1.TodoService.Update:output_type -> todo.v1.UpdateResponse
+	36, // 63: todo.v1.TodoService.CreateProject:output_type -> todo.v1.CreateProjectResponse
+	38, // 64: todo.v1.TodoService.ListProjects:output_type -> todo.v1.ListProjectsResponse
+	40, // 65: todo.v1.TodoService.RenameProject:output_type -> todo.v1.RenameProjectResponse
+	42, // 66: todo.v1.TodoService.ArchiveProject:output_type -> todo.v1.ArchiveProjectResponse
+	44, // 67: todo.v1.TodoService.DeleteProject:output_type -> todo.v1.DeleteProjectResponse
+	46, // 68: todo.v1.TodoService.Watch:output_type -> todo.v1.WatchResponse
+	48, // [48:69] is the sub-list for method output_type
+	27, // [27:48] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_proto_todo_v1_todo_proto_init() }
@@ -701,13 +3202,14 @@ func file_proto_todo_v1_todo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_todo_v1_todo_proto_rawDesc), len(file_proto_todo_v1_todo_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      3,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_todo_v1_todo_proto_goTypes,
 		DependencyIndexes: file_proto_todo_v1_todo_proto_depIdxs,
+		EnumInfos:         file_proto_todo_v1_todo_proto_enumTypes,
 		MessageInfos:      file_proto_todo_v1_todo_proto_msgTypes,
 	}.Build()
 	File_proto_todo_v1_todo_proto = out.File
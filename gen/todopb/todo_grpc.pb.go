@@ -19,24 +19,98 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TodoService_Add_FullMethodName             = "/todo.v1.TodoService/Add"
-	TodoService_List_FullMethodName            = "/todo.v1.TodoService/List"
-	TodoService_Delete_FullMethodName          = "/todo.v1.TodoService/Delete"
-	TodoService_SetCompleted_FullMethodName    = "/todo.v1.TodoService/SetCompleted"
-	TodoService_EditTitle_FullMethodName       = "/todo.v1.TodoService/EditTitle"
-	TodoService_EditDescription_FullMethodName = "/todo.v1.TodoService/EditDescription"
+	TodoService_Add_FullMethodName              = "/todo.v1.TodoService/Add"
+	TodoService_List_FullMethodName             = "/todo.v1.TodoService/List"
+	TodoService_Delete_FullMethodName           = "/todo.v1.TodoService/Delete"
+	TodoService_Restore_FullMethodName          = "/todo.v1.TodoService/Restore"
+	TodoService_PurgeTrash_FullMethodName       = "/todo.v1.TodoService/PurgeTrash"
+	TodoService_SetCompleted_FullMethodName     = "/todo.v1.TodoService/SetCompleted"
+	TodoService_EditTitle_FullMethodName        = "/todo.v1.TodoService/EditTitle"
+	TodoService_EditDescription_FullMethodName  = "/todo.v1.TodoService/EditDescription"
+	TodoService_EditDue_FullMethodName          = "/todo.v1.TodoService/EditDue"
+	TodoService_EditPriority_FullMethodName     = "/todo.v1.TodoService/EditPriority"
+	TodoService_AddTag_FullMethodName           = "/todo.v1.TodoService/AddTag"
+	TodoService_RemoveTag_FullMethodName        = "/todo.v1.TodoService/RemoveTag"
+	TodoService_AddDependency_FullMethodName    = "/todo.v1.TodoService/AddDependency"
+	TodoService_RemoveDependency_FullMethodName = "/todo.v1.TodoService/RemoveDependency"
+	TodoService_Update_FullMethodName           = "/todo.v1.TodoService/Update"
+	TodoService_CreateProject_FullMethodName    = "/todo.v1.TodoService/CreateProject"
+	TodoService_ListProjects_FullMethodName     = "/todo.v1.TodoService/ListProjects"
+	TodoService_RenameProject_FullMethodName    = "/todo.v1.TodoService/RenameProject"
+	TodoService_ArchiveProject_FullMethodName   = "/todo.v1.TodoService/ArchiveProject"
+	TodoService_DeleteProject_FullMethodName    = "/todo.v1.TodoService/DeleteProject"
+	TodoService_Watch_FullMethodName            = "/todo.v1.TodoService/Watch"
 )
 
 // TodoServiceClient is the client API for TodoService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TodoService manages todo items over gRPC. Every call acts for one user
+// and only sees that user's todos and projects. On a server requiring
+// client certificates, the user is the common name of the certificate's
+// subject. Otherwise it is the owner of the API key whose token the call
+// carries in its "authorization" metadata, as "Bearer <token>"; on a server
+// run without authentication, it is the user the "x-todo-user" metadata
+// names, or the shared list of callers that name none.
 type TodoServiceClient interface {
+	// Add creates a new todo with a title and optional description, due date,
+	// priority and tags, and returns it.
 	Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddResponse, error)
+	// List returns todos matching the optional tag and filter constraints,
+	// ordered by ID and optionally paginated.
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Delete moves a todo, and with cascade its subtasks, to the trash by ID.
+	// Trashed todos are left out of List and cannot be changed until they are
+	// restored.
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Restore moves a todo out of the trash, with the subtasks that were
+	// trashed with it.
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
+	// PurgeTrash permanently removes every todo in the trash.
+	PurgeTrash(ctx context.Context, in *PurgeTrashRequest, opts ...grpc.CallOption) (*PurgeTrashResponse, error)
+	// SetCompleted marks a todo as completed or incomplete. Completing a todo
+	// while a todo it is blocked by is open fails with FAILED_PRECONDITION and
+	// reason BLOCKED. Completing a recurring todo adds its next occurrence,
+	// whose ID the returned todo carries in next_id.
 	SetCompleted(ctx context.Context, in *SetCompletedRequest, opts ...grpc.CallOption) (*SetCompletedResponse, error)
+	// EditTitle updates the title of a todo.
 	EditTitle(ctx context.Context, in *EditTitleRequest, opts ...grpc.CallOption) (*EditTitleResponse, error)
+	// EditDescription updates the description of a todo.
 	EditDescription(ctx context.Context, in *EditDescriptionRequest, opts ...grpc.CallOption) (*EditDescriptionResponse, error)
+	// EditDue sets or clears the due date and time of a todo.
+	EditDue(ctx context.Context, in *EditDueRequest, opts ...grpc.CallOption) (*EditDueResponse, error)
+	// EditPriority changes the priority of a todo.
+	EditPriority(ctx context.Context, in *EditPriorityRequest, opts ...grpc.CallOption) (*EditPriorityResponse, error)
+	// AddTag attaches a tag to a todo.
+	AddTag(ctx context.Context, in *AddTagRequest, opts ...grpc.CallOption) (*AddTagResponse, error)
+	// RemoveTag detaches a tag from a todo.
+	RemoveTag(ctx context.Context, in *RemoveTagRequest, opts ...grpc.CallOption) (*RemoveTagResponse, error)
+	// AddDependency makes a todo blocked by another, which must be completed
+	// first. A dependency that would close a cycle fails with
+	// FAILED_PRECONDITION and reason DEPENDENCY_CYCLE.
+	AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error)
+	// RemoveDependency makes a todo no longer blocked by another.
+	RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error)
+	// Update replaces the fields of a todo named by the update mask, all or
+	// none of them.
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// CreateProject adds a project. A name already taken fails with
+	// ALREADY_EXISTS and reason PROJECT_NAME_TAKEN.
+	CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*CreateProjectResponse, error)
+	// ListProjects returns every project but the Inbox, archived ones
+	// included.
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	// RenameProject changes the name of a project.
+	RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*RenameProjectResponse, error)
+	// ArchiveProject archives or unarchives a project.
+	ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error)
+	// DeleteProject removes a project. One that holds todos, in the trash or
+	// not, fails with FAILED_PRECONDITION and reason PROJECT_NOT_EMPTY.
+	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error)
+	// Watch streams an event for every change made through this server until
+	// the client cancels or the server shuts down.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
 }
 
 type todoServiceClient struct {
@@ -77,6 +151,26 @@ func (c *todoServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts
 	return out, nil
 }
 
+func (c *todoServiceClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, TodoService_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) PurgeTrash(ctx context.Context, in *PurgeTrashRequest, opts ...grpc.CallOption) (*PurgeTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeTrashResponse)
+	err := c.cc.Invoke(ctx, TodoService_PurgeTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *todoServiceClient) SetCompleted(ctx context.Context, in *SetCompletedRequest, opts ...grpc.CallOption) (*SetCompletedResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SetCompletedResponse)
@@ -107,16 +201,214 @@ func (c *todoServiceClient) EditDescription(ctx context.Context, in *EditDescrip
 	return out, nil
 }
 
+func (c *todoServiceClient) EditDue(ctx context.Context, in *EditDueRequest, opts ...grpc.CallOption) (*EditDueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditDueResponse)
+	err := c.cc.Invoke(ctx, TodoService_EditDue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) EditPriority(ctx context.Context, in *EditPriorityRequest, opts ...grpc.CallOption) (*EditPriorityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditPriorityResponse)
+	err := c.cc.Invoke(ctx, TodoService_EditPriority_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AddTag(ctx context.Context, in *AddTagRequest, opts ...grpc.CallOption) (*AddTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTagResponse)
+	err := c.cc.Invoke(ctx, TodoService_AddTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RemoveTag(ctx context.Context, in *RemoveTagRequest, opts ...grpc.CallOption) (*RemoveTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveTagResponse)
+	err := c.cc.Invoke(ctx, TodoService_RemoveTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddDependencyResponse)
+	err := c.cc.Invoke(ctx, TodoService_AddDependency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveDependencyResponse)
+	err := c.cc.Invoke(ctx, TodoService_RemoveDependency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, TodoService_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*CreateProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateProjectResponse)
+	err := c.cc.Invoke(ctx, TodoService_CreateProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListProjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*RenameProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameProjectResponse)
+	err := c.cc.Invoke(ctx, TodoService_RenameProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveProjectResponse)
+	err := c.cc.Invoke(ctx, TodoService_ArchiveProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteProjectResponse)
+	err := c.cc.Invoke(ctx, TodoService_DeleteProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[0], TodoService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility.
+//
+// TodoService manages todo items over gRPC. Every call acts for one user
+// and only sees that user's todos and projects. On a server requiring
+// client certificates, the user is the common name of the certificate's
+// subject. Otherwise it is the owner of the API key whose token the call
+// carries in its "authorization" metadata, as "Bearer <token>"; on a server
+// run without authentication, it is the user the "x-todo-user" metadata
+// names, or the shared list of callers that name none.
 type TodoServiceServer interface {
+	// Add creates a new todo with a title and optional description, due date,
+	// priority and tags, and returns it.
 	Add(context.Context, *AddRequest) (*AddResponse, error)
+	// List returns todos matching the optional tag and filter constraints,
+	// ordered by ID and optionally paginated.
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Delete moves a todo, and with cascade its subtasks, to the trash by ID.
+	// Trashed todos are left out of List and cannot be changed until they are
+	// restored.
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Restore moves a todo out of the trash, with the subtasks that were
+	// trashed with it.
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
+	// PurgeTrash permanently removes every todo in the trash.
+	PurgeTrash(context.Context, *PurgeTrashRequest) (*PurgeTrashResponse, error)
+	// SetCompleted marks a todo as completed or incomplete. Completing a todo
+	// while a todo it is blocked by is open fails with FAILED_PRECONDITION and
+	// reason BLOCKED. Completing a recurring todo adds its next occurrence,
+	// whose ID the returned todo carries in next_id.
 	SetCompleted(context.Context, *SetCompletedRequest) (*SetCompletedResponse, error)
+	// EditTitle updates the title of a todo.
 	EditTitle(context.Context, *EditTitleRequest) (*EditTitleResponse, error)
+	// EditDescription updates the description of a todo.
 	EditDescription(context.Context, *EditDescriptionRequest) (*EditDescriptionResponse, error)
+	// EditDue sets or clears the due date and time of a todo.
+	EditDue(context.Context, *EditDueRequest) (*EditDueResponse, error)
+	// EditPriority changes the priority of a todo.
+	EditPriority(context.Context, *EditPriorityRequest) (*EditPriorityResponse, error)
+	// AddTag attaches a tag to a todo.
+	AddTag(context.Context, *AddTagRequest) (*AddTagResponse, error)
+	// RemoveTag detaches a tag from a todo.
+	RemoveTag(context.Context, *RemoveTagRequest) (*RemoveTagResponse, error)
+	// AddDependency makes a todo blocked by another, which must be completed
+	// first. A dependency that would close a cycle fails with
+	// FAILED_PRECONDITION and reason DEPENDENCY_CYCLE.
+	AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error)
+	// RemoveDependency makes a todo no longer blocked by another.
+	RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error)
+	// Update replaces the fields of a todo named by the update mask, all or
+	// none of them.
+	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	// CreateProject adds a project. A name already taken fails with
+	// ALREADY_EXISTS and reason PROJECT_NAME_TAKEN.
+	CreateProject(context.Context, *CreateProjectRequest) (*CreateProjectResponse, error)
+	// ListProjects returns every project but the Inbox, archived ones
+	// included.
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	// RenameProject changes the name of a project.
+	RenameProject(context.Context, *RenameProjectRequest) (*RenameProjectResponse, error)
+	// ArchiveProject archives or unarchives a project.
+	ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error)
+	// DeleteProject removes a project. One that holds todos, in the trash or
+	// not, fails with FAILED_PRECONDITION and reason PROJECT_NOT_EMPTY.
+	DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error)
+	// Watch streams an event for every change made through this server until
+	// the client cancels or the server shuts down.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -136,6 +428,12 @@ func (UnimplementedTodoServiceServer) List(context.Context, *ListRequest) (*List
 func (UnimplementedTodoServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedTodoServiceServer) Restore(context.Context, *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedTodoServiceServer) PurgeTrash(context.Context, *PurgeTrashRequest) (*PurgeTrashResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PurgeTrash not implemented")
+}
 func (UnimplementedTodoServiceServer) SetCompleted(context.Context, *SetCompletedRequest) (*SetCompletedResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetCompleted not implemented")
 }
@@ -145,6 +443,45 @@ func (UnimplementedTodoServiceServer) EditTitle(context.Context, *EditTitleReque
 func (UnimplementedTodoServiceServer) EditDescription(context.Context, *EditDescriptionRequest) (*EditDescriptionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method EditDescription not implemented")
 }
+func (UnimplementedTodoServiceServer) EditDue(context.Context, *EditDueRequest) (*EditDueResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EditDue not implemented")
+}
+func (UnimplementedTodoServiceServer) EditPriority(context.Context, *EditPriorityRequest) (*EditPriorityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EditPriority not implemented")
+}
+func (UnimplementedTodoServiceServer) AddTag(context.Context, *AddTagRequest) (*AddTagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddTag not implemented")
+}
+func (UnimplementedTodoServiceServer) RemoveTag(context.Context, *RemoveTagRequest) (*RemoveTagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveTag not implemented")
+}
+func (UnimplementedTodoServiceServer) AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddDependency not implemented")
+}
+func (UnimplementedTodoServiceServer) RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveDependency not implemented")
+}
+func (UnimplementedTodoServiceServer) Update(context.Context, *UpdateRequest) (*UpdateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateProject(context.Context, *CreateProjectRequest) (*CreateProjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateProject not implemented")
+}
+func (UnimplementedTodoServiceServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedTodoServiceServer) RenameProject(context.Context, *RenameProjectRequest) (*RenameProjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameProject not implemented")
+}
+func (UnimplementedTodoServiceServer) ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ArchiveProject not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedTodoServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 func (UnimplementedTodoServiceServer) testEmbeddedByValue()                     {}
 
@@ -220,6 +557,42 @@ func _TodoService_Delete_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_PurgeTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).PurgeTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_PurgeTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).PurgeTrash(ctx, req.(*PurgeTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TodoService_SetCompleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetCompletedRequest)
 	if err := dec(in); err != nil {
@@ -274,6 +647,233 @@ func _TodoService_EditDescription_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_EditDue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditDueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).EditDue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_EditDue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).EditDue(ctx, req.(*EditDueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_EditPriority_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditPriorityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).EditPriority(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_EditPriority_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).EditPriority(ctx, req.(*EditPriorityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AddTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_AddTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddTag(ctx, req.(*AddTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RemoveTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RemoveTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_RemoveTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RemoveTag(ctx, req.(*RemoveTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AddDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_AddDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddDependency(ctx, req.(*AddDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RemoveDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RemoveDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_RemoveDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RemoveDependency(ctx, req.(*RemoveDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateProject(ctx, req.(*CreateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RenameProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RenameProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_RenameProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RenameProject(ctx, req.(*RenameProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ArchiveProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ArchiveProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ArchiveProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ArchiveProject(ctx, req.(*ArchiveProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_DeleteProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteProject(ctx, req.(*DeleteProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
 // TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -293,6 +893,14 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _TodoService_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _TodoService_Restore_Handler,
+		},
+		{
+			MethodName: "PurgeTrash",
+			Handler:    _TodoService_PurgeTrash_Handler,
+		},
 		{
 			MethodName: "SetCompleted",
 			Handler:    _TodoService_SetCompleted_Handler,
@@ -305,7 +913,61 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EditDescription",
 			Handler:    _TodoService_EditDescription_Handler,
 		},
+		{
+			MethodName: "EditDue",
+			Handler:    _TodoService_EditDue_Handler,
+		},
+		{
+			MethodName: "EditPriority",
+			Handler:    _TodoService_EditPriority_Handler,
+		},
+		{
+			MethodName: "AddTag",
+			Handler:    _TodoService_AddTag_Handler,
+		},
+		{
+			MethodName: "RemoveTag",
+			Handler:    _TodoService_RemoveTag_Handler,
+		},
+		{
+			MethodName: "AddDependency",
+			Handler:    _TodoService_AddDependency_Handler,
+		},
+		{
+			MethodName: "RemoveDependency",
+			Handler:    _TodoService_RemoveDependency_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _TodoService_Update_Handler,
+		},
+		{
+			MethodName: "CreateProject",
+			Handler:    _TodoService_CreateProject_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _TodoService_ListProjects_Handler,
+		},
+		{
+			MethodName: "RenameProject",
+			Handler:    _TodoService_RenameProject_Handler,
+		},
+		{
+			MethodName: "ArchiveProject",
+			Handler:    _TodoService_ArchiveProject_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _TodoService_DeleteProject_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _TodoService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/todo/v1/todo.proto",
 }
@@ -2,130 +2,425 @@ package grpcclient
 
 import (
 	"context"
-	"errors"
-	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/amharshit45/todos-cli-/gen/todopb"
 	"github.com/amharshit45/todos-cli-/todo"
 )
 
-var _ todo.Storage = (*Storage)(nil)
+var (
+	_ todo.Storage = (*Storage)(nil)
+	_ todo.Watcher = (*Storage)(nil)
+)
 
 type Storage struct {
-	conn   *grpc.ClientConn
-	client todopb.TodoServiceClient
+	conn      *grpc.ClientConn
+	client    todopb.TodoServiceClient
+	closeOnce sync.Once
 }
 
+// NewStorage returns a Storage that calls the server over conn. Each call
+// names the owner of its context (see todo.WithOwner) to the server, which
+// scopes the call to that user.
 func NewStorage(conn *grpc.ClientConn) *Storage {
 	return &Storage{
 		conn:   conn,
-		client: todopb.NewTodoServiceClient(conn),
+		client: todopb.NewTodoServiceClient(ownerConn{conn}),
 	}
 }
 
-func (s *Storage) Add(ctx context.Context, title, description string) error {
-	_, err := s.client.Add(ctx, &todopb.AddRequest{Title: title, Description: description})
-	return grpcToDomainError(err)
+// userMetadataKey is the request metadata key the server reads the calling
+// user from.
+const userMetadataKey = "x-todo-user"
+
+// ownerConn adds the owner of each call's context to its metadata.
+type ownerConn struct {
+	grpc.ClientConnInterface
+}
+
+func (c ownerConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return c.ClientConnInterface.Invoke(withOwnerMetadata(ctx), method, args, reply, opts...)
+}
+
+func (c ownerConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.ClientConnInterface.NewStream(withOwnerMetadata(ctx), desc, method, opts...)
 }
 
-func (s *Storage) List(ctx context.Context) ([]todo.Todo, error) {
-	resp, err := s.client.List(ctx, &todopb.ListRequest{})
+// withOwnerMetadata names ctx's owner in its outgoing metadata. The shared
+// list is named by leaving it out.
+func withOwnerMetadata(ctx context.Context) context.Context {
+	if owner := todo.Owner(ctx); owner != "" {
+		return metadata.AppendToOutgoingContext(ctx, userMetadataKey, owner)
+	}
+	return ctx
+}
+
+func (s *Storage) Add(ctx context.Context, draft todo.Draft) (todo.Todo, error) {
+	resp, err := s.client.Add(ctx, &todopb.AddRequest{
+		Title:       draft.Title,
+		Description: draft.Description,
+		DueDate:     draft.DueDate,
+		DueTime:     draft.DueTime,
+		Priority:    todopb.Priority(draft.Priority),
+		Tags:        draft.Tags,
+		ParentId:    int32(draft.ParentID),
+		ProjectId:   int32(draft.ProjectID),
+		Recurrence:  draft.Recurrence,
+	})
 	if err != nil {
-		return nil, grpcToDomainError(err)
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) List(ctx context.Context, opts todo.ListOptions) ([]todo.Todo, string, error) {
+	resp, err := s.client.List(ctx, &todopb.ListRequest{
+		Tags:      opts.Tags,
+		Filter:    opts.Filter,
+		PageSize:  int32(opts.PageSize),
+		PageToken: opts.PageToken,
+		Trashed:   opts.Trashed,
+		ProjectId: int32(opts.ProjectID),
+	})
+	if err != nil {
+		return nil, "", grpcToDomainError(err)
 	}
 	todos := make([]todo.Todo, len(resp.GetTodos()))
 	for i, t := range resp.GetTodos() {
-		todos[i] = todo.Todo{
-			ID:          int(t.GetId()),
-			Title:       t.GetTitle(),
-			Description: t.GetDescription(),
-			Completed:   t.GetCompleted(),
-		}
+		todos[i] = fromPB(t)
 	}
-	return todos, nil
+	return todos, resp.GetNextPageToken(), nil
 }
 
 func (s *Storage) Delete(ctx context.Context, id int) error {
-	_, err := s.client.Delete(ctx, &todopb.DeleteRequest{Id: int32(id)})
+	_, err := s.client.Delete(ctx, &todopb.DeleteRequest{Id: int32(id), ExpectedVersion: expectedVersion(ctx)})
 	return grpcToDomainError(err)
 }
 
-func (s *Storage) SetCompleted(ctx context.Context, id int, completed bool) error {
-	_, err := s.client.SetCompleted(ctx, &todopb.SetCompletedRequest{
-		Id:        int32(id),
-		Completed: completed,
+func (s *Storage) DeleteTree(ctx context.Context, id int) (int, error) {
+	resp, err := s.client.Delete(ctx, &todopb.DeleteRequest{Id: int32(id), ExpectedVersion: expectedVersion(ctx), Cascade: true})
+	if err != nil {
+		return 0, grpcToDomainError(err)
+	}
+	return int(resp.GetDeleted()), nil
+}
+
+func (s *Storage) Restore(ctx context.Context, id int) (todo.Todo, error) {
+	resp, err := s.client.Restore(ctx, &todopb.RestoreRequest{Id: int32(id), ExpectedVersion: expectedVersion(ctx)})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) PurgeTrash(ctx context.Context) (int, error) {
+	resp, err := s.client.PurgeTrash(ctx, &todopb.PurgeTrashRequest{})
+	if err != nil {
+		return 0, grpcToDomainError(err)
+	}
+	return int(resp.GetPurged()), nil
+}
+
+func (s *Storage) SetCompleted(ctx context.Context, id int, completed bool) (todo.Todo, error) {
+	resp, err := s.client.SetCompleted(ctx, &todopb.SetCompletedRequest{
+		Id:              int32(id),
+		Completed:       completed,
+		ExpectedVersion: expectedVersion(ctx),
 	})
-	return grpcToDomainError(err)
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
 }
 
-func (s *Storage) EditTitle(ctx context.Context, id int, title string) error {
-	_, err := s.client.EditTitle(ctx, &todopb.EditTitleRequest{
-		Id:    int32(id),
-		Title: title,
+func (s *Storage) EditTitle(ctx context.Context, id int, title string) (todo.Todo, error) {
+	resp, err := s.client.EditTitle(ctx, &todopb.EditTitleRequest{
+		Id:              int32(id),
+		Title:           title,
+		ExpectedVersion: expectedVersion(ctx),
 	})
-	return grpcToDomainError(err)
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) EditDescription(ctx context.Context, id int, description string) (todo.Todo, error) {
+	resp, err := s.client.EditDescription(ctx, &todopb.EditDescriptionRequest{
+		Id:              int32(id),
+		Description:     description,
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) EditDue(ctx context.Context, id int, dueDate, dueTime string) (todo.Todo, error) {
+	resp, err := s.client.EditDue(ctx, &todopb.EditDueRequest{
+		Id:              int32(id),
+		DueDate:         dueDate,
+		DueTime:         dueTime,
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) EditPriority(ctx context.Context, id int, priority todo.Priority) (todo.Todo, error) {
+	resp, err := s.client.EditPriority(ctx, &todopb.EditPriorityRequest{
+		Id:              int32(id),
+		Priority:        todopb.Priority(priority),
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
 }
 
-func (s *Storage) EditDescription(ctx context.Context, id int, description string) error {
-	_, err := s.client.EditDescription(ctx, &todopb.EditDescriptionRequest{
-		Id:          int32(id),
-		Description: description,
+func (s *Storage) AddTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	resp, err := s.client.AddTag(ctx, &todopb.AddTagRequest{
+		Id:              int32(id),
+		Tag:             tag,
+		ExpectedVersion: expectedVersion(ctx),
 	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) RemoveTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	resp, err := s.client.RemoveTag(ctx, &todopb.RemoveTagRequest{
+		Id:              int32(id),
+		Tag:             tag,
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) AddDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	resp, err := s.client.AddDependency(ctx, &todopb.AddDependencyRequest{
+		Id:              int32(id),
+		BlockerId:       int32(blockerID),
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) RemoveDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	resp, err := s.client.RemoveDependency(ctx, &todopb.RemoveDependencyRequest{
+		Id:              int32(id),
+		BlockerId:       int32(blockerID),
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) Update(ctx context.Context, patch todo.Todo, mask []string) (todo.Todo, error) {
+	resp, err := s.client.Update(ctx, &todopb.UpdateRequest{
+		Todo:            toPB(patch),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: mask},
+		ExpectedVersion: expectedVersion(ctx),
+	})
+	if err != nil {
+		return todo.Todo{}, grpcToDomainError(err)
+	}
+	return fromPB(resp.GetTodo()), nil
+}
+
+func (s *Storage) CreateProject(ctx context.Context, name string) (todo.Project, error) {
+	resp, err := s.client.CreateProject(ctx, &todopb.CreateProjectRequest{Name: name})
+	if err != nil {
+		return todo.Project{}, grpcToDomainError(err)
+	}
+	return projectFromPB(resp.GetProject()), nil
+}
+
+func (s *Storage) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	resp, err := s.client.ListProjects(ctx, &todopb.ListProjectsRequest{})
+	if err != nil {
+		return nil, grpcToDomainError(err)
+	}
+	projects := make([]todo.Project, len(resp.GetProjects()))
+	for i, p := range resp.GetProjects() {
+		projects[i] = projectFromPB(p)
+	}
+	return projects, nil
+}
+
+func (s *Storage) RenameProject(ctx context.Context, id int, name string) (todo.Project, error) {
+	resp, err := s.client.RenameProject(ctx, &todopb.RenameProjectRequest{Id: int32(id), Name: name})
+	if err != nil {
+		return todo.Project{}, grpcToDomainError(err)
+	}
+	return projectFromPB(resp.GetProject()), nil
+}
+
+func (s *Storage) ArchiveProject(ctx context.Context, id int, archived bool) (todo.Project, error) {
+	resp, err := s.client.ArchiveProject(ctx, &todopb.ArchiveProjectRequest{Id: int32(id), Archived: archived})
+	if err != nil {
+		return todo.Project{}, grpcToDomainError(err)
+	}
+	return projectFromPB(resp.GetProject()), nil
+}
+
+func (s *Storage) DeleteProject(ctx context.Context, id int) error {
+	_, err := s.client.DeleteProject(ctx, &todopb.DeleteProjectRequest{Id: int32(id)})
 	return grpcToDomainError(err)
 }
 
+// Watch opens a change stream and returns once the server has subscribed it,
+// so changes made after Watch returns are always delivered. The channel is
+// closed when ctx is done or the stream breaks.
+func (s *Storage) Watch(ctx context.Context) (<-chan todo.Event, error) {
+	stream, err := s.client.Watch(ctx, &todopb.WatchRequest{})
+	if err != nil {
+		return nil, grpcToDomainError(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		return nil, grpcToDomainError(err)
+	}
+	events := make(chan todo.Event)
+	go func() {
+		defer close(events)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			e := todo.Event{Type: todo.EventType(resp.GetType()), ID: int(resp.GetId())}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
 func (s *Storage) Close(_ context.Context) error {
-	return s.conn.Close()
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.conn.Close()
+	})
+	return err
 }
 
-// wrappedError preserves the original server message for display
-// while wrapping the domain sentinel so errors.Is works across the gRPC boundary.
-type wrappedError struct {
-	msg      string
-	sentinel error
+// expectedVersion returns the version set on ctx by
+// todo.WithExpectedVersion, for the request's expected_version.
+func expectedVersion(ctx context.Context) int64 {
+	return int64(todo.ExpectedVersion(ctx))
 }
 
-func (e *wrappedError) Error() string { return e.msg }
-func (e *wrappedError) Unwrap() error { return e.sentinel }
+func toPB(t todo.Todo) *todopb.Todo {
+	return &todopb.Todo{
+		Id:          int32(t.ID),
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		DueDate:     t.DueDate,
+		DueTime:     t.DueTime,
+		Priority:    todopb.Priority(t.Priority),
+		Tags:        t.Tags,
+		Version:     int64(t.Version),
+		DeletedAt:   timestampPB(t.DeletedAt),
+		CreatedAt:   timestampPB(t.CreatedAt),
+		UpdatedAt:   timestampPB(t.UpdatedAt),
+		CompletedAt: timestampPB(t.CompletedAt),
+		ParentId:    int32(t.ParentID),
+		ProjectId:   int32(t.ProjectID),
+		BlockedBy:   int32s(t.BlockedBy),
+		Recurrence:  t.Recurrence,
+		NextId:      int32(t.NextID),
+	}
+}
+
+func fromPB(t *todopb.Todo) todo.Todo {
+	return todo.Todo{
+		ID:          int(t.GetId()),
+		Title:       t.GetTitle(),
+		Description: t.GetDescription(),
+		Completed:   t.GetCompleted(),
+		DueDate:     t.GetDueDate(),
+		DueTime:     t.GetDueTime(),
+		Priority:    todo.Priority(t.GetPriority()),
+		Tags:        t.GetTags(),
+		Version:     int(t.GetVersion()),
+		DeletedAt:   timeFromPB(t.GetDeletedAt()),
+		CreatedAt:   timeFromPB(t.GetCreatedAt()),
+		UpdatedAt:   timeFromPB(t.GetUpdatedAt()),
+		CompletedAt: timeFromPB(t.GetCompletedAt()),
+		ParentID:    int(t.GetParentId()),
+		ProjectID:   int(t.GetProjectId()),
+		BlockedBy:   ints(t.GetBlockedBy()),
+		Recurrence:  t.GetRecurrence(),
+		NextID:      int(t.GetNextId()),
+		Recurred:    t.GetRecurred(),
+		Owner:       t.GetOwner(),
+	}
+}
 
-var codeToSentinels = map[codes.Code][]error{
-	codes.NotFound: {todo.ErrNotFound},
-	codes.FailedPrecondition: {
-		todo.ErrAlreadyCompleted,
-		todo.ErrAlreadyIncomplete,
-		todo.ErrTitleUnchanged,
-		todo.ErrDescriptionUnchanged,
-	},
-	codes.InvalidArgument: {
-		todo.ErrInvalidID,
-		todo.ErrEmptyTitle,
-		todo.ErrTitleTooLong,
-		todo.ErrDescriptionTooLong,
-	},
+func projectFromPB(p *todopb.Project) todo.Project {
+	return todo.Project{ID: int(p.GetId()), Name: p.GetName(), Archived: p.GetArchived(), Owner: p.GetOwner()}
 }
 
-func grpcToDomainError(err error) error {
-	if err == nil {
+// timestampPB converts t to a Timestamp, leaving the zero time unset.
+func timestampPB(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
 		return nil
 	}
+	return timestamppb.New(t)
+}
 
-	st, ok := status.FromError(err)
-	if !ok {
-		return err
+// timeFromPB is the inverse of timestampPB.
+func timeFromPB(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
 	}
+	return ts.AsTime()
+}
 
-	msg := st.Message()
-	if sentinels, exists := codeToSentinels[st.Code()]; exists {
-		for _, sentinel := range sentinels {
-			if strings.Contains(msg, sentinel.Error()) {
-				return &wrappedError{msg: msg, sentinel: sentinel}
-			}
-		}
+// int32s converts IDs to their wire type.
+func int32s(ids []int) []int32 {
+	if len(ids) == 0 {
+		return nil
 	}
+	out := make([]int32, len(ids))
+	for i, id := range ids {
+		out[i] = int32(id)
+	}
+	return out
+}
 
-	return errors.New(msg)
+// ints converts IDs from their wire type.
+func ints(ids []int32) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	out := make([]int, len(ids))
+	for i, id := range ids {
+		out[i] = int(id)
+	}
+	return out
 }
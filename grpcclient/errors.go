@@ -0,0 +1,129 @@
+package grpcclient
+
+import (
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/amharshit45/todos-cli-/gen/todopb"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// errorDomain is the domain of the google.rpc.ErrorInfo the server attaches
+// to domain errors.
+const errorDomain = "todo.v1"
+
+var reasonToSentinel = map[todopb.ErrorReason]error{
+	todopb.ErrorReason_NOT_FOUND:              todo.ErrNotFound,
+	todopb.ErrorReason_ALREADY_COMPLETED:      todo.ErrAlreadyCompleted,
+	todopb.ErrorReason_ALREADY_INCOMPLETE:     todo.ErrAlreadyIncomplete,
+	todopb.ErrorReason_TITLE_UNCHANGED:        todo.ErrTitleUnchanged,
+	todopb.ErrorReason_DESCRIPTION_UNCHANGED:  todo.ErrDescriptionUnchanged,
+	todopb.ErrorReason_DUE_UNCHANGED:          todo.ErrDueUnchanged,
+	todopb.ErrorReason_PRIORITY_UNCHANGED:     todo.ErrPriorityUnchanged,
+	todopb.ErrorReason_TODO_UNCHANGED:         todo.ErrTodoUnchanged,
+	todopb.ErrorReason_TAG_ALREADY_PRESENT:    todo.ErrTagAlreadyPresent,
+	todopb.ErrorReason_TAG_NOT_PRESENT:        todo.ErrTagNotPresent,
+	todopb.ErrorReason_TOO_MANY_TAGS:          todo.ErrTooManyTags,
+	todopb.ErrorReason_INVALID_ID:             todo.ErrInvalidID,
+	todopb.ErrorReason_EMPTY_TITLE:            todo.ErrEmptyTitle,
+	todopb.ErrorReason_TITLE_TOO_LONG:         todo.ErrTitleTooLong,
+	todopb.ErrorReason_DESCRIPTION_TOO_LONG:   todo.ErrDescriptionTooLong,
+	todopb.ErrorReason_INVALID_DUE_DATE:       todo.ErrInvalidDueDate,
+	todopb.ErrorReason_INVALID_DUE_TIME:       todo.ErrInvalidDueTime,
+	todopb.ErrorReason_DUE_TIME_WITHOUT_DATE:  todo.ErrDueTimeWithoutDate,
+	todopb.ErrorReason_INVALID_PRIORITY:       todo.ErrInvalidPriority,
+	todopb.ErrorReason_INVALID_TAG:            todo.ErrInvalidTag,
+	todopb.ErrorReason_INVALID_FILTER:         todo.ErrInvalidFilter,
+	todopb.ErrorReason_INVALID_PAGE_SIZE:      todo.ErrInvalidPageSize,
+	todopb.ErrorReason_INVALID_PAGE_TOKEN:     todo.ErrInvalidPageToken,
+	todopb.ErrorReason_INVALID_UPDATE_MASK:    todo.ErrInvalidUpdateMask,
+	todopb.ErrorReason_VERSION_CONFLICT:       todo.ErrVersionConflict,
+	todopb.ErrorReason_NOT_IN_TRASH:           todo.ErrNotInTrash,
+	todopb.ErrorReason_PARENT_NOT_FOUND:       todo.ErrParentNotFound,
+	todopb.ErrorReason_PARENT_CYCLE:           todo.ErrParentCycle,
+	todopb.ErrorReason_HAS_SUBTASKS:           todo.ErrHasSubtasks,
+	todopb.ErrorReason_BLOCKED:                todo.ErrBlocked,
+	todopb.ErrorReason_BLOCKER_NOT_FOUND:      todo.ErrBlockerNotFound,
+	todopb.ErrorReason_DEPENDENCY_CYCLE:       todo.ErrDependencyCycle,
+	todopb.ErrorReason_DEPENDENCY_PRESENT:     todo.ErrDependencyPresent,
+	todopb.ErrorReason_DEPENDENCY_NOT_PRESENT: todo.ErrDependencyNotPresent,
+	todopb.ErrorReason_INVALID_RECURRENCE:     todo.ErrInvalidRecurrence,
+	todopb.ErrorReason_PROJECT_NOT_FOUND:      todo.ErrProjectNotFound,
+	todopb.ErrorReason_PROJECT_NAME_TAKEN:     todo.ErrProjectNameTaken,
+	todopb.ErrorReason_PROJECT_ARCHIVED:       todo.ErrProjectArchived,
+	todopb.ErrorReason_PROJECT_NOT_EMPTY:      todo.ErrProjectNotEmpty,
+	todopb.ErrorReason_PROJECT_UNCHANGED:      todo.ErrProjectUnchanged,
+	todopb.ErrorReason_INVALID_PROJECT_NAME:   todo.ErrInvalidProjectName,
+	todopb.ErrorReason_UNAUTHENTICATED:        todo.ErrUnauthenticated,
+}
+
+// Error is a failed RPC. It keeps the server's message for display and, when
+// the status carried an ErrorInfo, its reason and metadata. It unwraps to the
+// todo sentinel named by the reason, so errors.Is works across the gRPC
+// boundary.
+type Error struct {
+	Code codes.Code
+	// Reason is the ErrorInfo reason, e.g. "TITLE_TOO_LONG", or empty if
+	// the server attached none.
+	Reason string
+	// Metadata holds the ErrorInfo metadata, e.g. "id" and
+	// "max_title_length".
+	Metadata map[string]string
+
+	msg      string
+	sentinel error
+}
+
+func (e *Error) Error() string { return e.msg }
+func (e *Error) Unwrap() error { return e.sentinel }
+
+// ID returns the todo the failed request addressed, if the server reported
+// one.
+func (e *Error) ID() (int, bool) {
+	return e.intMetadata("id")
+}
+
+// Limit returns the limit named key, e.g. "max_title_length", if the server
+// reported it.
+func (e *Error) Limit(key string) (int, bool) {
+	return e.intMetadata(key)
+}
+
+func (e *Error) intMetadata(key string) (int, bool) {
+	v, ok := e.Metadata[key]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+func grpcToDomainError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	e := &Error{Code: st.Code(), msg: st.Message()}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != errorDomain {
+			continue
+		}
+		e.Reason = info.GetReason()
+		e.Metadata = info.GetMetadata()
+		// Reasons this client does not know map to no sentinel.
+		if reason, known := todopb.ErrorReason_value[e.Reason]; known {
+			e.sentinel = reasonToSentinel[todopb.ErrorReason(reason)]
+		}
+		break
+	}
+	return e
+}
@@ -0,0 +1,204 @@
+// Package query parses the todo filter language accepted by List into a
+// backend-neutral syntax tree.
+//
+// A filter is a boolean combination of comparisons:
+//
+//	completed=false AND title~"deploy"
+//	(priority>=high OR tag=urgent) AND NOT due_date=""
+//
+// Comparisons take the form field op value. Operators are =, !=, ~
+// (case-insensitive substring), <, <=, > and >=. AND binds tighter than OR,
+// NOT binds tightest, and parentheses group. Keywords are case-insensitive.
+// Values are bare words or double-quoted strings.
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// Field names a todo attribute that can be filtered on.
+type Field string
+
+const (
+	FieldID          Field = "id"
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldCompleted   Field = "completed"
+	FieldDueDate     Field = "due_date"
+	FieldDueTime     Field = "due_time"
+	FieldPriority    Field = "priority"
+	FieldTag         Field = "tag"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEq       Op = "="
+	OpNe       Op = "!="
+	OpContains Op = "~"
+	OpLt       Op = "<"
+	OpLe       Op = "<="
+	OpGt       Op = ">"
+	OpGe       Op = ">="
+)
+
+// Expr is a node of the filter syntax tree: *And, *Or, *Not or *Comparison.
+type Expr interface {
+	String() string
+	isExpr()
+}
+
+// And matches when both operands match.
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches when either operand matches.
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches when its operand does not.
+type Not struct {
+	X Expr
+}
+
+// Comparison tests a single field. Value holds an int for FieldID, a bool
+// for FieldCompleted, a todo.Priority for FieldPriority and a string for
+// every other field. An empty string compares against an unset due date or
+// due time; tags are normalized with todo.NormalizeTag.
+type Comparison struct {
+	Field Field
+	Op    Op
+	Value any
+}
+
+func (*And) isExpr()        {}
+func (*Or) isExpr()         {}
+func (*Not) isExpr()        {}
+func (*Comparison) isExpr() {}
+
+func (e *And) String() string {
+	return group(e.Left, false) + " AND " + group(e.Right, false)
+}
+
+func (e *Or) String() string {
+	return e.Left.String() + " OR " + e.Right.String()
+}
+
+func (e *Not) String() string {
+	return "NOT " + group(e.X, true)
+}
+
+func (e *Comparison) String() string {
+	var value string
+	switch v := e.Value.(type) {
+	case string:
+		value = quote(v)
+	case int:
+		value = strconv.Itoa(v)
+	case bool:
+		value = strconv.FormatBool(v)
+	case todo.Priority:
+		value = v.String()
+	}
+	return string(e.Field) + string(e.Op) + value
+}
+
+// quote renders s as a filter string literal, escaping only what lexString
+// unescapes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// group parenthesizes e when printing it bare would change how it parses:
+// an Or inside an And, or any binary node under NOT.
+func group(e Expr, underNot bool) string {
+	switch e.(type) {
+	case *Or:
+		return "(" + e.String() + ")"
+	case *And:
+		if underNot {
+			return "(" + e.String() + ")"
+		}
+	}
+	return e.String()
+}
+
+// Match reports whether t satisfies e. A nil e matches every todo. Backends
+// without a native query engine can evaluate filters with it directly.
+func Match(e Expr, t todo.Todo) bool {
+	switch e := e.(type) {
+	case nil:
+		return true
+	case *And:
+		return Match(e.Left, t) && Match(e.Right, t)
+	case *Or:
+		return Match(e.Left, t) || Match(e.Right, t)
+	case *Not:
+		return !Match(e.X, t)
+	case *Comparison:
+		return e.match(t)
+	}
+	return false
+}
+
+func (c *Comparison) match(t todo.Todo) bool {
+	switch c.Field {
+	case FieldID:
+		return compare(c.Op, t.ID, c.Value.(int))
+	case FieldTitle:
+		return matchString(c.Op, t.Title, c.Value.(string))
+	case FieldDescription:
+		return matchString(c.Op, t.Description, c.Value.(string))
+	case FieldCompleted:
+		return (t.Completed == c.Value.(bool)) == (c.Op == OpEq)
+	case FieldDueDate:
+		return compareOptional(c.Op, t.DueDate, c.Value.(string))
+	case FieldDueTime:
+		return compareOptional(c.Op, t.DueTime, c.Value.(string))
+	case FieldPriority:
+		return compare(c.Op, t.Priority, c.Value.(todo.Priority))
+	case FieldTag:
+		return t.HasTags([]string{c.Value.(string)}) == (c.Op == OpEq)
+	}
+	return false
+}
+
+func matchString(op Op, have, want string) bool {
+	if op == OpContains {
+		return strings.Contains(strings.ToLower(have), strings.ToLower(want))
+	}
+	return compare(op, have, want)
+}
+
+// compareOptional orders a field that may be unset. Like a missing field in
+// a database, an unset value only satisfies = "" and != <anything else>.
+func compareOptional(op Op, have, want string) bool {
+	if (have == "" || want == "") && op != OpEq && op != OpNe {
+		return false
+	}
+	return compare(op, have, want)
+}
+
+func compare[T int | string | todo.Priority](op Op, have, want T) bool {
+	switch op {
+	case OpEq:
+		return have == want
+	case OpNe:
+		return have != want
+	case OpLt:
+		return have < want
+	case OpLe:
+		return have <= want
+	case OpGt:
+		return have > want
+	case OpGe:
+		return have >= want
+	}
+	return false
+}
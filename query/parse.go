@@ -0,0 +1,335 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// MaxFilterLength bounds the size of a filter expression.
+const MaxFilterLength = 1000
+
+// fieldAliases maps accepted spellings to their canonical field.
+var fieldAliases = map[string]Field{
+	"id":          FieldID,
+	"title":       FieldTitle,
+	"description": FieldDescription,
+	"desc":        FieldDescription,
+	"completed":   FieldCompleted,
+	"done":        FieldCompleted,
+	"due_date":    FieldDueDate,
+	"due":         FieldDueDate,
+	"due_time":    FieldDueTime,
+	"priority":    FieldPriority,
+	"tag":         FieldTag,
+	"tags":        FieldTag,
+}
+
+// fieldOps lists the operators each field supports.
+var fieldOps = map[Field][]Op{
+	FieldID:          {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+	FieldTitle:       {OpEq, OpNe, OpContains},
+	FieldDescription: {OpEq, OpNe, OpContains},
+	FieldCompleted:   {OpEq, OpNe},
+	FieldDueDate:     {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+	FieldDueTime:     {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+	FieldPriority:    {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+	FieldTag:         {OpEq, OpNe},
+}
+
+// Parse parses a filter expression. An empty or all-whitespace filter
+// yields a nil Expr, which matches every todo. Errors wrap
+// todo.ErrInvalidFilter.
+func Parse(filter string) (Expr, error) {
+	if len(filter) > MaxFilterLength {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", todo.ErrInvalidFilter, len(filter), MaxFilterLength)
+	}
+	tokens, err := lex(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, nil
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("%w: at position %d: %s", todo.ErrInvalidFilter, tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("NOT"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	case tok.kind == tokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected ) but found %s", closing)
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	name := p.next()
+	if name.kind != tokWord || name.isKeyword("AND") || name.isKeyword("OR") {
+		return nil, p.errorf(name, "expected a field name but found %s", name)
+	}
+	field, ok := fieldAliases[strings.ToLower(name.text)]
+	if !ok {
+		return nil, p.errorf(name, "unknown field %q", name.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, p.errorf(opTok, "expected an operator after %s but found %s", field, opTok)
+	}
+	op := Op(opTok.text)
+	if !supports(field, op) {
+		return nil, p.errorf(opTok, "operator %s is not supported for %s", op, field)
+	}
+
+	valTok := p.next()
+	if valTok.kind != tokWord && valTok.kind != tokString {
+		return nil, p.errorf(valTok, "expected a value after %s%s but found %s", field, op, valTok)
+	}
+	value, err := parseValue(field, valTok.text)
+	if err != nil {
+		return nil, p.errorf(valTok, "%v", err)
+	}
+	return &Comparison{Field: field, Op: op, Value: value}, nil
+}
+
+func supports(field Field, op Op) bool {
+	for _, allowed := range fieldOps[field] {
+		if op == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func parseValue(field Field, raw string) (any, error) {
+	switch field {
+	case FieldID:
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("id must be a number, got %q", raw)
+		}
+		return id, nil
+	case FieldCompleted:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("completed must be true or false, got %q", raw)
+		}
+		return b, nil
+	case FieldDueDate:
+		if raw == "" {
+			return "", nil
+		}
+		if err := todo.ValidateDue(raw, ""); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	case FieldDueTime:
+		if raw == "" {
+			return "", nil
+		}
+		// Any valid date will do; only the time is being checked.
+		if err := todo.ValidateDue("2000-01-01", raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	case FieldPriority:
+		priority, err := todo.ParsePriority(raw)
+		if err != nil {
+			return nil, err
+		}
+		return priority, nil
+	case FieldTag:
+		tag := todo.NormalizeTag(strings.TrimPrefix(raw, "#"))
+		if err := todo.ValidateTag(tag); err != nil {
+			return nil, err
+		}
+		return tag, nil
+	}
+	return raw, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == '=' || c == '~':
+			tokens = append(tokens, token{tokOp, string(c), i})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			if i+1 < len(s) && s[i+1] == '=' {
+				tokens = append(tokens, token{tokOp, s[i : i+2], i})
+				i += 2
+				continue
+			}
+			if c == '!' {
+				return nil, fmt.Errorf("%w: at position %d: expected !=", todo.ErrInvalidFilter, i+1)
+			}
+			tokens = append(tokens, token{tokOp, string(c), i})
+			i++
+		case c == '"':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: at position %d: %v", todo.ErrInvalidFilter, i+1, err)
+			}
+			tokens = append(tokens, token{tokString, text, i})
+			i += n
+		default:
+			start := i
+			for i < len(s) && isWordByte(s[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("%w: at position %d: unexpected character %q", todo.ErrInvalidFilter, i+1, rune(c))
+			}
+			tokens = append(tokens, token{tokWord, s[start:i], start})
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(s)}), nil
+}
+
+// lexString reads a double-quoted string with backslash escapes for \" and
+// \\ from the start of s, returning its contents and the bytes consumed.
+func lexString(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isWordByte(c byte) bool {
+	if c >= 0x80 {
+		return true
+	}
+	r := rune(c)
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:#/", r)
+}
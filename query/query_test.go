@@ -0,0 +1,149 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{`completed=false`, `completed=false`},
+		{`completed=false AND title~"deploy"`, `completed=false AND title~"deploy"`},
+		{`done = 1 and desc ~ prod`, `completed=true AND description~"prod"`},
+		{`priority>=high OR tag=#Urgent`, `priority>=high OR tag="urgent"`},
+		{`(id<3 OR id>10) AND NOT due=""`, `(id<3 OR id>10) AND NOT due_date=""`},
+		{`NOT (completed=true AND priority=none)`, `NOT (completed=true AND priority=none)`},
+		{`title="say \"hi\""`, `title="say \"hi\""`},
+		{`id=1 OR id=2 AND id=3`, `id=1 OR id=2 AND id=3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := e.String(); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+			again, err := Parse(e.String())
+			if err != nil {
+				t.Fatalf("re-Parse of %s: %v", e, err)
+			}
+			if again.String() != e.String() {
+				t.Fatalf("round trip changed %s into %s", e, again)
+			}
+		})
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	e, err := Parse(`id=1 OR id=2 AND id=3`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	or, ok := e.(*Or)
+	if !ok {
+		t.Fatalf("expected OR at the root, got %T", e)
+	}
+	if _, ok := or.Right.(*And); !ok {
+		t.Fatalf("expected AND to bind tighter than OR, got %T on the right", or.Right)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, filter := range []string{"", "   "} {
+		e, err := Parse(filter)
+		if err != nil || e != nil {
+			t.Fatalf("Parse(%q) = %v, %v; want nil, nil", filter, e, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		`completed`,
+		`completed=`,
+		`completed=maybe`,
+		`colour=red`,
+		`title<"b"`,
+		`tag~work`,
+		`id=abc`,
+		`due_date>tomorrow`,
+		`due_time=25:00`,
+		`priority=asap`,
+		`(completed=true`,
+		`completed=true)`,
+		`completed=true AND`,
+		`title="unterminated`,
+		`title!"x"`,
+		`id=1 id=2`,
+		`title=@`,
+	}
+	for _, filter := range tests {
+		t.Run(filter, func(t *testing.T) {
+			_, err := Parse(filter)
+			if !errors.Is(err, todo.ErrInvalidFilter) {
+				t.Fatalf("expected ErrInvalidFilter, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	deploy := todo.Todo{
+		ID:          3,
+		Title:       "Deploy API",
+		Description: "prod rollout",
+		DueDate:     "2026-03-05",
+		DueTime:     "09:00",
+		Priority:    todo.PriorityHigh,
+		Tags:        []string{"work", "ops"},
+	}
+	chores := todo.Todo{ID: 7, Title: "chores", Completed: true}
+
+	tests := []struct {
+		filter     string
+		deploy     bool
+		choresWant bool
+	}{
+		{``, true, true},
+		{`completed=false AND title~"deploy"`, true, false},
+		{`title="Deploy API"`, true, false},
+		{`title="deploy api"`, false, false},
+		{`description~ROLLOUT`, true, false},
+		{`id>=3 AND id<7`, true, false},
+		{`id!=3`, false, true},
+		{`due_date=""`, false, true},
+		{`due_date!=""`, true, false},
+		{`due_date<2026-04-01`, true, false},
+		{`due_date>2026-01-01`, true, false},
+		{`due_time>=08:00`, true, false},
+		{`priority>medium`, true, false},
+		{`priority<high`, false, true},
+		{`priority=none`, false, true},
+		{`priority!=none`, true, false},
+		{`tag=work AND tag=ops`, true, false},
+		{`tag!=work`, false, true},
+		{`NOT completed=true`, true, false},
+		{`completed=true OR tag=ops`, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := Match(e, deploy); got != tt.deploy {
+				t.Errorf("Match(deploy) = %v, want %v", got, tt.deploy)
+			}
+			if got := Match(e, chores); got != tt.choresWant {
+				t.Errorf("Match(chores) = %v, want %v", got, tt.choresWant)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// Auth authenticates calls by the API key token they carry in their
+// "authorization" metadata, as "Bearer <token>", and scopes each call to
+// the user the key belongs to. A call without a token, or with one that
+// matches no key, is refused as unauthenticated. The x-todo-user metadata
+// is ignored: the key alone says who the caller is.
+type Auth struct {
+	keys todo.KeyStore
+}
+
+// NewAuth returns an Auth that checks tokens against the keys in keys. A
+// revoked key stops working with the next call.
+func NewAuth(keys todo.KeyStore) *Auth {
+	return &Auth{keys: keys}
+}
+
+// UnaryInterceptor authenticates every unary call. Install it, with
+// StreamInterceptor, on the grpc.Server the Server is registered with.
+func (a *Auth) UnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return scopeUnary(ctx, req, handler, a.authenticate)
+}
+
+// StreamInterceptor is UnaryInterceptor for streaming calls.
+func (a *Auth) StreamInterceptor(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return scopeStream(srv, stream, handler, a.authenticate)
+}
+
+// authenticate returns ctx scoped to the user of the key whose token it
+// carries.
+func (a *Auth) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return nil, fmt.Errorf("%w: expected one bearer token, got %d", todo.ErrUnauthenticated, len(values))
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: expected a bearer token", todo.ErrUnauthenticated)
+	}
+	key, err := a.keys.KeyByHash(ctx, todo.HashToken(token))
+	if errors.Is(err, todo.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown or revoked token", todo.ErrUnauthenticated)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return todo.WithOwner(ctx, key.User), nil
+}
@@ -0,0 +1,175 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/amharshit45/todos-cli-/certs"
+	"github.com/amharshit45/todos-cli-/gen/todopb"
+	"github.com/amharshit45/todos-cli-/grpcclient"
+	"github.com/amharshit45/todos-cli-/server"
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// withToken returns ctx carrying token the way grpcclient.TokenCredentials
+// sends it.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// serveAuth starts a server that authenticates calls against the keys in
+// store and dials it with dialOpts.
+func serveAuth(t *testing.T, store *storage.MemoryStorage, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	auth := server.NewAuth(store)
+	_, _, conn := serveWith(t, store, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(auth.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(auth.StreamInterceptor),
+	}, dialOpts...)
+	return conn
+}
+
+// addKey adds an API key for user to store and returns its token.
+func addKey(t *testing.T, store todo.KeyStore, user string) (todo.APIKey, string) {
+	t.Helper()
+	token, hash, err := todo.NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	key, err := store.AddKey(context.Background(), user, hash)
+	if err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+	return key, token
+}
+
+func TestAuth(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	client := todopb.NewTodoServiceClient(serveAuth(t, store))
+	ctx := context.Background()
+	key, token := addKey(t, store, "alice")
+
+	// The key alone names the caller; a user in the metadata changes nothing.
+	resp, err := client.Add(as(withToken(ctx, token), "bob"), &todopb.AddRequest{Title: "alice's"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if got := resp.GetTodo().GetOwner(); got != "alice" {
+		t.Fatalf("expected owner alice, got %q", got)
+	}
+
+	_, bobToken := addKey(t, store, "bob")
+	list, err := client.List(withToken(ctx, bobToken), &todopb.ListRequest{})
+	if err != nil {
+		t.Fatalf("List as bob: %v", err)
+	}
+	if len(list.GetTodos()) != 0 {
+		t.Fatalf("expected bob to see no todos, got %v", list.GetTodos())
+	}
+
+	if err := store.RevokeKey(ctx, key.ID); err != nil {
+		t.Fatalf("RevokeKey: %v", err)
+	}
+	rejected := map[string]context.Context{
+		"no token":      ctx,
+		"only a user":   as(ctx, "alice"),
+		"unknown token": withToken(ctx, todo.TokenPrefix+"nonsense"),
+		"revoked token": withToken(ctx, token),
+		"basic auth":    metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+bobToken),
+		"two tokens":    withToken(withToken(ctx, bobToken), bobToken),
+	}
+	for name, callCtx := range rejected {
+		if _, err := client.List(callCtx, &todopb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%s: expected Unauthenticated, got %v", name, err)
+		}
+	}
+}
+
+func TestAuthCredentials(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	_, token := addKey(t, store, "alice")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	anonymous := grpcclient.NewStorage(serveAuth(t, store))
+	if _, err := anonymous.Add(ctx, todo.Draft{Title: "x"}); !errors.Is(err, todo.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated without a token, got %v", err)
+	}
+
+	// TokenCredentials are never sent in plaintext.
+	_, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(grpcclient.TokenCredentials(token)))
+	if err == nil {
+		t.Fatal("expected token credentials to be refused without TLS")
+	}
+
+	// The token goes with every call, streams included.
+	client := grpcclient.NewStorage(serveAuth(t, store, grpc.WithPerRPCCredentials(grpcclient.LocalTokenCredentials(token))))
+	events, err := client.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	added, err := client.Add(ctx, todo.Draft{Title: "alice's"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if added.Owner != "alice" {
+		t.Fatalf("expected owner alice, got %q", added.Owner)
+	}
+	if got, want := nextEvent(t, events), (todo.Event{Type: todo.EventCreated, ID: added.ID}); got != want {
+		t.Fatalf("got event %+v, want %+v", got, want)
+	}
+}
+
+func TestAuthOverTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := certs.NewCA("test CA")
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, ca.CertPEM(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := ca.IssueServer("bufconn")
+	serverCert, serverKey := writePair(t, dir, "server", certPEM, keyPEM, err)
+	serverConfig, err := certs.ServerConfig(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatalf("ServerConfig: %v", err)
+	}
+	clientConfig, err := certs.ClientConfig(caFile, "", "")
+	if err != nil {
+		t.Fatalf("ClientConfig: %v", err)
+	}
+
+	store := storage.NewMemoryStorage()
+	_, token := addKey(t, store, "alice")
+	auth := server.NewAuth(store)
+	srvOpts := []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(serverConfig)),
+		grpc.ChainUnaryInterceptor(auth.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(auth.StreamInterceptor),
+	}
+	_, _, conn := serveWith(t, store, srvOpts,
+		grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)),
+		grpc.WithPerRPCCredentials(grpcclient.TokenCredentials(token)))
+	added, err := grpcclient.NewStorage(conn).Add(context.Background(), todo.Draft{Title: "alice's"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if added.Owner != "alice" {
+		t.Fatalf("expected owner alice, got %q", added.Owner)
+	}
+}
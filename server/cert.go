@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// withCertUser returns ctx scoped to the user named by the subject common
+// name of the client certificate the call's connection was verified with.
+func withCertUser(ctx context.Context) (context.Context, error) {
+	p, _ := peer.FromContext(ctx)
+	var info credentials.TLSInfo
+	if p != nil {
+		info, _ = p.AuthInfo.(credentials.TLSInfo)
+	}
+	if len(info.State.VerifiedChains) == 0 {
+		return nil, fmt.Errorf("%w: expected a verified client certificate", todo.ErrUnauthenticated)
+	}
+	name := info.State.VerifiedChains[0][0].Subject.CommonName
+	if err := todo.ValidateUser(name); err != nil {
+		return nil, fmt.Errorf("%w: %w in client certificate subject", todo.ErrUnauthenticated, err)
+	}
+	return todo.WithOwner(ctx, name), nil
+}
+
+// CertUnaryInterceptor scopes every unary call to the user its client
+// certificate names in its subject common name. Install it, with
+// CertStreamInterceptor, on a grpc.Server whose TLS credentials require
+// and verify client certificates; a call without one is refused as
+// unauthenticated.
+func CertUnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return scopeUnary(ctx, req, handler, withCertUser)
+}
+
+// CertStreamInterceptor is CertUnaryInterceptor for streaming calls.
+func CertStreamInterceptor(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return scopeStream(srv, stream, handler, withCertUser)
+}
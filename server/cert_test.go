@@ -0,0 +1,102 @@
+package server_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/amharshit45/todos-cli-/certs"
+	"github.com/amharshit45/todos-cli-/gen/todopb"
+	"github.com/amharshit45/todos-cli-/server"
+	"github.com/amharshit45/todos-cli-/storage"
+)
+
+// writePair writes a certificate and its key to dir and returns their paths.
+func writePair(t *testing.T, dir, name string, certPEM, keyPEM []byte, err error) (certFile, keyFile string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("issuing %s: %v", name, err)
+	}
+	certFile, keyFile = filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestCertUsers(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := certs.NewCA("test CA")
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, ca.CertPEM(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := ca.IssueServer("bufconn")
+	serverCert, serverKey := writePair(t, dir, "server", certPEM, keyPEM, err)
+
+	// dial serves store over mutual TLS, or plain TLS without clientCA, and
+	// dials it presenting the certificate issued to user, if any.
+	store := storage.NewMemoryStorage()
+	dial := func(clientCA, user string) todopb.TodoServiceClient {
+		t.Helper()
+		serverConfig, err := certs.ServerConfig(serverCert, serverKey, clientCA)
+		if err != nil {
+			t.Fatalf("ServerConfig: %v", err)
+		}
+		var clientCert, clientKey string
+		if user != "" {
+			certPEM, keyPEM, err := ca.IssueClient(user)
+			clientCert, clientKey = writePair(t, dir, "client", certPEM, keyPEM, err)
+		}
+		clientConfig, err := certs.ClientConfig(caFile, clientCert, clientKey)
+		if err != nil {
+			t.Fatalf("ClientConfig: %v", err)
+		}
+		_, _, conn := serveWith(t, store, []grpc.ServerOption{
+			grpc.Creds(credentials.NewTLS(serverConfig)),
+			grpc.ChainUnaryInterceptor(server.CertUnaryInterceptor),
+			grpc.ChainStreamInterceptor(server.CertStreamInterceptor),
+		}, grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+		return todopb.NewTodoServiceClient(conn)
+	}
+	ctx := context.Background()
+
+	// The certificate alone names the caller; a user in the metadata changes nothing.
+	resp, err := dial(caFile, "alice").Add(as(ctx, "bob"), &todopb.AddRequest{Title: "alice's"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if got := resp.GetTodo().GetOwner(); got != "alice" {
+		t.Fatalf("expected owner alice, got %q", got)
+	}
+	list, err := dial(caFile, "bob").List(ctx, &todopb.ListRequest{})
+	if err != nil {
+		t.Fatalf("List as bob: %v", err)
+	}
+	if len(list.GetTodos()) != 0 {
+		t.Fatalf("expected bob to see no todos, got %v", list.GetTodos())
+	}
+
+	if _, err := dial(caFile, "bad user").List(ctx, &todopb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated for an invalid subject, got %v", err)
+	}
+	// Without client certificates required, TLS alone authenticates no one.
+	if _, err := dial("", "").List(ctx, &todopb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a client certificate, got %v", err)
+	}
+	if _, err := dial(caFile, "").List(ctx, &todopb.ListRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected the handshake to fail without a client certificate, got %v", err)
+	}
+}
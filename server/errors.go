@@ -0,0 +1,113 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/amharshit45/todos-cli-/gen/todopb"
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// errorDomain is the domain of the google.rpc.ErrorInfo attached to every
+// domain error the service returns.
+const errorDomain = "todo.v1"
+
+// domainErrors maps each domain sentinel to its gRPC code and ErrorInfo
+// reason, and names the limit, if any, that the error reports exceeding.
+var domainErrors = []struct {
+	sentinel error
+	code     codes.Code
+	reason   todopb.ErrorReason
+	limitKey string
+	limit    int
+}{
+	{sentinel: todo.ErrNotFound, code: codes.NotFound, reason: todopb.ErrorReason_NOT_FOUND},
+	{sentinel: todo.ErrProjectNotFound, code: codes.NotFound, reason: todopb.ErrorReason_PROJECT_NOT_FOUND},
+
+	{sentinel: todo.ErrProjectNameTaken, code: codes.AlreadyExists, reason: todopb.ErrorReason_PROJECT_NAME_TAKEN},
+
+	{sentinel: todo.ErrAlreadyCompleted, code: codes.FailedPrecondition, reason: todopb.ErrorReason_ALREADY_COMPLETED},
+	{sentinel: todo.ErrAlreadyIncomplete, code: codes.FailedPrecondition, reason: todopb.ErrorReason_ALREADY_INCOMPLETE},
+	{sentinel: todo.ErrTitleUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_TITLE_UNCHANGED},
+	{sentinel: todo.ErrDescriptionUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_DESCRIPTION_UNCHANGED},
+	{sentinel: todo.ErrDueUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_DUE_UNCHANGED},
+	{sentinel: todo.ErrPriorityUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PRIORITY_UNCHANGED},
+	{sentinel: todo.ErrTodoUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_TODO_UNCHANGED},
+	{sentinel: todo.ErrNotInTrash, code: codes.FailedPrecondition, reason: todopb.ErrorReason_NOT_IN_TRASH},
+	{sentinel: todo.ErrParentNotFound, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PARENT_NOT_FOUND},
+	{sentinel: todo.ErrParentCycle, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PARENT_CYCLE},
+	{sentinel: todo.ErrHasSubtasks, code: codes.FailedPrecondition, reason: todopb.ErrorReason_HAS_SUBTASKS},
+	{sentinel: todo.ErrBlocked, code: codes.FailedPrecondition, reason: todopb.ErrorReason_BLOCKED},
+	{sentinel: todo.ErrBlockerNotFound, code: codes.FailedPrecondition, reason: todopb.ErrorReason_BLOCKER_NOT_FOUND},
+	{sentinel: todo.ErrDependencyCycle, code: codes.FailedPrecondition, reason: todopb.ErrorReason_DEPENDENCY_CYCLE},
+	{sentinel: todo.ErrDependencyPresent, code: codes.FailedPrecondition, reason: todopb.ErrorReason_DEPENDENCY_PRESENT},
+	{sentinel: todo.ErrDependencyNotPresent, code: codes.FailedPrecondition, reason: todopb.ErrorReason_DEPENDENCY_NOT_PRESENT},
+	{sentinel: todo.ErrTagAlreadyPresent, code: codes.FailedPrecondition, reason: todopb.ErrorReason_TAG_ALREADY_PRESENT},
+	{sentinel: todo.ErrTagNotPresent, code: codes.FailedPrecondition, reason: todopb.ErrorReason_TAG_NOT_PRESENT},
+	{sentinel: todo.ErrTooManyTags, code: codes.FailedPrecondition, reason: todopb.ErrorReason_TOO_MANY_TAGS, limitKey: "max_tags", limit: todo.MaxTags},
+	{sentinel: todo.ErrProjectArchived, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PROJECT_ARCHIVED},
+	{sentinel: todo.ErrProjectNotEmpty, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PROJECT_NOT_EMPTY},
+	{sentinel: todo.ErrProjectUnchanged, code: codes.FailedPrecondition, reason: todopb.ErrorReason_PROJECT_UNCHANGED},
+
+	{sentinel: todo.ErrVersionConflict, code: codes.Aborted, reason: todopb.ErrorReason_VERSION_CONFLICT},
+
+	{sentinel: todo.ErrUnauthenticated, code: codes.Unauthenticated, reason: todopb.ErrorReason_UNAUTHENTICATED},
+
+	{sentinel: todo.ErrInvalidID, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_ID},
+	{sentinel: todo.ErrEmptyTitle, code: codes.InvalidArgument, reason: todopb.ErrorReason_EMPTY_TITLE},
+	{sentinel: todo.ErrTitleTooLong, code: codes.InvalidArgument, reason: todopb.ErrorReason_TITLE_TOO_LONG, limitKey: "max_title_length", limit: todo.MaxTitleLength},
+	{sentinel: todo.ErrDescriptionTooLong, code: codes.InvalidArgument, reason: todopb.ErrorReason_DESCRIPTION_TOO_LONG, limitKey: "max_description_length", limit: todo.MaxDescriptionLength},
+	{sentinel: todo.ErrInvalidDueDate, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_DUE_DATE},
+	{sentinel: todo.ErrInvalidDueTime, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_DUE_TIME},
+	{sentinel: todo.ErrDueTimeWithoutDate, code: codes.InvalidArgument, reason: todopb.ErrorReason_DUE_TIME_WITHOUT_DATE},
+	{sentinel: todo.ErrInvalidPriority, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_PRIORITY},
+	{sentinel: todo.ErrInvalidTag, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_TAG, limitKey: "max_tag_length", limit: todo.MaxTagLength},
+	{sentinel: todo.ErrInvalidRecurrence, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_RECURRENCE},
+	{sentinel: todo.ErrInvalidProjectName, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_PROJECT_NAME, limitKey: "max_project_name_length", limit: todo.MaxProjectNameLength},
+	{sentinel: todo.ErrInvalidFilter, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_FILTER, limitKey: "max_filter_length", limit: query.MaxFilterLength},
+	{sentinel: todo.ErrInvalidPageSize, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_PAGE_SIZE, limitKey: "max_page_size", limit: todo.MaxPageSize},
+	{sentinel: todo.ErrInvalidPageToken, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_PAGE_TOKEN},
+	{sentinel: todo.ErrInvalidUpdateMask, code: codes.InvalidArgument, reason: todopb.ErrorReason_INVALID_UPDATE_MASK},
+}
+
+// domainToGRPCError converts a storage error into a gRPC status carrying an
+// ErrorInfo. id is the todo the request addressed, or 0 if it addressed
+// none. Errors that wrap no sentinel are logged and reported as Internal so
+// that their details do not leak to clients.
+func domainToGRPCError(err error, id int32) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, d := range domainErrors {
+		if !errors.Is(err, d.sentinel) {
+			continue
+		}
+		info := &errdetails.ErrorInfo{
+			Reason:   d.reason.String(),
+			Domain:   errorDomain,
+			Metadata: make(map[string]string),
+		}
+		if id != 0 {
+			info.Metadata["id"] = strconv.Itoa(int(id))
+		}
+		if d.limitKey != "" {
+			info.Metadata[d.limitKey] = strconv.Itoa(d.limit)
+		}
+		st, detailErr := status.New(d.code, err.Error()).WithDetails(info)
+		if detailErr != nil {
+			log.Printf("attaching error details: %v", detailErr)
+			return status.Error(d.code, err.Error())
+		}
+		return st.Err()
+	}
+
+	log.Printf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal server error")
+}
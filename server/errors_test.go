@@ -0,0 +1,168 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/amharshit45/todos-cli-/gen/todopb"
+	"github.com/amharshit45/todos-cli-/grpcclient"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// failingStorage fails every Delete with err.
+type failingStorage struct {
+	todo.Storage
+	err error
+}
+
+func (f *failingStorage) Delete(context.Context, int) error { return f.err }
+
+var sentinels = []error{
+	todo.ErrNotFound,
+	todo.ErrAlreadyCompleted,
+	todo.ErrAlreadyIncomplete,
+	todo.ErrTitleUnchanged,
+	todo.ErrDescriptionUnchanged,
+	todo.ErrDueUnchanged,
+	todo.ErrPriorityUnchanged,
+	todo.ErrTodoUnchanged,
+	todo.ErrNotInTrash,
+	todo.ErrParentNotFound,
+	todo.ErrParentCycle,
+	todo.ErrHasSubtasks,
+	todo.ErrBlocked,
+	todo.ErrBlockerNotFound,
+	todo.ErrDependencyCycle,
+	todo.ErrDependencyPresent,
+	todo.ErrDependencyNotPresent,
+	todo.ErrTagAlreadyPresent,
+	todo.ErrTagNotPresent,
+	todo.ErrTooManyTags,
+	todo.ErrInvalidID,
+	todo.ErrEmptyTitle,
+	todo.ErrTitleTooLong,
+	todo.ErrDescriptionTooLong,
+	todo.ErrInvalidDueDate,
+	todo.ErrInvalidDueTime,
+	todo.ErrDueTimeWithoutDate,
+	todo.ErrInvalidPriority,
+	todo.ErrInvalidTag,
+	todo.ErrInvalidRecurrence,
+	todo.ErrInvalidProjectName,
+	todo.ErrProjectNotFound,
+	todo.ErrProjectNameTaken,
+	todo.ErrProjectArchived,
+	todo.ErrProjectNotEmpty,
+	todo.ErrProjectUnchanged,
+	todo.ErrInvalidFilter,
+	todo.ErrInvalidPageSize,
+	todo.ErrInvalidPageToken,
+	todo.ErrInvalidUpdateMask,
+	todo.ErrVersionConflict,
+	todo.ErrUnauthenticated,
+}
+
+func TestErrorInfo(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	ctx := context.Background()
+
+	_, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: strings.Repeat("x", todo.MaxTitleLength+1)})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected an ErrorInfo detail, got %v", st.Details())
+	}
+	if info.GetReason() != "TITLE_TOO_LONG" || info.GetDomain() != "todo.v1" {
+		t.Errorf("unexpected reason %q in domain %q", info.GetReason(), info.GetDomain())
+	}
+	want := map[string]string{"id": "1", "max_title_length": fmt.Sprint(todo.MaxTitleLength)}
+	if fmt.Sprint(info.GetMetadata()) != fmt.Sprint(want) {
+		t.Errorf("expected metadata %v, got %v", want, info.GetMetadata())
+	}
+}
+
+// TestErrorReasonsRoundTrip checks that every sentinel survives the trip
+// through the server and grpcclient, and only that sentinel, even when the
+// message mentions another one.
+func TestErrorReasonsRoundTrip(t *testing.T) {
+	for _, sentinel := range sentinels {
+		t.Run(sentinel.Error(), func(t *testing.T) {
+			decoy := todo.ErrNotFound
+			if sentinel == todo.ErrNotFound {
+				decoy = todo.ErrInvalidFilter
+			}
+			_, _, conn := serve(t, &failingStorage{err: fmt.Errorf("todo 7: %w (not %v)", sentinel, decoy)})
+			store := grpcclient.NewStorage(conn)
+
+			err := store.Delete(context.Background(), 7)
+			if !errors.Is(err, sentinel) {
+				t.Fatalf("expected %v, got: %v", sentinel, err)
+			}
+			if errors.Is(err, decoy) {
+				t.Errorf("expected no match for %v, got: %v", decoy, err)
+			}
+			var rpcErr *grpcclient.Error
+			if !errors.As(err, &rpcErr) {
+				t.Fatalf("expected a *grpcclient.Error, got %T", err)
+			}
+			if id, ok := rpcErr.ID(); !ok || id != 7 {
+				t.Errorf("expected ID 7, got %d (reported: %v)", id, ok)
+			}
+			if !strings.Contains(rpcErr.Error(), "todo 7: ") {
+				t.Errorf("expected the server message to be kept, got %q", rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestErrorLimits(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	store := grpcclient.NewStorage(env.conn)
+
+	_, err := store.EditDescription(context.Background(), 1, strings.Repeat("x", todo.MaxDescriptionLength+1))
+	var rpcErr *grpcclient.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected a *grpcclient.Error, got %T: %v", err, err)
+	}
+	if rpcErr.Reason != "DESCRIPTION_TOO_LONG" {
+		t.Errorf("expected reason DESCRIPTION_TOO_LONG, got %q", rpcErr.Reason)
+	}
+	if limit, ok := rpcErr.Limit("max_description_length"); !ok || limit != todo.MaxDescriptionLength {
+		t.Errorf("expected limit %d, got %d (reported: %v)", todo.MaxDescriptionLength, limit, ok)
+	}
+	if _, ok := rpcErr.Limit("max_title_length"); ok {
+		t.Error("expected no title limit")
+	}
+}
+
+func TestInternalErrorHasNoReason(t *testing.T) {
+	_, _, conn := serve(t, &failingStorage{err: errors.New("disk on fire")})
+	store := grpcclient.NewStorage(conn)
+
+	err := store.Delete(context.Background(), 7)
+	var rpcErr *grpcclient.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected a *grpcclient.Error, got %T: %v", err, err)
+	}
+	if rpcErr.Code != codes.Internal || rpcErr.Reason != "" || errors.Unwrap(rpcErr) != nil {
+		t.Errorf("expected a bare Internal error, got %+v", rpcErr)
+	}
+	if strings.Contains(err.Error(), "disk on fire") {
+		t.Errorf("expected internal details to stay on the server, got %q", err)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it is dropped.
+const subscriberBuffer = 64
+
+// eventBus fans out change events from the mutating handlers to the Watch
+// streams of the user whose todo changed. Publishing never blocks: a
+// subscriber whose buffer is full is dropped and its channel closed, so one
+// slow client cannot stall writes for everyone else.
+type eventBus struct {
+	mu     sync.Mutex
+	subs   map[chan todo.Event]string // to the owner subscribed to
+	closed bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[chan todo.Event]string)}
+}
+
+// subscribe registers a new subscriber to the events of owner's todos. The
+// returned cancel function unregisters it and is safe to call more than
+// once.
+func (b *eventBus) subscribe(owner string) (<-chan todo.Event, func()) {
+	ch := make(chan todo.Event, subscriberBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = owner
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.drop(ch)
+	}
+}
+
+// publish sends e, about a todo of owner, to owner's subscribers.
+func (b *eventBus) publish(owner string, e todo.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, subscriber := range b.subs {
+		if subscriber != owner {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+			b.drop(ch)
+		}
+	}
+}
+
+// close ends every subscription and makes later subscriptions end at once.
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		b.drop(ch)
+	}
+}
+
+func (b *eventBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// drop must be called with b.mu held.
+func (b *eventBus) drop(ch chan todo.Event) {
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
@@ -2,11 +2,12 @@ package server
 
 import (
 	"context"
-	"errors"
-	"log"
+	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/amharshit45/todos-cli-/gen/todopb"
 	"github.com/amharshit45/todos-cli-/todo"
@@ -14,88 +15,368 @@ import (
 
 type Server struct {
 	todopb.UnimplementedTodoServiceServer
-	store todo.Storage
+	store  todo.Storage
+	events *eventBus
 }
 
 func New(store todo.Storage) *Server {
-	return &Server{store: store}
+	return &Server{store: store, events: newEventBus()}
+}
+
+// Close ends every open Watch stream so that a graceful stop does not wait
+// on them. The server keeps serving unary RPCs, but new Watch calls fail.
+func (s *Server) Close() {
+	s.events.close()
 }
 
 func (s *Server) Add(ctx context.Context, req *todopb.AddRequest) (*todopb.AddResponse, error) {
-	if err := s.store.Add(ctx, req.GetTitle(), req.GetDescription()); err != nil {
-		return nil, domainToGRPCError(err)
+	draft := todo.Draft{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		DueDate:     req.GetDueDate(),
+		DueTime:     req.GetDueTime(),
+		Priority:    todo.Priority(req.GetPriority()),
+		Tags:        req.GetTags(),
+		ParentID:    int(req.GetParentId()),
+		ProjectID:   int(req.GetProjectId()),
+		Recurrence:  req.GetRecurrence(),
+	}
+	added, err := s.store.Add(ctx, draft)
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
 	}
-	return &todopb.AddResponse{}, nil
+	s.publish(ctx, todo.Event{Type: todo.EventCreated, ID: added.ID})
+	return &todopb.AddResponse{Todo: toPB(added)}, nil
 }
 
-func (s *Server) List(ctx context.Context, _ *todopb.ListRequest) (*todopb.ListResponse, error) {
-	todos, err := s.store.List(ctx)
+func (s *Server) List(ctx context.Context, req *todopb.ListRequest) (*todopb.ListResponse, error) {
+	todos, next, err := s.store.List(ctx, todo.ListOptions{
+		Tags:      req.GetTags(),
+		Filter:    req.GetFilter(),
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		Trashed:   req.GetTrashed(),
+		ProjectID: int(req.GetProjectId()),
+	})
 	if err != nil {
-		return nil, domainToGRPCError(err)
+		return nil, domainToGRPCError(err, 0)
 	}
 	pbTodos := make([]*todopb.Todo, len(todos))
 	for i, t := range todos {
-		pbTodos[i] = &todopb.Todo{
-			Id:          int32(t.ID),
-			Title:       t.Title,
-			Description: t.Description,
-			Completed:   t.Completed,
-		}
+		pbTodos[i] = toPB(t)
 	}
-	return &todopb.ListResponse{Todos: pbTodos}, nil
+	return &todopb.ListResponse{Todos: pbTodos, NextPageToken: next}, nil
 }
 
 func (s *Server) Delete(ctx context.Context, req *todopb.DeleteRequest) (*todopb.DeleteResponse, error) {
-	if err := s.store.Delete(ctx, int(req.GetId())); err != nil {
-		return nil, domainToGRPCError(err)
+	ctx, id := expecting(ctx, req.GetExpectedVersion()), int(req.GetId())
+	deleted := 1
+	var err error
+	if req.GetCascade() {
+		deleted, err = s.store.DeleteTree(ctx, id)
+	} else {
+		err = s.store.Delete(ctx, id)
+	}
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
 	}
-	return &todopb.DeleteResponse{}, nil
+	// Watchers learn of the todo they can see go; its subtasks went with it.
+	s.publish(ctx, todo.Event{Type: todo.EventDeleted, ID: id})
+	return &todopb.DeleteResponse{Deleted: int32(deleted)}, nil
+}
+
+func (s *Server) Restore(ctx context.Context, req *todopb.RestoreRequest) (*todopb.RestoreResponse, error) {
+	restored, err := s.store.Restore(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()))
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publish(ctx, todo.Event{Type: todo.EventRestored, ID: restored.ID})
+	return &todopb.RestoreResponse{Todo: toPB(restored)}, nil
+}
+
+// PurgeTrash publishes no events: purged todos already left listings when
+// they were deleted.
+func (s *Server) PurgeTrash(ctx context.Context, _ *todopb.PurgeTrashRequest) (*todopb.PurgeTrashResponse, error) {
+	n, err := s.store.PurgeTrash(ctx)
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return &todopb.PurgeTrashResponse{Purged: int32(n)}, nil
 }
 
 func (s *Server) SetCompleted(ctx context.Context, req *todopb.SetCompletedRequest) (*todopb.SetCompletedResponse, error) {
-	if err := s.store.SetCompleted(ctx, int(req.GetId()), req.GetCompleted()); err != nil {
-		return nil, domainToGRPCError(err)
+	updated, err := s.store.SetCompleted(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetCompleted())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
 	}
-	return &todopb.SetCompletedResponse{}, nil
+	s.publishUpdated(ctx, updated)
+	return &todopb.SetCompletedResponse{Todo: toPB(updated)}, nil
 }
 
 func (s *Server) EditTitle(ctx context.Context, req *todopb.EditTitleRequest) (*todopb.EditTitleResponse, error) {
-	if err := s.store.EditTitle(ctx, int(req.GetId()), req.GetTitle()); err != nil {
-		return nil, domainToGRPCError(err)
+	updated, err := s.store.EditTitle(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetTitle())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
 	}
-	return &todopb.EditTitleResponse{}, nil
+	s.publishUpdated(ctx, updated)
+	return &todopb.EditTitleResponse{Todo: toPB(updated)}, nil
 }
 
 func (s *Server) EditDescription(ctx context.Context, req *todopb.EditDescriptionRequest) (*todopb.EditDescriptionResponse, error) {
-	if err := s.store.EditDescription(ctx, int(req.GetId()), req.GetDescription()); err != nil {
-		return nil, domainToGRPCError(err)
+	updated, err := s.store.EditDescription(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetDescription())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.EditDescriptionResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) EditDue(ctx context.Context, req *todopb.EditDueRequest) (*todopb.EditDueResponse, error) {
+	updated, err := s.store.EditDue(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetDueDate(), req.GetDueTime())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.EditDueResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) EditPriority(ctx context.Context, req *todopb.EditPriorityRequest) (*todopb.EditPriorityResponse, error) {
+	updated, err := s.store.EditPriority(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), todo.Priority(req.GetPriority()))
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.EditPriorityResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) AddTag(ctx context.Context, req *todopb.AddTagRequest) (*todopb.AddTagResponse, error) {
+	updated, err := s.store.AddTag(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetTag())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.AddTagResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) RemoveTag(ctx context.Context, req *todopb.RemoveTagRequest) (*todopb.RemoveTagResponse, error) {
+	updated, err := s.store.RemoveTag(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), req.GetTag())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.RemoveTagResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) AddDependency(ctx context.Context, req *todopb.AddDependencyRequest) (*todopb.AddDependencyResponse, error) {
+	updated, err := s.store.AddDependency(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), int(req.GetBlockerId()))
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
 	}
-	return &todopb.EditDescriptionResponse{}, nil
+	s.publishUpdated(ctx, updated)
+	return &todopb.AddDependencyResponse{Todo: toPB(updated)}, nil
 }
 
-func domainToGRPCError(err error) error {
-	if err == nil {
+func (s *Server) RemoveDependency(ctx context.Context, req *todopb.RemoveDependencyRequest) (*todopb.RemoveDependencyResponse, error) {
+	updated, err := s.store.RemoveDependency(expecting(ctx, req.GetExpectedVersion()), int(req.GetId()), int(req.GetBlockerId()))
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.RemoveDependencyResponse{Todo: toPB(updated)}, nil
+}
+
+func (s *Server) Update(ctx context.Context, req *todopb.UpdateRequest) (*todopb.UpdateResponse, error) {
+	updated, err := s.store.Update(expecting(ctx, req.GetExpectedVersion()), fromPB(req.GetTodo()), req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, domainToGRPCError(err, req.GetTodo().GetId())
+	}
+	s.publishUpdated(ctx, updated)
+	return &todopb.UpdateResponse{Todo: toPB(updated)}, nil
+}
+
+// The project RPCs pass no ID to domainToGRPCError, whose "id" metadata
+// names a todo.
+
+func (s *Server) CreateProject(ctx context.Context, req *todopb.CreateProjectRequest) (*todopb.CreateProjectResponse, error) {
+	p, err := s.store.CreateProject(ctx, req.GetName())
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return &todopb.CreateProjectResponse{Project: projectPB(p)}, nil
+}
+
+func (s *Server) ListProjects(ctx context.Context, _ *todopb.ListProjectsRequest) (*todopb.ListProjectsResponse, error) {
+	projects, err := s.store.ListProjects(ctx)
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	pbProjects := make([]*todopb.Project, len(projects))
+	for i, p := range projects {
+		pbProjects[i] = projectPB(p)
+	}
+	return &todopb.ListProjectsResponse{Projects: pbProjects}, nil
+}
+
+func (s *Server) RenameProject(ctx context.Context, req *todopb.RenameProjectRequest) (*todopb.RenameProjectResponse, error) {
+	p, err := s.store.RenameProject(ctx, int(req.GetId()), req.GetName())
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return &todopb.RenameProjectResponse{Project: projectPB(p)}, nil
+}
+
+func (s *Server) ArchiveProject(ctx context.Context, req *todopb.ArchiveProjectRequest) (*todopb.ArchiveProjectResponse, error) {
+	p, err := s.store.ArchiveProject(ctx, int(req.GetId()), req.GetArchived())
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return &todopb.ArchiveProjectResponse{Project: projectPB(p)}, nil
+}
+
+func (s *Server) DeleteProject(ctx context.Context, req *todopb.DeleteProjectRequest) (*todopb.DeleteProjectResponse, error) {
+	if err := s.store.DeleteProject(ctx, int(req.GetId())); err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return &todopb.DeleteProjectResponse{}, nil
+}
+
+// Watch streams the change events of the calling user's todos until the
+// client goes away or the server is closed. It sends the response header
+// once the subscription is live, so a client that waits for it misses no
+// later change.
+func (s *Server) Watch(_ *todopb.WatchRequest, stream todopb.TodoService_WatchServer) error {
+	events, cancel := s.events.subscribe(todo.Owner(stream.Context()))
+	defer cancel()
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				if s.events.isClosed() {
+					return status.Error(codes.Unavailable, "server is shutting down")
+				}
+				return status.Error(codes.ResourceExhausted, "watcher fell too far behind")
+			}
+			err := stream.Send(&todopb.WatchResponse{Type: todopb.EventType(e.Type), Id: int32(e.ID)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish sends e to the Watch streams of the user ctx acts for.
+func (s *Server) publish(ctx context.Context, e todo.Event) {
+	s.events.publish(todo.Owner(ctx), e)
+}
+
+// publishUpdated publishes the change to t and, if the same change added
+// t's next occurrence, the occurrence's creation.
+func (s *Server) publishUpdated(ctx context.Context, t todo.Todo) {
+	s.publish(ctx, todo.Event{Type: todo.EventUpdated, ID: t.ID})
+	if t.Recurred {
+		s.publish(ctx, todo.Event{Type: todo.EventCreated, ID: t.NextID})
+	}
+}
+
+// expecting passes a request's expected_version on to the store.
+func expecting(ctx context.Context, version int64) context.Context {
+	return todo.WithExpectedVersion(ctx, int(version))
+}
+
+func toPB(t todo.Todo) *todopb.Todo {
+	return &todopb.Todo{
+		Id:          int32(t.ID),
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		DueDate:     t.DueDate,
+		DueTime:     t.DueTime,
+		Priority:    todopb.Priority(t.Priority),
+		Tags:        t.Tags,
+		Version:     int64(t.Version),
+		DeletedAt:   timestampPB(t.DeletedAt),
+		CreatedAt:   timestampPB(t.CreatedAt),
+		UpdatedAt:   timestampPB(t.UpdatedAt),
+		CompletedAt: timestampPB(t.CompletedAt),
+		ParentId:    int32(t.ParentID),
+		ProjectId:   int32(t.ProjectID),
+		BlockedBy:   int32s(t.BlockedBy),
+		Recurrence:  t.Recurrence,
+		NextId:      int32(t.NextID),
+		Recurred:    t.Recurred,
+		Owner:       t.Owner,
+	}
+}
+
+func fromPB(t *todopb.Todo) todo.Todo {
+	return todo.Todo{
+		ID:          int(t.GetId()),
+		Title:       t.GetTitle(),
+		Description: t.GetDescription(),
+		Completed:   t.GetCompleted(),
+		DueDate:     t.GetDueDate(),
+		DueTime:     t.GetDueTime(),
+		Priority:    todo.Priority(t.GetPriority()),
+		Tags:        t.GetTags(),
+		Version:     int(t.GetVersion()),
+		DeletedAt:   timeFromPB(t.GetDeletedAt()),
+		CreatedAt:   timeFromPB(t.GetCreatedAt()),
+		UpdatedAt:   timeFromPB(t.GetUpdatedAt()),
+		CompletedAt: timeFromPB(t.GetCompletedAt()),
+		ParentID:    int(t.GetParentId()),
+		ProjectID:   int(t.GetProjectId()),
+		BlockedBy:   ints(t.GetBlockedBy()),
+		Recurrence:  t.GetRecurrence(),
+		NextID:      int(t.GetNextId()),
+		Owner:       t.GetOwner(),
+	}
+}
+
+func projectPB(p todo.Project) *todopb.Project {
+	return &todopb.Project{Id: int32(p.ID), Name: p.Name, Archived: p.Archived, Owner: p.Owner}
+}
+
+// timestampPB converts t to a Timestamp, leaving the zero time unset.
+func timestampPB(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
 		return nil
 	}
+	return timestamppb.New(t)
+}
+
+// timeFromPB is the inverse of timestampPB.
+func timeFromPB(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// int32s converts IDs to their wire type.
+func int32s(ids []int) []int32 {
+	if len(ids) == 0 {
+		return nil
+	}
+	out := make([]int32, len(ids))
+	for i, id := range ids {
+		out[i] = int32(id)
+	}
+	return out
+}
 
-	var code codes.Code
-	switch {
-	case errors.Is(err, todo.ErrNotFound):
-		code = codes.NotFound
-	case errors.Is(err, todo.ErrAlreadyCompleted),
-		errors.Is(err, todo.ErrAlreadyIncomplete),
-		errors.Is(err, todo.ErrTitleUnchanged),
-		errors.Is(err, todo.ErrDescriptionUnchanged):
-		code = codes.FailedPrecondition
-	case errors.Is(err, todo.ErrInvalidID),
-		errors.Is(err, todo.ErrEmptyTitle),
-		errors.Is(err, todo.ErrTitleTooLong),
-		errors.Is(err, todo.ErrDescriptionTooLong):
-		code = codes.InvalidArgument
-	default:
-		log.Printf("internal error: %v", err)
-		return status.Error(codes.Internal, "internal server error")
-	}
-
-	return status.Error(code, err.Error())
+// ints converts IDs from their wire type.
+func ints(ids []int32) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	out := make([]int, len(ids))
+	for i, id := range ids {
+		out[i] = int(id)
+	}
+	return out
 }
@@ -5,138 +5,85 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/amharshit45/todos-cli-/gen/todopb"
 	"github.com/amharshit45/todos-cli-/grpcclient"
 	"github.com/amharshit45/todos-cli-/server"
+	"github.com/amharshit45/todos-cli-/storage"
+	"github.com/amharshit45/todos-cli-/storage/storagetest"
 	"github.com/amharshit45/todos-cli-/todo"
 )
 
-type mockStorage struct {
-	todos  []todo.Todo
-	nextID int
-}
-
-func newMockStorage() *mockStorage {
-	return &mockStorage{nextID: 1}
-}
-
-func (m *mockStorage) Add(_ context.Context, title, description string) error {
-	if err := todo.ValidateTitle(title); err != nil {
-		return err
-	}
-	if err := todo.ValidateDescription(description); err != nil {
-		return err
+// listTodos returns every todo in store.
+func listTodos(t *testing.T, store todo.Storage) []todo.Todo {
+	t.Helper()
+	todos, _, err := store.List(context.Background(), todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
 	}
-	m.todos = append(m.todos, todo.Todo{ID: m.nextID, Title: title, Description: description})
-	m.nextID++
-	return nil
+	return todos
 }
 
-func (m *mockStorage) List(_ context.Context) ([]todo.Todo, error) {
-	result := make([]todo.Todo, len(m.todos))
-	copy(result, m.todos)
-	return result, nil
+// errOf drops the todo returned by a Storage method, keeping its error.
+func errOf(_ todo.Todo, err error) error {
+	return err
 }
 
-func (m *mockStorage) Delete(_ context.Context, id int) error {
-	if err := todo.ValidateID(id); err != nil {
-		return err
-	}
-	for i, t := range m.todos {
-		if t.ID == id {
-			m.todos = append(m.todos[:i], m.todos[i+1:]...)
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
+const bufSize = 1024 * 1024
 
-func (m *mockStorage) SetCompleted(_ context.Context, id int, completed bool) error {
-	if err := todo.ValidateID(id); err != nil {
-		return err
-	}
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Completed == completed {
-				if completed {
-					return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
-				}
-				return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
-			}
-			m.todos[i].Completed = completed
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+type testEnv struct {
+	store  *storage.MemoryStorage
+	client todopb.TodoServiceClient
+	conn   *grpc.ClientConn
+	srv    *grpc.Server
+	api    *server.Server
 }
 
-func (m *mockStorage) EditTitle(_ context.Context, id int, title string) error {
-	if err := todo.ValidateID(id); err != nil {
-		return err
-	}
-	if err := todo.ValidateTitle(title); err != nil {
-		return err
-	}
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Title == title {
-				return fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged)
-			}
-			m.todos[i].Title = title
-			return nil
-		}
-	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
-}
+// setup starts a server over bufconn backed by a MemoryStorage holding seed.
+func setup(t *testing.T, seed ...todo.Todo) *testEnv {
+	t.Helper()
 
-func (m *mockStorage) EditDescription(_ context.Context, id int, description string) error {
-	if err := todo.ValidateID(id); err != nil {
-		return err
-	}
-	if err := todo.ValidateDescription(description); err != nil {
-		return err
-	}
-	for i, t := range m.todos {
-		if t.ID == id {
-			if t.Description == description {
-				return fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged)
-			}
-			m.todos[i].Description = description
-			return nil
-		}
+	store := storage.NewMemoryStorage(seed...)
+	api, srv, conn := serve(t, store)
+	return &testEnv{
+		store:  store,
+		client: todopb.NewTodoServiceClient(conn),
+		conn:   conn,
+		srv:    srv,
+		api:    api,
 	}
-	return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
 }
 
-func (m *mockStorage) Close(_ context.Context) error {
-	return nil
-}
-
-const bufSize = 1024 * 1024
-
-type testEnv struct {
-	store  *mockStorage
-	client todopb.TodoServiceClient
-	conn   *grpc.ClientConn
-	srv    *grpc.Server
+// serve starts a server over bufconn backed by store and dials it. The
+// server scopes calls to the user their metadata names.
+func serve(t *testing.T, store todo.Storage) (*server.Server, *grpc.Server, *grpc.ClientConn) {
+	t.Helper()
+	return serveWith(t, store, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(server.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(server.StreamInterceptor),
+	})
 }
 
-func setup(t *testing.T) *testEnv {
+// serveWith is serve with the given server and dial options.
+func serveWith(t *testing.T, store todo.Storage, srvOpts []grpc.ServerOption, dialOpts ...grpc.DialOption) (*server.Server, *grpc.Server, *grpc.ClientConn) {
 	t.Helper()
 
-	store := newMockStorage()
 	lis := bufconn.Listen(bufSize)
 
-	srv := grpc.NewServer()
-	todopb.RegisterTodoServiceServer(srv, server.New(store))
+	srv := grpc.NewServer(srvOpts...)
+	api := server.New(store)
+	todopb.RegisterTodoServiceServer(srv, api)
 
 	go func() {
 		if err := srv.Serve(lis); err != nil {
@@ -144,12 +91,12 @@ func setup(t *testing.T) *testEnv {
 		}
 	}()
 
-	conn, err := grpc.NewClient("passthrough:///bufconn",
+	conn, err := grpc.NewClient("passthrough:///bufconn", append([]grpc.DialOption{
 		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
 			return lis.Dial()
 		}),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	}, dialOpts...)...)
 	if err != nil {
 		t.Fatalf("Failed to dial bufconn: %v", err)
 	}
@@ -158,32 +105,40 @@ func setup(t *testing.T) *testEnv {
 		conn.Close()
 		srv.GracefulStop()
 	})
+	return api, srv, conn
+}
 
-	return &testEnv{
-		store:  store,
-		client: todopb.NewTodoServiceClient(conn),
-		conn:   conn,
-		srv:    srv,
-	}
+// TestGRPCClientConformance runs the storage suite through the full
+// client -> server -> MemoryStorage stack.
+func TestGRPCClientConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) todo.Storage {
+		store := grpcclient.NewStorage(setup(t).conn)
+		t.Cleanup(func() { store.Close(context.Background()) })
+		return store
+	})
 }
 
 func TestAdd(t *testing.T) {
 	env := setup(t)
 	ctx := context.Background()
 
-	_, err := env.client.Add(ctx, &todopb.AddRequest{Title: "buy milk", Description: "from store"})
+	resp, err := env.client.Add(ctx, &todopb.AddRequest{Title: "buy milk", Description: "from store"})
 	if err != nil {
 		t.Fatalf("Add: %v", err)
 	}
+	if got := resp.GetTodo(); got.GetId() != 1 || got.GetTitle() != "buy milk" {
+		t.Fatalf("expected the added todo in the response, got %v", got)
+	}
 
-	if len(env.store.todos) != 1 {
-		t.Fatalf("expected 1 todo, got %d", len(env.store.todos))
+	todos := listTodos(t, env.store)
+	if len(todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(todos))
 	}
-	if env.store.todos[0].Title != "buy milk" {
-		t.Fatalf("expected title 'buy milk', got %q", env.store.todos[0].Title)
+	if todos[0].Title != "buy milk" {
+		t.Fatalf("expected title 'buy milk', got %q", todos[0].Title)
 	}
-	if env.store.todos[0].Description != "from store" {
-		t.Fatalf("expected description 'from store', got %q", env.store.todos[0].Description)
+	if todos[0].Description != "from store" {
+		t.Fatalf("expected description 'from store', got %q", todos[0].Description)
 	}
 }
 
@@ -195,8 +150,9 @@ func TestAddNoDescription(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Add: %v", err)
 	}
-	if env.store.todos[0].Description != "" {
-		t.Fatalf("expected empty description, got %q", env.store.todos[0].Description)
+	todos := listTodos(t, env.store)
+	if todos[0].Description != "" {
+		t.Fatalf("expected empty description, got %q", todos[0].Description)
 	}
 }
 
@@ -218,14 +174,57 @@ func TestAddEmptyTitle(t *testing.T) {
 	}
 }
 
-func TestList(t *testing.T) {
+func TestAddWithDue(t *testing.T) {
+	env := setup(t)
+	ctx := context.Background()
+
+	_, err := env.client.Add(ctx, &todopb.AddRequest{Title: "report", DueDate: "2026-03-01", DueTime: "09:30"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	todos := listTodos(t, env.store)
+	if todos[0].DueDate != "2026-03-01" || todos[0].DueTime != "09:30" {
+		t.Fatalf("unexpected due: %+v", todos[0])
+	}
+
+	resp, err := env.client.List(ctx, &todopb.ListRequest{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := resp.GetTodos()[0]; got.GetDueDate() != "2026-03-01" || got.GetDueTime() != "09:30" {
+		t.Fatalf("unexpected listed due: %+v", got)
+	}
+}
+
+func TestAddInvalidDue(t *testing.T) {
 	env := setup(t)
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{
-		{ID: 1, Title: "task one", Description: "details"},
-		{ID: 2, Title: "task two", Completed: true},
+	tests := []struct {
+		name string
+		req  *todopb.AddRequest
+	}{
+		{"bad date", &todopb.AddRequest{Title: "task", DueDate: "tomorrow"}},
+		{"bad time", &todopb.AddRequest{Title: "task", DueDate: "2026-03-01", DueTime: "25:00"}},
+		{"time without date", &todopb.AddRequest{Title: "task", DueTime: "09:30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.client.Add(ctx, tt.req)
+			st, _ := status.FromError(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
 	}
+}
+
+func TestList(t *testing.T) {
+	env := setup(t,
+		todo.Todo{ID: 1, Title: "task one", Description: "details"},
+		todo.Todo{ID: 2, Title: "task two", Completed: true},
+	)
+	ctx := context.Background()
 
 	resp, err := env.client.List(ctx, &todopb.ListRequest{})
 	if err != nil {
@@ -247,6 +246,63 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListPagination(t *testing.T) {
+	var seed []todo.Todo
+	for i := 1; i <= 5; i++ {
+		seed = append(seed, todo.Todo{ID: i * 2, Title: fmt.Sprintf("task %d", i)})
+	}
+	env := setup(t, seed...)
+	ctx := context.Background()
+
+	var ids []int32
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		resp, err := env.client.List(ctx, &todopb.ListRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(resp.GetTodos()) > 2 {
+			t.Fatalf("page exceeds page size: %d todos", len(resp.GetTodos()))
+		}
+		for _, td := range resp.GetTodos() {
+			ids = append(ids, td.GetId())
+		}
+		token = resp.GetNextPageToken()
+		if token == "" {
+			break
+		}
+	}
+	if fmt.Sprint(ids) != "[2 4 6 8 10]" {
+		t.Fatalf("unexpected IDs across pages: %v", ids)
+	}
+}
+
+func TestListInvalidPagination(t *testing.T) {
+	env := setup(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  *todopb.ListRequest
+	}{
+		{"negative size", &todopb.ListRequest{PageSize: -1}},
+		{"oversized", &todopb.ListRequest{PageSize: todo.MaxPageSize + 1}},
+		{"garbage token", &todopb.ListRequest{PageToken: "not-a-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.client.List(ctx, tt.req)
+			st, _ := status.FromError(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
 func TestListEmpty(t *testing.T) {
 	env := setup(t)
 	ctx := context.Background()
@@ -261,17 +317,57 @@ func TestListEmpty(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "to delete"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "to delete"}}
-
 	_, err := env.client.Delete(ctx, &todopb.DeleteRequest{Id: 1})
 	if err != nil {
 		t.Fatalf("Delete: %v", err)
 	}
-	if len(env.store.todos) != 0 {
-		t.Fatalf("expected 0 todos, got %d", len(env.store.todos))
+	todos := listTodos(t, env.store)
+	if len(todos) != 0 {
+		t.Fatalf("expected 0 todos, got %d", len(todos))
+	}
+}
+
+func TestTrash(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "to delete", Version: 1}, todo.Todo{ID: 2, Title: "to purge", Version: 1})
+	ctx := context.Background()
+
+	for _, id := range []int32{1, 2} {
+		if _, err := env.client.Delete(ctx, &todopb.DeleteRequest{Id: id}); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	resp, err := env.client.List(ctx, &todopb.ListRequest{Trashed: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resp.GetTodos()) != 2 || resp.GetTodos()[0].GetDeletedAt() == nil {
+		t.Fatalf("expected 2 trashed todos with deleted_at set, got %v", resp.GetTodos())
+	}
+
+	restored, err := env.client.Restore(ctx, &todopb.RestoreRequest{Id: 1, ExpectedVersion: 2})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := restored.GetTodo(); got.GetTitle() != "to delete" || got.GetDeletedAt() != nil || got.GetVersion() != 3 {
+		t.Errorf("unexpected restored todo: %v", got)
+	}
+	_, err = env.client.Restore(ctx, &todopb.RestoreRequest{Id: 1})
+	if st, _ := status.FromError(err); st.Code() != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition restoring a live todo, got %v", st.Code())
+	}
+
+	purged, err := env.client.PurgeTrash(ctx, &todopb.PurgeTrashRequest{})
+	if err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if purged.GetPurged() != 1 {
+		t.Errorf("expected 1 todo purged, got %d", purged.GetPurged())
+	}
+	if todos := listTodos(t, env.store); len(todos) != 1 || todos[0].ID != 1 {
+		t.Fatalf("expected only todo 1 left, got %+v", todos)
 	}
 }
 
@@ -306,26 +402,23 @@ func TestDeleteInvalidID(t *testing.T) {
 }
 
 func TestSetCompleted(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "my task"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "my task"}}
-
 	_, err := env.client.SetCompleted(ctx, &todopb.SetCompletedRequest{Id: 1, Completed: true})
 	if err != nil {
 		t.Fatalf("SetCompleted: %v", err)
 	}
-	if !env.store.todos[0].Completed {
+	todos := listTodos(t, env.store)
+	if !todos[0].Completed {
 		t.Fatal("expected todo to be completed")
 	}
 }
 
 func TestSetCompletedAlready(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "done", Completed: true})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "done", Completed: true}}
-
 	_, err := env.client.SetCompleted(ctx, &todopb.SetCompletedRequest{Id: 1, Completed: true})
 	if err == nil {
 		t.Fatal("expected error for already completed")
@@ -353,17 +446,19 @@ func TestSetCompletedNotFound(t *testing.T) {
 }
 
 func TestEditTitle(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "original"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "original"}}
-
-	_, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "updated"})
+	resp, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "updated"})
 	if err != nil {
 		t.Fatalf("EditTitle: %v", err)
 	}
-	if env.store.todos[0].Title != "updated" {
-		t.Fatalf("expected 'updated', got %q", env.store.todos[0].Title)
+	if resp.GetTodo().GetTitle() != "updated" {
+		t.Fatalf("expected the updated todo in the response, got %v", resp.GetTodo())
+	}
+	todos := listTodos(t, env.store)
+	if todos[0].Title != "updated" {
+		t.Fatalf("expected 'updated', got %q", todos[0].Title)
 	}
 }
 
@@ -383,11 +478,9 @@ func TestEditTitleNotFound(t *testing.T) {
 }
 
 func TestEditTitleEmpty(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "original"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "original"}}
-
 	_, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: ""})
 	if err == nil {
 		t.Fatal("expected error for empty title")
@@ -400,11 +493,9 @@ func TestEditTitleEmpty(t *testing.T) {
 }
 
 func TestEditTitleUnchanged(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "same"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "same"}}
-
 	_, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "same"})
 	if err == nil {
 		t.Fatal("expected error for unchanged title")
@@ -417,17 +508,16 @@ func TestEditTitleUnchanged(t *testing.T) {
 }
 
 func TestEditDescription(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "task", Description: "old"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "task", Description: "old"}}
-
 	_, err := env.client.EditDescription(ctx, &todopb.EditDescriptionRequest{Id: 1, Description: "new"})
 	if err != nil {
 		t.Fatalf("EditDescription: %v", err)
 	}
-	if env.store.todos[0].Description != "new" {
-		t.Fatalf("expected 'new', got %q", env.store.todos[0].Description)
+	todos := listTodos(t, env.store)
+	if todos[0].Description != "new" {
+		t.Fatalf("expected 'new', got %q", todos[0].Description)
 	}
 }
 
@@ -447,11 +537,9 @@ func TestEditDescriptionNotFound(t *testing.T) {
 }
 
 func TestEditDescriptionUnchanged(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "task", Description: "same"})
 	ctx := context.Background()
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "task", Description: "same"}}
-
 	_, err := env.client.EditDescription(ctx, &todopb.EditDescriptionRequest{Id: 1, Description: "same"})
 	if err == nil {
 		t.Fatal("expected error for unchanged description")
@@ -463,16 +551,199 @@ func TestEditDescriptionUnchanged(t *testing.T) {
 	}
 }
 
+func TestEditDue(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	ctx := context.Background()
+
+	_, err := env.client.EditDue(ctx, &todopb.EditDueRequest{Id: 1, DueDate: "2026-03-01", DueTime: "09:30"})
+	if err != nil {
+		t.Fatalf("EditDue: %v", err)
+	}
+	todos := listTodos(t, env.store)
+	if todos[0].DueDate != "2026-03-01" || todos[0].DueTime != "09:30" {
+		t.Fatalf("unexpected due: %+v", todos[0])
+	}
+}
+
+func TestEditDueUnchanged(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task", DueDate: "2026-03-01"})
+	ctx := context.Background()
+
+	_, err := env.client.EditDue(ctx, &todopb.EditDueRequest{Id: 1, DueDate: "2026-03-01"})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+}
+
+func TestEditPriority(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	ctx := context.Background()
+
+	_, err := env.client.EditPriority(ctx, &todopb.EditPriorityRequest{Id: 1, Priority: todopb.Priority_PRIORITY_HIGH})
+	if err != nil {
+		t.Fatalf("EditPriority: %v", err)
+	}
+	todos := listTodos(t, env.store)
+	if todos[0].Priority != todo.PriorityHigh {
+		t.Fatalf("expected high priority, got %v", todos[0].Priority)
+	}
+
+	resp, err := env.client.List(ctx, &todopb.ListRequest{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := resp.GetTodos()[0].GetPriority(); got != todopb.Priority_PRIORITY_HIGH {
+		t.Fatalf("expected PRIORITY_HIGH, got %v", got)
+	}
+}
+
+func TestEditPriorityInvalid(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	ctx := context.Background()
+
+	_, err := env.client.EditPriority(ctx, &todopb.EditPriorityRequest{Id: 1, Priority: todopb.Priority(42)})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "old", Description: "old desc", Priority: todo.PriorityLow})
+	ctx := context.Background()
+
+	resp, err := env.client.Update(ctx, &todopb.UpdateRequest{
+		Todo:       &todopb.Todo{Id: 1, Title: "new", Description: "new desc"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "description"}},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := resp.GetTodo(); got.GetTitle() != "new" || got.GetPriority() != todopb.Priority_PRIORITY_LOW {
+		t.Fatalf("expected only the masked fields to change, got %v", got)
+	}
+	todos := listTodos(t, env.store)
+	if todos[0].Title != "new" || todos[0].Description != "new desc" || todos[0].Priority != todo.PriorityLow {
+		t.Fatalf("unexpected todo: %+v", todos[0])
+	}
+}
+
+func TestUpdateInvalidMask(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "old"})
+	ctx := context.Background()
+
+	for _, paths := range [][]string{nil, {"id"}, {"title", "due_date"}} {
+		_, err := env.client.Update(ctx, &todopb.UpdateRequest{
+			Todo:       &todopb.Todo{Id: 1, Title: "new"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+		})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("mask %v: expected InvalidArgument, got %v", paths, st.Code())
+		}
+	}
+	if todos := listTodos(t, env.store); todos[0].Title != "old" {
+		t.Fatalf("expected title to stay 'old', got %q", todos[0].Title)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "original", Version: 3})
+	ctx := context.Background()
+
+	_, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "stale", ExpectedVersion: 2})
+	if st, _ := status.FromError(err); st.Code() != codes.Aborted {
+		t.Fatalf("expected Aborted, got %v", st.Code())
+	}
+	resp, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "fresh", ExpectedVersion: 3})
+	if err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+	if resp.GetTodo().GetVersion() != 4 {
+		t.Fatalf("expected version 4, got %d", resp.GetTodo().GetVersion())
+	}
+}
+
+func TestTagsAndListByTag(t *testing.T) {
+	env := setup(t,
+		todo.Todo{ID: 1, Title: "deploy", Tags: []string{"work"}},
+		todo.Todo{ID: 2, Title: "groceries", Tags: []string{"home"}},
+	)
+	ctx := context.Background()
+
+	if _, err := env.client.AddTag(ctx, &todopb.AddTagRequest{Id: 1, Tag: "Urgent"}); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	resp, err := env.client.List(ctx, &todopb.ListRequest{Tags: []string{"work", "urgent"}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resp.GetTodos()) != 1 || resp.GetTodos()[0].GetId() != 1 {
+		t.Fatalf("expected only todo 1, got %+v", resp.GetTodos())
+	}
+	if got := resp.GetTodos()[0].GetTags(); len(got) != 2 || got[1] != "urgent" {
+		t.Fatalf("unexpected tags: %v", got)
+	}
+
+	if _, err := env.client.RemoveTag(ctx, &todopb.RemoveTagRequest{Id: 1, Tag: "work"}); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	resp, err = env.client.List(ctx, &todopb.ListRequest{Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resp.GetTodos()) != 0 {
+		t.Fatalf("expected no todos tagged work, got %+v", resp.GetTodos())
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	env := setup(t,
+		todo.Todo{ID: 1, Title: "deploy api"},
+		todo.Todo{ID: 2, Title: "deploy docs", Completed: true},
+		todo.Todo{ID: 3, Title: "groceries"},
+	)
+	ctx := context.Background()
+
+	resp, err := env.client.List(ctx, &todopb.ListRequest{Filter: `completed=false AND title~"deploy"`})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(resp.GetTodos()) != 1 || resp.GetTodos()[0].GetId() != 1 {
+		t.Fatalf("expected only todo 1, got %+v", resp.GetTodos())
+	}
+}
+
+func TestListInvalidFilter(t *testing.T) {
+	env := setup(t)
+	ctx := context.Background()
+
+	_, err := env.client.List(ctx, &todopb.ListRequest{Filter: "completed=maybe"})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestAddTagInvalid(t *testing.T) {
+	env := setup(t, todo.Todo{ID: 1, Title: "task"})
+	ctx := context.Background()
+
+	_, err := env.client.AddTag(ctx, &todopb.AddTagRequest{Id: 1, Tag: "two words"})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
 // TestRoundTripErrorMapping verifies domain errors survive the
 // server->gRPC->client round-trip with errors.Is semantics intact.
 func TestRoundTripErrorMapping(t *testing.T) {
-	env := setup(t)
+	env := setup(t, todo.Todo{ID: 1, Title: "task", Description: "desc", Completed: true, Tags: []string{"work"}})
 	ctx := context.Background()
 
 	store := grpcclient.NewStorage(env.conn)
 
-	env.store.todos = []todo.Todo{{ID: 1, Title: "task", Description: "desc", Completed: true}}
-
 	tests := []struct {
 		name     string
 		fn       func() error
@@ -485,7 +756,7 @@ func TestRoundTripErrorMapping(t *testing.T) {
 		},
 		{
 			name:     "already completed",
-			fn:       func() error { return store.SetCompleted(ctx, 1, true) },
+			fn:       func() error { return errOf(store.SetCompleted(ctx, 1, true)) },
 			sentinel: todo.ErrAlreadyCompleted,
 		},
 		{
@@ -495,19 +766,70 @@ func TestRoundTripErrorMapping(t *testing.T) {
 		},
 		{
 			name:     "empty title",
-			fn:       func() error { return store.Add(ctx, "", "desc") },
+			fn:       func() error { return errOf(store.Add(ctx, todo.Draft{Title: "", Description: "desc"})) },
 			sentinel: todo.ErrEmptyTitle,
 		},
 		{
 			name:     "title unchanged",
-			fn:       func() error { return store.EditTitle(ctx, 1, "task") },
+			fn:       func() error { return errOf(store.EditTitle(ctx, 1, "task")) },
 			sentinel: todo.ErrTitleUnchanged,
 		},
 		{
 			name:     "description unchanged",
-			fn:       func() error { return store.EditDescription(ctx, 1, "desc") },
+			fn:       func() error { return errOf(store.EditDescription(ctx, 1, "desc")) },
 			sentinel: todo.ErrDescriptionUnchanged,
 		},
+		{
+			name: "invalid filter",
+			fn: func() error {
+				_, _, err := store.List(ctx, todo.ListOptions{Filter: "due_date=someday"})
+				return err
+			},
+			sentinel: todo.ErrInvalidFilter,
+		},
+		{
+			name: "invalid page token",
+			fn: func() error {
+				_, _, err := store.List(ctx, todo.ListOptions{PageToken: "bogus"})
+				return err
+			},
+			sentinel: todo.ErrInvalidPageToken,
+		},
+		{
+			name:     "tag already present",
+			fn:       func() error { return errOf(store.AddTag(ctx, 1, "work")) },
+			sentinel: todo.ErrTagAlreadyPresent,
+		},
+		{
+			name:     "tag not present",
+			fn:       func() error { return errOf(store.RemoveTag(ctx, 1, "home")) },
+			sentinel: todo.ErrTagNotPresent,
+		},
+		{
+			name:     "invalid tag",
+			fn:       func() error { return errOf(store.AddTag(ctx, 1, "")) },
+			sentinel: todo.ErrInvalidTag,
+		},
+		{
+			name:     "priority unchanged",
+			fn:       func() error { return errOf(store.EditPriority(ctx, 1, todo.PriorityNone)) },
+			sentinel: todo.ErrPriorityUnchanged,
+		},
+		{
+			name:     "due unchanged",
+			fn:       func() error { return errOf(store.EditDue(ctx, 1, "", "")) },
+			sentinel: todo.ErrDueUnchanged,
+		},
+		{
+			name:     "invalid due date",
+			fn:       func() error { return errOf(store.EditDue(ctx, 1, "2026-13-01", "")) },
+			sentinel: todo.ErrInvalidDueDate,
+		},
+		{
+			name:     "due time without date",
+			fn:       func() error { return errOf(store.EditDue(ctx, 1, "", "09:30")) },
+			sentinel: todo.ErrDueTimeWithoutDate,
+		},
 	}
 
 	for _, tt := range tests {
@@ -522,3 +844,201 @@ func TestRoundTripErrorMapping(t *testing.T) {
 		})
 	}
 }
+
+func nextEvent(t *testing.T, events <-chan todo.Event) todo.Event {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("event stream closed early")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return todo.Event{}
+}
+
+func TestWatch(t *testing.T) {
+	env := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := grpcclient.NewStorage(env.conn).Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if _, err := env.client.Add(ctx, &todopb.AddRequest{Title: "buy milk"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := env.client.EditTitle(ctx, &todopb.EditTitleRequest{Id: 1, Title: "buy oat milk"}); err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+	// A failed mutation publishes nothing.
+	if _, err := env.client.Delete(ctx, &todopb.DeleteRequest{Id: 99}); err == nil {
+		t.Fatal("expected Delete of a missing todo to fail")
+	}
+	if _, err := env.client.Delete(ctx, &todopb.DeleteRequest{Id: 1}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := env.client.Restore(ctx, &todopb.RestoreRequest{Id: 1}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	want := []todo.Event{
+		{Type: todo.EventCreated, ID: 1},
+		{Type: todo.EventUpdated, ID: 1},
+		{Type: todo.EventDeleted, ID: 1},
+		{Type: todo.EventRestored, ID: 1},
+	}
+	for _, w := range want {
+		if got := nextEvent(t, events); got != w {
+			t.Fatalf("got event %+v, want %+v", got, w)
+		}
+	}
+}
+
+func TestWatchRecurrence(t *testing.T) {
+	env := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, title := range []string{"water plants", "take out bins"} {
+		if _, err := env.client.Add(ctx, &todopb.AddRequest{Title: title, Recurrence: "FREQ=WEEKLY"}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	events, err := grpcclient.NewStorage(env.conn).Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// Completing a recurring todo adds its next occurrence, #3.
+	if _, err := env.client.SetCompleted(ctx, &todopb.SetCompletedRequest{Id: 1, Completed: true}); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+	// Completed again after reopening, it adds none.
+	for _, completed := range []bool{false, true} {
+		if _, err := env.client.SetCompleted(ctx, &todopb.SetCompletedRequest{Id: 1, Completed: completed}); err != nil {
+			t.Fatalf("SetCompleted(%v): %v", completed, err)
+		}
+	}
+	// Update adds one too, #4.
+	_, err = env.client.Update(ctx, &todopb.UpdateRequest{
+		Todo:       &todopb.Todo{Id: 2, Completed: true},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"completed"}},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	want := []todo.Event{
+		{Type: todo.EventUpdated, ID: 1},
+		{Type: todo.EventCreated, ID: 3},
+		{Type: todo.EventUpdated, ID: 1},
+		{Type: todo.EventUpdated, ID: 1},
+		{Type: todo.EventUpdated, ID: 2},
+		{Type: todo.EventCreated, ID: 4},
+	}
+	for _, w := range want {
+		if got := nextEvent(t, events); got != w {
+			t.Fatalf("got event %+v, want %+v", got, w)
+		}
+	}
+}
+
+func TestWatchEndsOnClose(t *testing.T) {
+	env := setup(t)
+	ctx := context.Background()
+
+	events, err := grpcclient.NewStorage(env.conn).Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	env.api.Close()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("expected no events after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream still open after Close")
+	}
+
+	stream, err := env.client.Watch(ctx, &todopb.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable from a closed server, got %v", err)
+	}
+}
+
+// as returns ctx with its outgoing metadata naming the given user.
+func as(ctx context.Context, user string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, server.UserMetadataKey, user)
+}
+
+func TestUsers(t *testing.T) {
+	env := setup(t)
+	ctx := context.Background()
+
+	resp, err := env.client.Add(as(ctx, "alice"), &todopb.AddRequest{Title: "alice's"})
+	if err != nil {
+		t.Fatalf("Add as alice: %v", err)
+	}
+	if got := resp.GetTodo().GetOwner(); got != "alice" {
+		t.Fatalf("expected owner alice, got %q", got)
+	}
+	for _, user := range []string{"bob", ""} {
+		userCtx := ctx
+		if user != "" {
+			userCtx = as(ctx, user)
+		}
+		list, err := env.client.List(userCtx, &todopb.ListRequest{})
+		if err != nil {
+			t.Fatalf("List as %q: %v", user, err)
+		}
+		if len(list.GetTodos()) != 0 {
+			t.Fatalf("expected %q to see no todos, got %v", user, list.GetTodos())
+		}
+		_, err = env.client.EditTitle(userCtx, &todopb.EditTitleRequest{Id: 1, Title: "mine now"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("expected NotFound editing alice's todo as %q, got %v", user, err)
+		}
+	}
+
+	for _, user := range []string{"bad user", "-alice", strings.Repeat("a", 65)} {
+		_, err := env.client.List(as(ctx, user), &todopb.ListRequest{})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected Unauthenticated for user %q, got %v", user, err)
+		}
+	}
+	twice := metadata.AppendToOutgoingContext(as(ctx, "alice"), server.UserMetadataKey, "bob")
+	if _, err := env.client.List(twice, &todopb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated for two users, got %v", err)
+	}
+}
+
+func TestWatchUsers(t *testing.T) {
+	env := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := grpcclient.NewStorage(env.conn).Watch(todo.WithOwner(ctx, "alice"))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	// Bob's change reaches none of alice's watchers.
+	if _, err := env.client.Add(as(ctx, "bob"), &todopb.AddRequest{Title: "bob's"}); err != nil {
+		t.Fatalf("Add as bob: %v", err)
+	}
+	if _, err := env.client.Add(as(ctx, "alice"), &todopb.AddRequest{Title: "alice's"}); err != nil {
+		t.Fatalf("Add as alice: %v", err)
+	}
+	if got, want := nextEvent(t, events), (todo.Event{Type: todo.EventCreated, ID: 2}); got != want {
+		t.Fatalf("got event %+v, want %+v", got, want)
+	}
+}
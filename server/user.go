@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// UserMetadataKey is the request metadata key naming the calling user.
+// Calls without it act for the shared list.
+const UserMetadataKey = "x-todo-user"
+
+// withUser returns ctx scoped to the user its metadata names, as
+// todo.WithOwner makes it. A malformed or repeated name is refused as
+// unauthenticated rather than taken for the shared list.
+func withUser(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	names := md.Get(UserMetadataKey)
+	switch len(names) {
+	case 0:
+		return todo.WithOwner(ctx, ""), nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: %s given %d times", todo.ErrUnauthenticated, UserMetadataKey, len(names))
+	}
+	if err := todo.ValidateUser(names[0]); err != nil {
+		return nil, fmt.Errorf("%w: %w in %s", todo.ErrUnauthenticated, err, UserMetadataKey)
+	}
+	return todo.WithOwner(ctx, names[0]), nil
+}
+
+// UnaryInterceptor scopes every unary call to the user its metadata names,
+// taking the caller's word for it. Install it, with StreamInterceptor, on
+// the grpc.Server the Server is registered with, unless Auth's or the
+// certificate interceptors are installed instead.
+func UnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return scopeUnary(ctx, req, handler, withUser)
+}
+
+// StreamInterceptor is UnaryInterceptor for streaming calls.
+func StreamInterceptor(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return scopeStream(srv, stream, handler, withUser)
+}
+
+// scopeUnary calls handler with ctx as scope scopes it, or reports the
+// error scope refuses the call with.
+func scopeUnary(ctx context.Context, req any, handler grpc.UnaryHandler, scope func(context.Context) (context.Context, error)) (any, error) {
+	ctx, err := scope(ctx)
+	if err != nil {
+		return nil, domainToGRPCError(err, 0)
+	}
+	return handler(ctx, req)
+}
+
+// scopeStream is scopeUnary for streaming calls.
+func scopeStream(srv any, stream grpc.ServerStream, handler grpc.StreamHandler, scope func(context.Context) (context.Context, error)) error {
+	ctx, err := scope(stream.Context())
+	if err != nil {
+		return domainToGRPCError(err, 0)
+	}
+	return handler(srv, &scopedStream{ServerStream: stream, ctx: ctx})
+}
+
+// scopedStream is a grpc.ServerStream with a replaced context.
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,681 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+var (
+	_ todo.Storage  = (*MemoryStorage)(nil)
+	_ todo.KeyStore = (*MemoryStorage)(nil)
+)
+
+// MemoryStorage keeps todos in memory. It is safe for concurrent use and
+// returns the same errors as MongoStorage, which makes it a stand-in for a
+// real database in tests and demos. Nothing survives the process.
+type MemoryStorage struct {
+	mu            sync.RWMutex
+	todos         []todo.Todo // sorted by ID
+	nextID        int
+	projects      []todo.Project // sorted by ID
+	nextProjectID int
+	keys          []todo.APIKey // sorted by ID
+	nextKeyID     int
+}
+
+// NewMemoryStorage returns a MemoryStorage holding a copy of seed, which is
+// stored as given without validation. New IDs continue after the highest
+// seeded ID.
+func NewMemoryStorage(seed ...todo.Todo) *MemoryStorage {
+	m := &MemoryStorage{nextID: 1, nextProjectID: 1, nextKeyID: 1}
+	for _, t := range seed {
+		m.todos = append(m.todos, clone(t))
+		m.nextID = max(m.nextID, t.ID+1)
+	}
+	slices.SortFunc(m.todos, func(a, b todo.Todo) int { return a.ID - b.ID })
+	return m
+}
+
+// now returns the current time at the millisecond precision MongoDB stores,
+// so every backend reports the same timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// clone returns a copy of t that shares no memory with it.
+func clone(t todo.Todo) todo.Todo {
+	t.Tags = slices.Clone(t.Tags)
+	t.BlockedBy = slices.Clone(t.BlockedBy)
+	return t
+}
+
+func (m *MemoryStorage) Add(ctx context.Context, draft todo.Draft) (todo.Todo, error) {
+	if err := draft.Validate(); err != nil {
+		return todo.Todo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := todo.Owner(ctx)
+	if err := todo.CheckProject(draft.ProjectID, m.projectGetter(owner)); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.CheckParent(0, draft.ParentID, draft.ProjectID, m.getter(owner)); err != nil {
+		return todo.Todo{}, err
+	}
+	return clone(m.insert(owner, draft, now())), nil
+}
+
+// insert stores a new todo of owner from a validated draft, added at the
+// given time, and returns it. m.mu must be held.
+func (m *MemoryStorage) insert(owner string, draft todo.Draft, at time.Time) todo.Todo {
+	t := todo.Todo{
+		ID:          m.nextID,
+		Title:       draft.Title,
+		Description: draft.Description,
+		DueDate:     draft.DueDate,
+		DueTime:     draft.DueTime,
+		Priority:    draft.Priority,
+		Tags:        todo.NormalizeTags(draft.Tags),
+		ParentID:    draft.ParentID,
+		ProjectID:   draft.ProjectID,
+		Recurrence:  todo.NormalizeRecurrence(draft.Recurrence),
+		Owner:       owner,
+		Version:     1,
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+	m.todos = append(m.todos, t)
+	m.nextID++
+	return t
+}
+
+func (m *MemoryStorage) List(ctx context.Context, opts todo.ListOptions) ([]todo.Todo, string, error) {
+	if err := todo.ValidatePageSize(opts.PageSize); err != nil {
+		return nil, "", err
+	}
+	if err := todo.ValidateProjectRef(opts.ProjectID); err != nil {
+		return nil, "", err
+	}
+	if _, err := todo.DecodePageToken(opts.PageToken); err != nil {
+		return nil, "", err
+	}
+	expr, err := query.Parse(opts.Filter)
+	if err != nil {
+		return nil, "", err
+	}
+	tags := todo.NormalizeTags(opts.Tags)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	owner := todo.Owner(ctx)
+	matched := []todo.Todo{}
+	for _, t := range m.todos {
+		if t.Owner == owner && t.Trashed() == opts.Trashed && t.ProjectID == opts.ProjectID && t.HasTags(tags) && query.Match(expr, t) {
+			matched = append(matched, clone(t))
+		}
+	}
+	return todo.Paginate(matched, opts)
+}
+
+// index returns the position of owner's todo with the given ID, live or
+// trashed. m.mu must be held.
+func (m *MemoryStorage) index(owner string, id int) (int, error) {
+	i, found := slices.BinarySearchFunc(m.todos, id, func(t todo.Todo, id int) int { return t.ID - id })
+	if !found || m.todos[i].Owner != owner {
+		return 0, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	return i, nil
+}
+
+// live is like index but reports ErrNotFound for a todo in the trash.
+func (m *MemoryStorage) live(owner string, id int) (int, error) {
+	i, err := m.index(owner, id)
+	if err == nil && m.todos[i].Trashed() {
+		err = fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	return i, err
+}
+
+// getter returns a function that returns a copy of owner's todo with the
+// given ID, live or trashed, for todo.CheckParent and the other checks that
+// look at related todos. m.mu must be held while it is used.
+func (m *MemoryStorage) getter(owner string) func(id int) (todo.Todo, error) {
+	return func(id int) (todo.Todo, error) {
+		i, err := m.index(owner, id)
+		if err != nil {
+			return todo.Todo{}, err
+		}
+		return clone(m.todos[i]), nil
+	}
+}
+
+// subtree returns the positions of the todo at position i and of those of
+// its subtasks, at any depth, for which follow reports true; the subtasks
+// of a subtask it skips are skipped too. m.mu must be held.
+func (m *MemoryStorage) subtree(i int, follow func(todo.Todo) bool) []int {
+	positions := []int{i}
+	for k := 0; k < len(positions); k++ {
+		parentID := m.todos[positions[k]].ID
+		for j, t := range m.todos {
+			if t.ParentID == parentID && follow(t) {
+				positions = append(positions, j)
+			}
+		}
+	}
+	return positions
+}
+
+// isLive reports whether t is outside the trash.
+func isLive(t todo.Todo) bool {
+	return !t.Trashed()
+}
+
+// update applies fn to the todo with the given ID under the write lock,
+// bumps its version, stamps the change, adds the next occurrence if the
+// todo is due to recur and returns a copy of the result. fn reports the
+// unchanged error, if any, before modifying the todo; it is not called if
+// the todo is not at the version ctx expects.
+func (m *MemoryStorage) update(ctx context.Context, id int, fn func(t *todo.Todo) error) (todo.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.live(todo.Owner(ctx), id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.CheckVersion(ctx, m.todos[i]); err != nil {
+		return todo.Todo{}, err
+	}
+	before := clone(m.todos[i])
+	if err := fn(&m.todos[i]); err != nil {
+		return todo.Todo{}, err
+	}
+	at := now()
+	m.todos[i] = m.todos[i].Stamp(before, at)
+	m.todos[i].Version++
+	draft, recurs := m.todos[i].Recur()
+	if recurs {
+		m.todos[i].NextID = m.insert(m.todos[i].Owner, draft, at).ID
+	}
+	updated := clone(m.todos[i])
+	updated.Recurred = recurs
+	return updated, nil
+}
+
+func (m *MemoryStorage) Delete(ctx context.Context, id int) error {
+	if err := todo.ValidateID(id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.live(todo.Owner(ctx), id)
+	if err != nil {
+		return err
+	}
+	if err := todo.CheckVersion(ctx, m.todos[i]); err != nil {
+		return err
+	}
+	if len(m.subtree(i, isLive)) > 1 {
+		return fmt.Errorf("todo %d: %w", id, todo.ErrHasSubtasks)
+	}
+	m.trash(i, now())
+	return nil
+}
+
+func (m *MemoryStorage) DeleteTree(ctx context.Context, id int) (int, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.live(todo.Owner(ctx), id)
+	if err != nil {
+		return 0, err
+	}
+	if err := todo.CheckVersion(ctx, m.todos[i]); err != nil {
+		return 0, err
+	}
+	// One timestamp for the whole tree lets Restore bring it back together.
+	at := now()
+	positions := m.subtree(i, isLive)
+	for _, j := range positions {
+		m.trash(j, at)
+	}
+	return len(positions), nil
+}
+
+// trash moves the todo at position i to the trash at the given time. m.mu
+// must be held.
+func (m *MemoryStorage) trash(i int, at time.Time) {
+	m.todos[i].DeletedAt, m.todos[i].UpdatedAt = at, at
+	m.todos[i].Version++
+}
+
+func (m *MemoryStorage) Restore(ctx context.Context, id int) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := todo.Owner(ctx)
+	i, err := m.index(owner, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.CheckVersion(ctx, m.todos[i]); err != nil {
+		return todo.Todo{}, err
+	}
+	if !m.todos[i].Trashed() {
+		return todo.Todo{}, fmt.Errorf("todo %d: %w", id, todo.ErrNotInTrash)
+	}
+	if err := todo.CheckParent(id, m.todos[i].ParentID, m.todos[i].ProjectID, m.getter(owner)); err != nil {
+		return todo.Todo{}, err
+	}
+	deletedAt, at := m.todos[i].DeletedAt, now()
+	for _, j := range m.subtree(i, func(t todo.Todo) bool { return t.DeletedAt.Equal(deletedAt) }) {
+		m.todos[j].DeletedAt = time.Time{}
+		m.todos[j].UpdatedAt = at
+		m.todos[j].Version++
+	}
+	return clone(m.todos[i]), nil
+}
+
+func (m *MemoryStorage) PurgeTrash(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := todo.Owner(ctx)
+	purged := make(map[int]bool)
+	for _, t := range m.todos {
+		if t.Owner == owner && t.Trashed() {
+			purged[t.ID] = true
+		}
+	}
+	m.todos = slices.DeleteFunc(m.todos, func(t todo.Todo) bool { return purged[t.ID] })
+	for i := range m.todos {
+		m.todos[i].BlockedBy = slices.DeleteFunc(m.todos[i].BlockedBy, func(id int) bool { return purged[id] })
+	}
+	return len(purged), nil
+}
+
+func (m *MemoryStorage) SetCompleted(ctx context.Context, id int, completed bool) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if t.Completed == completed {
+			if completed {
+				return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
+			}
+			return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
+		}
+		if completed {
+			if err := todo.CheckBlockers(*t, m.getter(t.Owner)); err != nil {
+				return err
+			}
+		}
+		t.Completed = completed
+		return nil
+	})
+}
+
+func (m *MemoryStorage) EditTitle(ctx context.Context, id int, title string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateTitle(title); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if t.Title == title {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged)
+		}
+		t.Title = title
+		return nil
+	})
+}
+
+func (m *MemoryStorage) EditDescription(ctx context.Context, id int, description string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDescription(description); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if t.Description == description {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged)
+		}
+		t.Description = description
+		return nil
+	})
+}
+
+func (m *MemoryStorage) EditDue(ctx context.Context, id int, dueDate, dueTime string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDue(dueDate, dueTime); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if t.DueDate == dueDate && t.DueTime == dueTime {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrDueUnchanged)
+		}
+		t.DueDate, t.DueTime = dueDate, dueTime
+		return nil
+	})
+}
+
+func (m *MemoryStorage) EditPriority(ctx context.Context, id int, priority todo.Priority) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidatePriority(priority); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if t.Priority == priority {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrPriorityUnchanged)
+		}
+		t.Priority = priority
+		return nil
+	})
+}
+
+func (m *MemoryStorage) AddTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		// Like MongoStorage, a full todo reports ErrTooManyTags even when
+		// the tag is already present.
+		if len(t.Tags) >= todo.MaxTags {
+			return fmt.Errorf("todo %d: %w (max %d)", id, todo.ErrTooManyTags, todo.MaxTags)
+		}
+		if slices.Contains(t.Tags, tag) {
+			return fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagAlreadyPresent, tag)
+		}
+		t.Tags = append(t.Tags, tag)
+		return nil
+	})
+}
+
+func (m *MemoryStorage) RemoveTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		i := slices.Index(t.Tags, tag)
+		if i < 0 {
+			return fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagNotPresent, tag)
+		}
+		t.Tags = slices.Delete(t.Tags, i, i+1)
+		return nil
+	})
+}
+
+func (m *MemoryStorage) AddDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		if slices.Contains(t.BlockedBy, blockerID) {
+			return fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyPresent, blockerID)
+		}
+		if err := todo.CheckDependency(id, blockerID, m.getter(t.Owner)); err != nil {
+			return err
+		}
+		t.BlockedBy = append(t.BlockedBy, blockerID)
+		return nil
+	})
+}
+
+func (m *MemoryStorage) RemoveDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, id, func(t *todo.Todo) error {
+		i := slices.Index(t.BlockedBy, blockerID)
+		if i < 0 {
+			return fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyNotPresent, blockerID)
+		}
+		t.BlockedBy = slices.Delete(t.BlockedBy, i, i+1)
+		return nil
+	})
+}
+
+func (m *MemoryStorage) Update(ctx context.Context, patch todo.Todo, mask []string) (todo.Todo, error) {
+	patch, err := todo.ValidateUpdate(patch, mask)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	return m.update(ctx, patch.ID, func(t *todo.Todo) error {
+		updated, changed := t.Apply(patch, mask)
+		if !changed {
+			return fmt.Errorf("todo %d: %w", t.ID, todo.ErrTodoUnchanged)
+		}
+		if updated.ProjectID != t.ProjectID {
+			if err := todo.CheckProject(updated.ProjectID, m.projectGetter(t.Owner)); err != nil {
+				return err
+			}
+			i, _ := m.index(t.Owner, t.ID)
+			if len(m.subtree(i, isLive)) > 1 {
+				return fmt.Errorf("todo %d: %w", t.ID, todo.ErrHasSubtasks)
+			}
+		}
+		if updated.ParentID != t.ParentID || updated.ProjectID != t.ProjectID {
+			if err := todo.CheckParent(t.ID, updated.ParentID, updated.ProjectID, m.getter(t.Owner)); err != nil {
+				return err
+			}
+		}
+		if updated.Completed && !t.Completed {
+			if err := todo.CheckBlockers(*t, m.getter(t.Owner)); err != nil {
+				return err
+			}
+		}
+		*t = updated
+		return nil
+	})
+}
+
+// projectIndex returns the position of owner's project with the given ID.
+// m.mu must be held.
+func (m *MemoryStorage) projectIndex(owner string, id int) (int, error) {
+	i, found := slices.BinarySearchFunc(m.projects, id, func(p todo.Project, id int) int { return p.ID - id })
+	if !found || m.projects[i].Owner != owner {
+		return 0, fmt.Errorf("project with id %d: %w", id, todo.ErrProjectNotFound)
+	}
+	return i, nil
+}
+
+// projectGetter returns a function that returns owner's project with the
+// given ID, for todo.CheckProject. m.mu must be held while it is used.
+func (m *MemoryStorage) projectGetter(owner string) func(id int) (todo.Project, error) {
+	return func(id int) (todo.Project, error) {
+		i, err := m.projectIndex(owner, id)
+		if err != nil {
+			return todo.Project{}, err
+		}
+		return m.projects[i], nil
+	}
+}
+
+// ownedProjects returns owner's projects, ordered by ID. m.mu must be held.
+func (m *MemoryStorage) ownedProjects(owner string) []todo.Project {
+	projects := []todo.Project{}
+	for _, p := range m.projects {
+		if p.Owner == owner {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// checkProjectName reports ErrProjectNameTaken if a project of owner other
+// than the one with ID id is called name. m.mu must be held.
+func (m *MemoryStorage) checkProjectName(owner string, id int, name string) error {
+	if p, ok := todo.ProjectNamed(m.ownedProjects(owner), name); ok && p.ID != id {
+		return fmt.Errorf("%w: %q", todo.ErrProjectNameTaken, name)
+	}
+	return nil
+}
+
+func (m *MemoryStorage) CreateProject(ctx context.Context, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := todo.Owner(ctx)
+	if err := m.checkProjectName(owner, 0, name); err != nil {
+		return todo.Project{}, err
+	}
+	p := todo.Project{ID: m.nextProjectID, Name: name, Owner: owner}
+	m.projects = append(m.projects, p)
+	m.nextProjectID++
+	return p, nil
+}
+
+func (m *MemoryStorage) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.ownedProjects(todo.Owner(ctx)), nil
+}
+
+// updateProject applies fn to the project of ctx's owner with the given ID
+// under the write lock and returns the result. fn reports the unchanged
+// error, if any, before modifying the project.
+func (m *MemoryStorage) updateProject(ctx context.Context, id int, fn func(p *todo.Project) error) (todo.Project, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.projectIndex(todo.Owner(ctx), id)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	if err := fn(&m.projects[i]); err != nil {
+		return todo.Project{}, err
+	}
+	return m.projects[i], nil
+}
+
+func (m *MemoryStorage) RenameProject(ctx context.Context, id int, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	return m.updateProject(ctx, id, func(p *todo.Project) error {
+		if p.Name == name {
+			return fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged)
+		}
+		if err := m.checkProjectName(p.Owner, id, name); err != nil {
+			return err
+		}
+		p.Name = name
+		return nil
+	})
+}
+
+func (m *MemoryStorage) ArchiveProject(ctx context.Context, id int, archived bool) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	return m.updateProject(ctx, id, func(p *todo.Project) error {
+		if p.Archived == archived {
+			return fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged)
+		}
+		p.Archived = archived
+		return nil
+	})
+}
+
+func (m *MemoryStorage) DeleteProject(ctx context.Context, id int) error {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.projectIndex(todo.Owner(ctx), id)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(m.todos, func(t todo.Todo) bool { return t.ProjectID == id }) {
+		return fmt.Errorf("project %d: %w", id, todo.ErrProjectNotEmpty)
+	}
+	m.projects = slices.Delete(m.projects, i, i+1)
+	return nil
+}
+
+func (m *MemoryStorage) AddKey(_ context.Context, user, hash string) (todo.APIKey, error) {
+	if err := todo.ValidateUser(user); err != nil {
+		return todo.APIKey{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := todo.APIKey{ID: m.nextKeyID, User: user, Hash: hash, CreatedAt: now()}
+	m.keys = append(m.keys, key)
+	m.nextKeyID++
+	return key, nil
+}
+
+func (m *MemoryStorage) ListKeys(_ context.Context) ([]todo.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]todo.APIKey{}, m.keys...), nil
+}
+
+func (m *MemoryStorage) KeyByHash(_ context.Context, hash string) (todo.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return todo.APIKey{}, todo.ErrKeyNotFound
+}
+
+func (m *MemoryStorage) RevokeKey(_ context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(m.keys, id, func(k todo.APIKey, id int) int { return k.ID - id })
+	if !found {
+		return fmt.Errorf("API key with id %d: %w", id, todo.ErrKeyNotFound)
+	}
+	m.keys = slices.Delete(m.keys, i, i+1)
+	return nil
+}
+
+// Close is a no-op; a MemoryStorage holds no external resources.
+func (m *MemoryStorage) Close(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/storage/storagetest"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// errOf drops the todo returned by a Storage method, keeping its error.
+func errOf(_ todo.Todo, err error) error {
+	return err
+}
+
+func TestMemoryConformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) todo.Storage { return NewMemoryStorage() })
+	storagetest.RunKeys(t, func(*testing.T) todo.KeyStore { return NewMemoryStorage() })
+}
+
+func TestMemorySeed(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage(
+		todo.Todo{ID: 7, Title: "later", Tags: []string{"work"}},
+		todo.Todo{ID: 3, Title: "earlier", Completed: true},
+	)
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "new"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var ids []int
+	for _, td := range todos {
+		ids = append(ids, td.ID)
+	}
+	if fmt.Sprint(ids) != "[3 7 8]" {
+		t.Fatalf("expected seeded IDs in order followed by 8, got %v", ids)
+	}
+
+	// Returned todos must not alias the stored ones.
+	todos[1].Tags[0] = "changed"
+	todos, _, err = s.List(ctx, todo.ListOptions{Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 7 {
+		t.Fatalf("expected todo 7 to keep its tag, got %+v", todos)
+	}
+}
+
+func TestMemoryErrors(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage(todo.Todo{ID: 1, Title: "task", Completed: true, Tags: []string{"work"}})
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"delete missing", s.Delete(ctx, 2), todo.ErrNotFound},
+		{"delete invalid", s.Delete(ctx, -1), todo.ErrInvalidID},
+		{"already completed", errOf(s.SetCompleted(ctx, 1, true)), todo.ErrAlreadyCompleted},
+		{"title unchanged", errOf(s.EditTitle(ctx, 1, "task")), todo.ErrTitleUnchanged},
+		{"title empty", errOf(s.EditTitle(ctx, 2, "")), todo.ErrEmptyTitle},
+		{"description unchanged", errOf(s.EditDescription(ctx, 1, "")), todo.ErrDescriptionUnchanged},
+		{"due unchanged", errOf(s.EditDue(ctx, 1, "", "")), todo.ErrDueUnchanged},
+		{"priority unchanged", errOf(s.EditPriority(ctx, 1, todo.PriorityNone)), todo.ErrPriorityUnchanged},
+		{"tag present", errOf(s.AddTag(ctx, 1, "WORK")), todo.ErrTagAlreadyPresent},
+		{"tag absent", errOf(s.RemoveTag(ctx, 1, "home")), todo.ErrTagNotPresent},
+		{"tag missing todo", errOf(s.AddTag(ctx, 2, "home")), todo.ErrNotFound},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: expected %v, got: %v", tt.name, tt.want, tt.err)
+		}
+	}
+}
+
+func TestMemoryConcurrentAdds(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	const n = 200
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Add(ctx, todo.Draft{Title: fmt.Sprintf("task %d", i)}); err != nil {
+				t.Errorf("Add: %v", err)
+			}
+			if _, _, err := s.List(ctx, todo.ListOptions{PageSize: 10}); err != nil {
+				t.Errorf("List: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != n {
+		t.Fatalf("expected %d todos, got %d", n, len(todos))
+	}
+	for i, td := range todos {
+		if td.ID != i+1 {
+			t.Fatalf("expected contiguous IDs, got %d at position %d", td.ID, i)
+		}
+	}
+}
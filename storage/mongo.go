@@ -2,8 +2,9 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,17 +13,23 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 
+	"github.com/amharshit45/todos-cli-/query"
 	"github.com/amharshit45/todos-cli-/todo"
 )
 
 const (
 	collectionName    = "todos"
+	projectCollection = "projects"
+	keyCollection     = "api_keys"
 	counterCollection = "counters"
 	defaultTimeout    = 5 * time.Second
 	listTimeout       = 10 * time.Second
 )
 
-var _ todo.Storage = (*MongoStorage)(nil)
+var (
+	_ todo.Storage  = (*MongoStorage)(nil)
+	_ todo.KeyStore = (*MongoStorage)(nil)
+)
 
 type MongoStorage struct {
 	client    *mongo.Client
@@ -46,14 +53,69 @@ func NewMongoStorage(ctx context.Context, uri, dbName string) (*MongoStorage, er
 		return nil, fmt.Errorf("failed to ping mongodb: %w", err)
 	}
 
-	return &MongoStorage{client: client, dbName: dbName}, nil
+	ms := &MongoStorage{client: client, dbName: dbName}
+	if err := ms.ensureIndexes(ctx); err != nil {
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), defaultTimeout)
+		defer disconnectCancel()
+		client.Disconnect(disconnectCtx)
+		return nil, err
+	}
+	return ms, nil
+}
+
+// ensureIndexes creates the indexes the owner-scoped queries and API key
+// lookups use, unless they exist. The one on project names also keeps each
+// owner's names unique regardless of case.
+func (ms *MongoStorage) ensureIndexes(ctx context.Context) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	_, err := ms.coll().Indexes().CreateMany(opCtx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "project_id", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "parent_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create todo indexes: %w", err)
+	}
+	_, err = ms.projects().Indexes().CreateOne(opCtx, mongo.IndexModel{
+		Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create project indexes: %w", err)
+	}
+	_, err = ms.keys().Indexes().CreateOne(opCtx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API key indexes: %w", err)
+	}
+	return nil
 }
 
 func (ms *MongoStorage) coll() *mongo.Collection {
 	return ms.client.Database(ms.dbName).Collection(collectionName)
 }
 
-func (ms *MongoStorage) nextID(ctx context.Context) (int, error) {
+func (ms *MongoStorage) projects() *mongo.Collection {
+	return ms.client.Database(ms.dbName).Collection(projectCollection)
+}
+
+func (ms *MongoStorage) keys() *mongo.Collection {
+	return ms.client.Database(ms.dbName).Collection(keyCollection)
+}
+
+func (ms *MongoStorage) counters() *mongo.Collection {
+	return ms.client.Database(ms.dbName).Collection(counterCollection)
+}
+
+// nextID atomically takes the next ID from the counters document of the
+// named collection. IDs are never handed back: an Add whose insert fails
+// leaves a gap, because by then another Add may already have taken a later
+// ID, and returning this one would let two todos share it.
+func (ms *MongoStorage) nextID(ctx context.Context, collection string) (int, error) {
 	type counter struct {
 		Seq int `bson:"seq"`
 	}
@@ -63,9 +125,9 @@ func (ms *MongoStorage) nextID(ctx context.Context) (int, error) {
 		SetUpsert(true).
 		SetReturnDocument(options.After)
 
-	err := ms.client.Database(ms.dbName).Collection(counterCollection).
+	err := ms.counters().
 		FindOneAndUpdate(ctx,
-			bson.D{{Key: "_id", Value: collectionName}},
+			bson.D{{Key: "_id", Value: collection}},
 			bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: 1}}}},
 			opts,
 		).Decode(&result)
@@ -76,59 +138,152 @@ func (ms *MongoStorage) nextID(ctx context.Context) (int, error) {
 	return result.Seq, nil
 }
 
-func (ms *MongoStorage) Add(ctx context.Context, title, description string) error {
-	if err := todo.ValidateTitle(title); err != nil {
-		return err
+// advanceCounter moves the counter past the highest ID in use, for when it
+// has fallen behind the todos, e.g. because it was reset or an older release
+// handed an ID back after another Add had taken it.
+func (ms *MongoStorage) advanceCounter(ctx context.Context) error {
+	var highest struct {
+		ID int `bson:"_id"`
 	}
-	if err := todo.ValidateDescription(description); err != nil {
-		return err
+	err := ms.coll().FindOne(ctx, bson.D{},
+		options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}}).SetProjection(bson.D{{Key: "_id", Value: 1}}),
+	).Decode(&highest)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to find highest id: %w", err)
+	}
+
+	_, err = ms.counters().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: collectionName}},
+		bson.D{{Key: "$max", Value: bson.D{{Key: "seq", Value: highest.ID}}}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to advance id counter: %w", err)
+	}
+	return nil
+}
+
+func (ms *MongoStorage) Add(ctx context.Context, draft todo.Draft) (todo.Todo, error) {
+	if err := draft.Validate(); err != nil {
+		return todo.Todo{}, err
 	}
 	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	id, err := ms.nextID(opCtx)
-	if err != nil {
-		return err
+	if err := todo.CheckProject(draft.ProjectID, ms.projectGetter(opCtx)); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.CheckParent(0, draft.ParentID, draft.ProjectID, ms.getter(opCtx)); err != nil {
+		return todo.Todo{}, err
 	}
+	return ms.insert(opCtx, draft, now())
+}
 
-	newTodo := todo.Todo{ID: id, Title: title, Description: description}
-	if _, err := ms.coll().InsertOne(opCtx, newTodo); err != nil {
-		ms.rollbackID(opCtx)
-		return fmt.Errorf("failed to insert todo: %w", err)
+// insert stores a new todo of ctx's owner from a validated draft, added at
+// the given time, under a fresh ID and returns it.
+func (ms *MongoStorage) insert(ctx context.Context, draft todo.Draft, at time.Time) (todo.Todo, error) {
+	newTodo := todo.Todo{
+		Title:       draft.Title,
+		Description: draft.Description,
+		DueDate:     draft.DueDate,
+		DueTime:     draft.DueTime,
+		Priority:    draft.Priority,
+		Tags:        todo.NormalizeTags(draft.Tags),
+		ParentID:    draft.ParentID,
+		ProjectID:   draft.ProjectID,
+		Recurrence:  todo.NormalizeRecurrence(draft.Recurrence),
+		Owner:       todo.Owner(ctx),
+		Version:     1,
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+	for attempt := 1; ; attempt++ {
+		id, err := ms.nextID(ctx, collectionName)
+		if err != nil {
+			return todo.Todo{}, err
+		}
+		newTodo.ID = id
+
+		_, err = ms.coll().InsertOne(ctx, newTodo)
+		if err == nil {
+			return newTodo, nil
+		}
+		// A duplicate means the counter is behind the todos; catch it up
+		// once and take a fresh ID rather than fail every Add from now on.
+		if !mongo.IsDuplicateKeyError(err) || attempt > 1 {
+			return todo.Todo{}, fmt.Errorf("failed to insert todo: %w", err)
+		}
+		if err := ms.advanceCounter(ctx); err != nil {
+			return todo.Todo{}, err
+		}
 	}
-	return nil
 }
 
-func (ms *MongoStorage) rollbackID(ctx context.Context) {
-	err := ms.client.Database(ms.dbName).Collection(counterCollection).
-		FindOneAndUpdate(ctx,
-			bson.D{{Key: "_id", Value: collectionName}},
-			bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: -1}}}},
-		).Err()
+func (ms *MongoStorage) List(ctx context.Context, opts todo.ListOptions) ([]todo.Todo, string, error) {
+	if err := todo.ValidatePageSize(opts.PageSize); err != nil {
+		return nil, "", err
+	}
+	if err := todo.ValidateProjectRef(opts.ProjectID); err != nil {
+		return nil, "", err
+	}
+	after, err := todo.DecodePageToken(opts.PageToken)
 	if err != nil {
-		log.Printf("failed to rollback ID counter: %v", err)
+		return nil, "", err
+	}
+	expr, err := query.Parse(opts.Filter)
+	if err != nil {
+		return nil, "", err
+	}
+	filter := bson.D{liveFilter, ownerFilter(ctx), projectFilter(opts.ProjectID)}
+	if opts.Trashed {
+		filter[0] = trashedFilter
+	}
+	if after > 0 {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}})
+	}
+	if tags := todo.NormalizeTags(opts.Tags); len(tags) > 0 {
+		filter = append(filter, bson.E{Key: "tags", Value: bson.D{{Key: "$all", Value: tags}}})
+	}
+	if expr != nil {
+		filter = append(filter, bson.E{Key: "$and", Value: bson.A{exprToBSON(expr)}})
+	}
+
+	findOpts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if opts.PageSize > 0 {
+		// One extra document tells us whether another page follows.
+		findOpts.SetLimit(int64(opts.PageSize) + 1)
 	}
-}
 
-func (ms *MongoStorage) List(ctx context.Context) ([]todo.Todo, error) {
 	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
 	defer cancel()
 
-	cursor, err := ms.coll().Find(opCtx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	cursor, err := ms.coll().Find(opCtx, filter, findOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find todos: %w", err)
+		return nil, "", fmt.Errorf("failed to find todos: %w", err)
 	}
 
 	var todos []todo.Todo
 	if err := cursor.All(opCtx, &todos); err != nil {
-		return nil, fmt.Errorf("failed to decode todos: %w", err)
+		return nil, "", fmt.Errorf("failed to decode todos: %w", err)
 	}
 	if todos == nil {
 		todos = []todo.Todo{}
 	}
-	return todos, nil
+	var next string
+	if opts.PageSize > 0 && len(todos) > opts.PageSize {
+		todos = todos[:opts.PageSize]
+		next = todo.EncodePageToken(todos[len(todos)-1].ID)
+	}
+	return todos, next, nil
 }
 
+// Delete and DeleteTree check for subtasks, Add, Restore and Update for a
+// live parent, Add and Update for an open project, AddDependency for a
+// cycle and SetCompleted and Update for open blockers, in reads separate
+// from their writes, so unlike the other backends they can race a
+// concurrent change to the todos involved. So can DeleteProject checking
+// for todos; a taken project name is caught by its unique index.
+
 func (ms *MongoStorage) Delete(ctx context.Context, id int) error {
 	if err := todo.ValidateID(id); err != nil {
 		return err
@@ -136,90 +291,916 @@ func (ms *MongoStorage) Delete(ctx context.Context, id int) error {
 	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	result, err := ms.coll().DeleteOne(opCtx, bson.D{{Key: "_id", Value: id}})
+	subtasks, err := ms.subtaskIDs(opCtx, []int{id}, liveFilter)
+	if err != nil {
+		return err
+	}
+	if len(subtasks) > 0 {
+		// Report a missing or changed todo first, as the other backends do.
+		if _, err := ms.find(opCtx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("todo %d: %w", id, todo.ErrHasSubtasks)
+	}
+	result, err := ms.coll().UpdateOne(opCtx, versionFilter(ctx, id), trashUpdate(now()))
 	if err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	if result.MatchedCount == 0 {
+		_, err := ms.find(opCtx, id)
+		return err
 	}
 	return nil
 }
 
-func (ms *MongoStorage) SetCompleted(ctx context.Context, id int, completed bool) error {
+func (ms *MongoStorage) DeleteTree(ctx context.Context, id int) (int, error) {
 	if err := todo.ValidateID(id); err != nil {
-		return err
+		return 0, err
 	}
 	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	result, err := ms.coll().UpdateOne(opCtx,
-		bson.D{{Key: "_id", Value: id}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "completed", Value: completed}}}},
+	// One timestamp for the whole tree lets Restore bring it back together.
+	at := now()
+	result, err := ms.coll().UpdateOne(opCtx, versionFilter(ctx, id), trashUpdate(at))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete todo: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		_, err := ms.find(opCtx, id)
+		return 0, err
+	}
+	n := 1
+	for level := []int{id}; ; {
+		if level, err = ms.subtaskIDs(opCtx, level, liveFilter); err != nil || len(level) == 0 {
+			return n, err
+		}
+		result, err := ms.coll().UpdateMany(opCtx,
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: level}}}, ownerFilter(ctx), liveFilter}, trashUpdate(at))
+		if err != nil {
+			return n, fmt.Errorf("failed to delete subtasks: %w", err)
+		}
+		n += int(result.ModifiedCount)
+	}
+}
+
+// trashUpdate moves todos to the trash at the given time.
+func trashUpdate(at time.Time) bson.D {
+	return bson.D{
+		{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: at}, {Key: "updated_at", Value: at}}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+}
+
+// subtaskIDs returns the IDs of the direct subtasks of ctx's owner's todos
+// with the given IDs that match the filter element which.
+func (ms *MongoStorage) subtaskIDs(ctx context.Context, parentIDs []int, which bson.E) ([]int, error) {
+	cursor, err := ms.coll().Find(ctx,
+		bson.D{ownerFilter(ctx), {Key: "parent_id", Value: bson.D{{Key: "$in", Value: parentIDs}}}, which},
+		options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update todo: %w", err)
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	var subtasks []struct {
+		ID int `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &subtasks); err != nil {
+		return nil, fmt.Errorf("failed to decode subtasks: %w", err)
+	}
+	ids := make([]int, len(subtasks))
+	for i, t := range subtasks {
+		ids[i] = t.ID
+	}
+	return ids, nil
+}
+
+func (ms *MongoStorage) Restore(ctx context.Context, id int) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	current, err := ms.get(opCtx, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.CheckVersion(ctx, current); err != nil {
+		return todo.Todo{}, err
+	}
+	if !current.Trashed() {
+		return todo.Todo{}, fmt.Errorf("todo %d: %w", id, todo.ErrNotInTrash)
+	}
+	if err := todo.CheckParent(id, current.ParentID, current.ProjectID, ms.getter(opCtx)); err != nil {
+		return todo.Todo{}, err
+	}
+
+	at := now()
+	restore := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: at}}},
+		{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+	var restored todo.Todo
+	err = ms.coll().FindOneAndUpdate(opCtx,
+		bson.D{{Key: "_id", Value: id}, {Key: "version", Value: current.Version}},
+		restore,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&restored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.Todo{}, fmt.Errorf("todo %d: %w (changed while being restored)", id, todo.ErrVersionConflict)
+	}
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("failed to restore todo: %w", err)
+	}
+
+	// The subtasks that went to the trash with the todo share its deleted_at.
+	sameDeletion := bson.E{Key: "deleted_at", Value: current.DeletedAt}
+	for level := []int{id}; ; {
+		if level, err = ms.subtaskIDs(opCtx, level, sameDeletion); err != nil {
+			return todo.Todo{}, err
+		}
+		if len(level) == 0 {
+			return restored, nil
+		}
+		_, err := ms.coll().UpdateMany(opCtx,
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: level}}}, ownerFilter(ctx), sameDeletion}, restore)
+		if err != nil {
+			return todo.Todo{}, fmt.Errorf("failed to restore subtasks: %w", err)
+		}
+	}
+}
+
+func (ms *MongoStorage) PurgeTrash(ctx context.Context) (int, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	cursor, err := ms.coll().Find(opCtx, bson.D{ownerFilter(ctx), trashedFilter}, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find trash: %w", err)
+	}
+	var trashed []struct {
+		ID int `bson:"_id"`
+	}
+	if err := cursor.All(opCtx, &trashed); err != nil {
+		return 0, fmt.Errorf("failed to decode trash: %w", err)
+	}
+	ids := make([]int, len(trashed))
+	for i, t := range trashed {
+		ids[i] = t.ID
+	}
+	inIDs := bson.D{{Key: "$in", Value: ids}}
+	result, err := ms.coll().DeleteMany(opCtx, bson.D{{Key: "_id", Value: inIDs}, ownerFilter(ctx), trashedFilter})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	_, err = ms.coll().UpdateMany(opCtx,
+		bson.D{{Key: "blocked_by", Value: inIDs}},
+		bson.D{{Key: "$pull", Value: bson.D{{Key: "blocked_by", Value: inIDs}}}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to drop purged blockers: %w", err)
+	}
+	return int(result.DeletedCount), nil
+}
+
+var (
+	// liveFilter matches todos outside the trash; Add leaves deleted_at
+	// out and Restore removes it.
+	liveFilter = bson.E{Key: "deleted_at", Value: nil}
+	// trashedFilter matches todos in the trash.
+	trashedFilter = bson.E{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}
+)
+
+// projectFilter matches the todos of the project with the given ID. Add
+// leaves project_id out for the Inbox.
+func projectFilter(id int) bson.E {
+	if id == todo.InboxID {
+		return bson.E{Key: "project_id", Value: nil}
+	}
+	return bson.E{Key: "project_id", Value: id}
+}
+
+// ownerFilter matches the todos or projects of ctx's owner. Add and
+// CreateProject leave owner out for the shared list.
+func ownerFilter(ctx context.Context) bson.E {
+	if owner := todo.Owner(ctx); owner != "" {
+		return bson.E{Key: "owner", Value: owner}
+	}
+	return bson.E{Key: "owner", Value: nil}
+}
+
+// versionFilter matches ctx's owner's live todo with the given ID, provided
+// it is at the version ctx expects.
+func versionFilter(ctx context.Context, id int) bson.D {
+	filter := bson.D{{Key: "_id", Value: id}, ownerFilter(ctx), liveFilter}
+	if version := todo.ExpectedVersion(ctx); version != 0 {
+		filter = append(filter, bson.E{Key: "version", Value: version})
+	}
+	return filter
+}
+
+// get returns ctx's owner's todo with the given ID, live or trashed.
+func (ms *MongoStorage) get(ctx context.Context, id int) (todo.Todo, error) {
+	var current todo.Todo
+	err := ms.coll().FindOne(ctx, bson.D{{Key: "_id", Value: id}, ownerFilter(ctx)}).Decode(&current)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.Todo{}, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("failed to find todo: %w", err)
+	}
+	return current, nil
+}
+
+// getter returns get bound to ctx, for todo.CheckParent.
+func (ms *MongoStorage) getter(ctx context.Context) func(id int) (todo.Todo, error) {
+	return func(id int) (todo.Todo, error) { return ms.get(ctx, id) }
+}
+
+// find returns the todo with the given ID after a filtered write matched
+// nothing. It reports ErrNotFound if the todo is missing or in the trash
+// and ErrVersionConflict if it is not at the version ctx expects.
+func (ms *MongoStorage) find(ctx context.Context, id int) (todo.Todo, error) {
+	current, err := ms.get(ctx, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if current.Trashed() {
+		return todo.Todo{}, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	if err := todo.CheckVersion(ctx, current); err != nil {
+		return todo.Todo{}, err
+	}
+	return current, nil
+}
+
+// update applies update to the todo with the given ID, increments its
+// version, stamps its updated_at and returns the todo as updated, having
+// added its next occurrence if it is due to recur. The filter
+// also requires cond, which must only hold when the update would change the
+// todo, and the version ctx expects, so a single atomic FindOneAndUpdate
+// both applies the change and detects a no-op or a conflicting change. When
+// nothing matches, unchanged is called with the current todo to explain
+// why; ErrNotFound and ErrVersionConflict are reported without calling it.
+func (ms *MongoStorage) update(ctx context.Context, id int, cond, update bson.D, unchanged func(current todo.Todo) error) (todo.Todo, error) {
+	update = touch(update, now())
+	update = append(update, bson.E{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}})
+	return ms.apply(ctx, id, cond, update, unchanged)
+}
+
+// apply is update for an update document, or pipeline, that already moves
+// the version on and stamps the change.
+func (ms *MongoStorage) apply(ctx context.Context, id int, cond bson.D, update any, unchanged func(current todo.Todo) error) (todo.Todo, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	filter := append(versionFilter(ctx, id), cond...)
+	var updated todo.Todo
+	err := ms.coll().FindOneAndUpdate(opCtx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == nil {
+		return ms.recur(opCtx, updated)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.Todo{}, fmt.Errorf("failed to update todo: %w", err)
+	}
+
+	current, err := ms.find(opCtx, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	return todo.Todo{}, unchanged(current)
+}
+
+// recur adds the next occurrence of t if it is due to recur and returns t
+// with its NextID and Recurred set. Unlike in the other backends this is a
+// write of its own: should it fail, t stays completed without a next
+// occurrence, and its next change tries again. Of two changes that race to
+// recur t, the loser removes the occurrence it added.
+func (ms *MongoStorage) recur(ctx context.Context, t todo.Todo) (todo.Todo, error) {
+	draft, ok := t.Recur()
+	if !ok {
+		return t, nil
+	}
+	next, err := ms.insert(ctx, draft, t.UpdatedAt)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	result, err := ms.coll().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: t.ID}, {Key: "next_id", Value: nil}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "next_id", Value: next.ID}}}})
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("failed to record next occurrence: %w", err)
 	}
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+		if _, err := ms.coll().DeleteOne(ctx, bson.D{{Key: "_id", Value: next.ID}}); err != nil {
+			return todo.Todo{}, fmt.Errorf("failed to remove duplicate occurrence: %w", err)
+		}
+		return ms.get(ctx, t.ID)
 	}
-	if result.ModifiedCount == 0 {
-		if completed {
-			return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
+	t.NextID = next.ID
+	t.Recurred = true
+	return t, nil
+}
+
+// touch adds setting updated_at to at to update, merging it into the
+// update's $set if it has one.
+func touch(update bson.D, at time.Time) bson.D {
+	stamp := bson.E{Key: "updated_at", Value: at}
+	for i, op := range update {
+		if op.Key == "$set" {
+			update[i].Value = append(slices.Clone(op.Value.(bson.D)), stamp)
+			return update
 		}
-		return fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
 	}
-	return nil
+	return append(update, bson.E{Key: "$set", Value: bson.D{stamp}})
+}
+
+// unchangedErr returns an unchanged callback for update that always
+// reports err.
+func unchangedErr(err error) func(todo.Todo) error {
+	return func(todo.Todo) error { return err }
+}
+
+// absent reports whether value is stored as an absent field: Add leaves
+// out empty strings, PriorityNone, empty tag lists and a zero parent ID.
+func absent(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case todo.Priority:
+		return v == todo.PriorityNone
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+// differs returns an update condition that holds unless every listed field
+// already has its value. For absent values an absent field counts as equal.
+func differs(fields bson.D) bson.D {
+	same := bson.D{}
+	for _, f := range fields {
+		value := f.Value
+		if absent(value) {
+			if _, ok := value.([]string); ok {
+				// RemoveTag can leave an empty array behind.
+				value = bson.A{}
+			}
+			value = bson.D{{Key: "$in", Value: bson.A{nil, value}}}
+		}
+		same = append(same, bson.E{Key: f.Key, Value: value})
+	}
+	return bson.D{{Key: "$nor", Value: bson.A{same}}}
 }
 
-func (ms *MongoStorage) EditTitle(ctx context.Context, id int, title string) error {
+// setFields returns an update that sets every listed field in one step.
+// Absent values are removed rather than stored, matching what Add writes.
+func setFields(fields bson.D) bson.D {
+	set := bson.D{}
+	unset := bson.D{}
+	for _, f := range fields {
+		if absent(f.Value) {
+			unset = append(unset, bson.E{Key: f.Key, Value: ""})
+		} else {
+			set = append(set, f)
+		}
+	}
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+	return update
+}
+
+func (ms *MongoStorage) SetCompleted(ctx context.Context, id int, completed bool) (todo.Todo, error) {
 	if err := todo.ValidateID(id); err != nil {
-		return err
+		return todo.Todo{}, err
+	}
+	unchanged := fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
+	if completed {
+		unchanged = fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "completed", Value: false}}},
+		{Key: "$unset", Value: bson.D{{Key: "completed_at", Value: ""}}},
+	}
+	if completed {
+		if err := ms.checkBlockers(ctx, id); err != nil {
+			return todo.Todo{}, err
+		}
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "completed", Value: true}, {Key: "completed_at", Value: now()}}}}
+	}
+	return ms.update(ctx, id,
+		bson.D{{Key: "completed", Value: bson.D{{Key: "$ne", Value: completed}}}},
+		update,
+		unchangedErr(unchanged),
+	)
+}
+
+func (ms *MongoStorage) EditTitle(ctx context.Context, id int, title string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
 	}
 	if err := todo.ValidateTitle(title); err != nil {
-		return err
+		return todo.Todo{}, err
+	}
+	return ms.update(ctx, id,
+		bson.D{{Key: "title", Value: bson.D{{Key: "$ne", Value: title}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "title", Value: title}}}},
+		unchangedErr(fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged)),
+	)
+}
+
+func (ms *MongoStorage) EditDescription(ctx context.Context, id int, description string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDescription(description); err != nil {
+		return todo.Todo{}, err
+	}
+	return ms.update(ctx, id,
+		bson.D{{Key: "description", Value: bson.D{{Key: "$ne", Value: description}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "description", Value: description}}}},
+		unchangedErr(fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged)),
+	)
+}
+
+func (ms *MongoStorage) EditDue(ctx context.Context, id int, dueDate, dueTime string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDue(dueDate, dueTime); err != nil {
+		return todo.Todo{}, err
+	}
+
+	fields := bson.D{{Key: "due_date", Value: dueDate}, {Key: "due_time", Value: dueTime}}
+	return ms.update(ctx, id, differs(fields), setFields(fields),
+		unchangedErr(fmt.Errorf("todo %d: %w", id, todo.ErrDueUnchanged)))
+}
+
+func (ms *MongoStorage) EditPriority(ctx context.Context, id int, priority todo.Priority) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidatePriority(priority); err != nil {
+		return todo.Todo{}, err
+	}
+
+	fields := bson.D{{Key: "priority", Value: priority}}
+	return ms.update(ctx, id, differs(fields), setFields(fields),
+		unchangedErr(fmt.Errorf("todo %d: %w", id, todo.ErrPriorityUnchanged)))
+}
+
+func (ms *MongoStorage) AddTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+
+	// The filter only matches while there is room for another tag, so the
+	// MaxTags limit holds even under concurrent AddTag calls.
+	return ms.update(ctx, id,
+		bson.D{
+			{Key: fmt.Sprintf("tags.%d", todo.MaxTags-1), Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "tags", Value: bson.D{{Key: "$ne", Value: tag}}},
+		},
+		bson.D{{Key: "$push", Value: bson.D{{Key: "tags", Value: tag}}}},
+		func(current todo.Todo) error {
+			if len(current.Tags) >= todo.MaxTags {
+				return fmt.Errorf("todo %d: %w (max %d)", id, todo.ErrTooManyTags, todo.MaxTags)
+			}
+			return fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagAlreadyPresent, tag)
+		},
+	)
+}
+
+func (ms *MongoStorage) RemoveTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+
+	return ms.update(ctx, id,
+		bson.D{{Key: "tags", Value: tag}},
+		bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: tag}}}},
+		unchangedErr(fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagNotPresent, tag)),
+	)
+}
+
+func (ms *MongoStorage) AddDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
 	}
+	present := fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyPresent, blockerID)
+	if err := ms.checkDependency(ctx, id, blockerID, present); err != nil {
+		return todo.Todo{}, err
+	}
+	return ms.update(ctx, id,
+		bson.D{{Key: "blocked_by", Value: bson.D{{Key: "$ne", Value: blockerID}}}},
+		bson.D{{Key: "$push", Value: bson.D{{Key: "blocked_by", Value: blockerID}}}},
+		unchangedErr(present),
+	)
+}
+
+func (ms *MongoStorage) RemoveDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
+	}
+	return ms.update(ctx, id,
+		bson.D{{Key: "blocked_by", Value: blockerID}},
+		bson.D{{Key: "$pull", Value: bson.D{{Key: "blocked_by", Value: blockerID}}}},
+		unchangedErr(fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyNotPresent, blockerID)),
+	)
+}
+
+// checkDependency runs todo.CheckDependency for blocking the todo with the
+// given ID on blockerID. A missing or changed todo is reported first, then
+// present if the todo is already blocked by it, as the other backends do.
+func (ms *MongoStorage) checkDependency(ctx context.Context, id, blockerID int, present error) error {
 	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	result, err := ms.coll().UpdateOne(opCtx,
-		bson.D{{Key: "_id", Value: id}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "title", Value: title}}}},
+	err := todo.CheckDependency(id, blockerID, ms.getter(opCtx))
+	if err == nil {
+		return nil
+	}
+	current, findErr := ms.find(opCtx, id)
+	if findErr != nil {
+		return findErr
+	}
+	if slices.Contains(current.BlockedBy, blockerID) {
+		return present
+	}
+	return err
+}
+
+// checkBlockers runs todo.CheckBlockers before the todo with the given ID
+// is completed. A missing, changed or already completed todo passes, for
+// the update to report.
+func (ms *MongoStorage) checkBlockers(ctx context.Context, id int) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	current, err := ms.find(opCtx, id)
+	if err != nil || current.Completed {
+		return nil
+	}
+	return todo.CheckBlockers(current, ms.getter(opCtx))
+}
+
+// Update sets every field the mask names in a single atomic update, so the
+// change applies all or nothing. It is a pipeline update, which lets it keep
+// completed_at when the todo was already completed.
+func (ms *MongoStorage) Update(ctx context.Context, patch todo.Todo, mask []string) (todo.Todo, error) {
+	patch, err := todo.ValidateUpdate(patch, mask)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if slices.Contains(mask, todo.FieldParentID) || slices.Contains(mask, todo.FieldProjectID) {
+		if err := ms.checkMove(ctx, patch, mask); err != nil {
+			return todo.Todo{}, err
+		}
+	}
+	if slices.Contains(mask, todo.FieldCompleted) && patch.Completed {
+		if err := ms.checkBlockers(ctx, patch.ID); err != nil {
+			return todo.Todo{}, err
+		}
+	}
+	at := now()
+	// The mask paths are also the document's field names.
+	fields := bson.D{}
+	set := bson.D{}
+	for _, field := range mask {
+		value := fieldValue(patch, field)
+		fields = append(fields, bson.E{Key: field, Value: value})
+		if absent(value) {
+			// Removed rather than stored, matching what Add writes.
+			set = append(set, bson.E{Key: field, Value: "$$REMOVE"})
+		} else {
+			// $literal keeps a title such as "$x" from reading as a field path.
+			set = append(set, bson.E{Key: field, Value: bson.D{{Key: "$literal", Value: value}}})
+		}
+	}
+	if slices.Contains(mask, todo.FieldCompleted) {
+		completedAt := any("$$REMOVE")
+		if patch.Completed {
+			// Stages see the todo as it was, so "$completed" is its old value.
+			completedAt = bson.D{{Key: "$cond", Value: bson.A{"$completed", "$completed_at", at}}}
+		}
+		set = append(set, bson.E{Key: "completed_at", Value: completedAt})
+	}
+	set = append(set,
+		bson.E{Key: "updated_at", Value: at},
+		// A todo stored before versioning has no version; $add would make
+		// it null, which no later $inc could move on.
+		bson.E{Key: "version", Value: bson.D{{Key: "$add", Value: bson.A{
+			bson.D{{Key: "$ifNull", Value: bson.A{"$version", 0}}}, 1,
+		}}}},
 	)
+	return ms.apply(ctx, patch.ID, differs(fields), mongo.Pipeline{{{Key: "$set", Value: set}}},
+		unchangedErr(fmt.Errorf("todo %d: %w", patch.ID, todo.ErrTodoUnchanged)))
+}
+
+// checkMove checks moving the todo patch.ID to the parent or project the
+// mask gives it: the project must be open and the todo without subtasks if
+// it changes, and the parent must pass todo.CheckParent. A missing or
+// changed todo is reported first, as the other backends do.
+func (ms *MongoStorage) checkMove(ctx context.Context, patch todo.Todo, mask []string) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	current, err := ms.find(opCtx, patch.ID)
 	if err != nil {
-		return fmt.Errorf("failed to update todo: %w", err)
+		return err
 	}
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	next, _ := current.Apply(patch, mask)
+	if next.ProjectID != current.ProjectID {
+		if err := todo.CheckProject(next.ProjectID, ms.projectGetter(opCtx)); err != nil {
+			return err
+		}
+		subtasks, err := ms.subtaskIDs(opCtx, []int{patch.ID}, liveFilter)
+		if err != nil {
+			return err
+		}
+		if len(subtasks) > 0 {
+			return fmt.Errorf("todo %d: %w", patch.ID, todo.ErrHasSubtasks)
+		}
+	}
+	if next.ParentID == current.ParentID && next.ProjectID == current.ProjectID {
+		return nil
 	}
-	if result.ModifiedCount == 0 {
-		return fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged)
+	return todo.CheckParent(patch.ID, next.ParentID, next.ProjectID, ms.getter(opCtx))
+}
+
+// fieldValue returns the value of the named field of t.
+func fieldValue(t todo.Todo, field string) any {
+	switch field {
+	case todo.FieldTitle:
+		return t.Title
+	case todo.FieldDescription:
+		return t.Description
+	case todo.FieldCompleted:
+		return t.Completed
+	case todo.FieldDueDate:
+		return t.DueDate
+	case todo.FieldDueTime:
+		return t.DueTime
+	case todo.FieldPriority:
+		return t.Priority
+	case todo.FieldTags:
+		return t.Tags
+	case todo.FieldParentID:
+		return t.ParentID
+	case todo.FieldProjectID:
+		return t.ProjectID
+	case todo.FieldRecurrence:
+		return t.Recurrence
+	}
+	panic("unknown field " + field)
+}
+
+// getProject returns ctx's owner's project with the given ID.
+func (ms *MongoStorage) getProject(ctx context.Context, id int) (todo.Project, error) {
+	var p todo.Project
+	err := ms.projects().FindOne(ctx, bson.D{{Key: "_id", Value: id}, ownerFilter(ctx)}).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.Project{}, fmt.Errorf("project with id %d: %w", id, todo.ErrProjectNotFound)
+	}
+	if err != nil {
+		return todo.Project{}, fmt.Errorf("failed to find project: %w", err)
+	}
+	return p, nil
+}
+
+// projectGetter returns getProject bound to ctx, for todo.CheckProject.
+func (ms *MongoStorage) projectGetter(ctx context.Context) func(id int) (todo.Project, error) {
+	return func(id int) (todo.Project, error) { return ms.getProject(ctx, id) }
+}
+
+// listProjects returns the projects of ctx's owner.
+func (ms *MongoStorage) listProjects(ctx context.Context) ([]todo.Project, error) {
+	cursor, err := ms.projects().Find(ctx, bson.D{ownerFilter(ctx)}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find projects: %w", err)
+	}
+	projects := []todo.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+	return projects, nil
+}
+
+// checkProjectName reports ErrProjectNameTaken if a project of ctx's owner
+// other than the one with ID id is called name.
+func (ms *MongoStorage) checkProjectName(ctx context.Context, id int, name string) error {
+	projects, err := ms.listProjects(ctx)
+	if err != nil {
+		return err
+	}
+	if p, ok := todo.ProjectNamed(projects, name); ok && p.ID != id {
+		return fmt.Errorf("%w: %q", todo.ErrProjectNameTaken, name)
 	}
 	return nil
 }
 
-func (ms *MongoStorage) EditDescription(ctx context.Context, id int, description string) error {
-	if err := todo.ValidateID(id); err != nil {
+func (ms *MongoStorage) CreateProject(ctx context.Context, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if err := ms.checkProjectName(opCtx, 0, name); err != nil {
+		return todo.Project{}, err
+	}
+	id, err := ms.nextID(opCtx, projectCollection)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	p := todo.Project{ID: id, Name: name, Owner: todo.Owner(ctx)}
+	if _, err := ms.projects().InsertOne(opCtx, p); mongo.IsDuplicateKeyError(err) {
+		return todo.Project{}, fmt.Errorf("%w: %q", todo.ErrProjectNameTaken, name)
+	} else if err != nil {
+		return todo.Project{}, fmt.Errorf("failed to insert project: %w", err)
+	}
+	return p, nil
+}
+
+func (ms *MongoStorage) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	return ms.listProjects(opCtx)
+}
+
+// updateProject applies update to ctx's owner's project with the given ID
+// if it matches cond, which must only hold when the update would change
+// it, and returns the project as updated. If nothing matches, it reports
+// ErrProjectNotFound for a missing project and unchanged otherwise. A
+// rename that races another to the same name reports ErrProjectNameTaken.
+func (ms *MongoStorage) updateProject(ctx context.Context, id int, cond, update bson.D, unchanged error) (todo.Project, error) {
+	var updated todo.Project
+	err := ms.projects().FindOneAndUpdate(ctx, append(bson.D{{Key: "_id", Value: id}, ownerFilter(ctx)}, cond...), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == nil {
+		return updated, nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return todo.Project{}, fmt.Errorf("project %d: %w", id, todo.ErrProjectNameTaken)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.Project{}, fmt.Errorf("failed to update project: %w", err)
+	}
+	if _, err := ms.getProject(ctx, id); err != nil {
+		return todo.Project{}, err
+	}
+	return todo.Project{}, unchanged
+}
+
+func (ms *MongoStorage) RenameProject(ctx context.Context, id int, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	current, err := ms.getProject(opCtx, id)
+	if err != nil {
+		return todo.Project{}, err
+	}
+	unchanged := fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged)
+	if current.Name == name {
+		return todo.Project{}, unchanged
+	}
+	if err := ms.checkProjectName(opCtx, id, name); err != nil {
+		return todo.Project{}, err
+	}
+	return ms.updateProject(opCtx, id,
+		bson.D{{Key: "name", Value: bson.D{{Key: "$ne", Value: name}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: name}}}},
+		unchanged)
+}
+
+func (ms *MongoStorage) ArchiveProject(ctx context.Context, id int, archived bool) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	// An open project has no archived field, as CreateProject writes it.
+	cond := bson.D{{Key: "archived", Value: true}}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "archived", Value: ""}}}}
+	if archived {
+		cond = bson.D{{Key: "archived", Value: bson.D{{Key: "$ne", Value: true}}}}
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "archived", Value: true}}}}
+	}
+	return ms.updateProject(opCtx, id, cond, update, fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged))
+}
+
+func (ms *MongoStorage) DeleteProject(ctx context.Context, id int) error {
+	if err := todo.ValidateProjectID(id); err != nil {
 		return err
 	}
-	if err := todo.ValidateDescription(description); err != nil {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if _, err := ms.getProject(opCtx, id); err != nil {
 		return err
 	}
+	n, err := ms.coll().CountDocuments(opCtx, bson.D{ownerFilter(ctx), projectFilter(id)}, options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to find todos: %w", err)
+	}
+	if n > 0 {
+		return fmt.Errorf("project %d: %w", id, todo.ErrProjectNotEmpty)
+	}
+	result, err := ms.projects().DeleteOne(opCtx, bson.D{{Key: "_id", Value: id}, ownerFilter(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("project with id %d: %w", id, todo.ErrProjectNotFound)
+	}
+	return nil
+}
+
+func (ms *MongoStorage) AddKey(ctx context.Context, user, hash string) (todo.APIKey, error) {
+	if err := todo.ValidateUser(user); err != nil {
+		return todo.APIKey{}, err
+	}
 	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	result, err := ms.coll().UpdateOne(opCtx,
-		bson.D{{Key: "_id", Value: id}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "description", Value: description}}}},
-	)
+	id, err := ms.nextID(opCtx, keyCollection)
 	if err != nil {
-		return fmt.Errorf("failed to update todo: %w", err)
+		return todo.APIKey{}, err
 	}
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	key := todo.APIKey{ID: id, User: user, Hash: hash, CreatedAt: now()}
+	if _, err := ms.keys().InsertOne(opCtx, key); err != nil {
+		return todo.APIKey{}, fmt.Errorf("failed to insert API key: %w", err)
 	}
-	if result.ModifiedCount == 0 {
-		return fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged)
+	return key, nil
+}
+
+func (ms *MongoStorage) ListKeys(ctx context.Context) ([]todo.APIKey, error) {
+	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	cursor, err := ms.keys().Find(opCtx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API keys: %w", err)
+	}
+	keys := []todo.APIKey{}
+	if err := cursor.All(opCtx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (ms *MongoStorage) KeyByHash(ctx context.Context, hash string) (todo.APIKey, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var key todo.APIKey
+	err := ms.keys().FindOne(opCtx, bson.D{{Key: "hash", Value: hash}}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return todo.APIKey{}, todo.ErrKeyNotFound
+	}
+	if err != nil {
+		return todo.APIKey{}, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return key, nil
+}
+
+func (ms *MongoStorage) RevokeKey(ctx context.Context, id int) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	result, err := ms.keys().DeleteOne(opCtx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("API key with id %d: %w", id, todo.ErrKeyNotFound)
 	}
 	return nil
 }
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// fieldKeys maps query fields to document keys.
+var fieldKeys = map[query.Field]string{
+	query.FieldID:          "_id",
+	query.FieldTitle:       "title",
+	query.FieldDescription: "description",
+	query.FieldCompleted:   "completed",
+	query.FieldDueDate:     "due_date",
+	query.FieldDueTime:     "due_time",
+	query.FieldPriority:    "priority",
+	query.FieldTag:         "tags",
+}
+
+var mongoOps = map[query.Op]string{
+	query.OpEq: "$eq",
+	query.OpNe: "$ne",
+	query.OpLt: "$lt",
+	query.OpLe: "$lte",
+	query.OpGt: "$gt",
+	query.OpGe: "$gte",
+}
+
+// exprToBSON translates a parsed filter into an equivalent MongoDB query
+// document, following the semantics of query.Match.
+func exprToBSON(e query.Expr) bson.D {
+	switch e := e.(type) {
+	case *query.And:
+		return bson.D{{Key: "$and", Value: bson.A{exprToBSON(e.Left), exprToBSON(e.Right)}}}
+	case *query.Or:
+		return bson.D{{Key: "$or", Value: bson.A{exprToBSON(e.Left), exprToBSON(e.Right)}}}
+	case *query.Not:
+		return bson.D{{Key: "$nor", Value: bson.A{exprToBSON(e.X)}}}
+	case *query.Comparison:
+		return comparisonToBSON(e)
+	}
+	return bson.D{}
+}
+
+func comparisonToBSON(c *query.Comparison) bson.D {
+	key := fieldKeys[c.Field]
+	switch {
+	case c.Op == query.OpContains:
+		pattern := regexp.QuoteMeta(c.Value.(string))
+		return bson.D{{Key: key, Value: bson.D{{Key: "$regex", Value: pattern}, {Key: "$options", Value: "i"}}}}
+
+	case c.Value == "" && (c.Field == query.FieldDueDate || c.Field == query.FieldDueTime):
+		// Unset due fields are absent from the document. Ordering against
+		// an unset value matches nothing, as in query.Match.
+		switch c.Op {
+		case query.OpEq:
+			return bson.D{{Key: key, Value: bson.D{{Key: "$exists", Value: false}}}}
+		case query.OpNe:
+			return bson.D{{Key: key, Value: bson.D{{Key: "$exists", Value: true}}}}
+		}
+		return bson.D{{Key: "_id", Value: bson.D{{Key: "$exists", Value: false}}}}
+
+	case c.Field == query.FieldPriority:
+		return priorityToBSON(c.Op, c.Value.(todo.Priority))
+	}
+	return bson.D{{Key: key, Value: bson.D{{Key: mongoOps[c.Op], Value: c.Value}}}}
+}
+
+// priorityToBSON compares against the priority field, which Add and
+// EditPriority leave absent for todo.PriorityNone. Every operator is phrased
+// so that a missing field behaves like zero.
+func priorityToBSON(op query.Op, p todo.Priority) bson.D {
+	cond := func(mongoOp string, v todo.Priority) bson.D {
+		return bson.D{{Key: mongoOp, Value: v}}
+	}
+	var value any
+	switch op {
+	case query.OpEq:
+		value = cond("$eq", p)
+		if p == todo.PriorityNone {
+			value = bson.D{{Key: "$in", Value: bson.A{nil, p}}}
+		}
+	case query.OpNe:
+		value = cond("$ne", p)
+		if p == todo.PriorityNone {
+			value = cond("$gt", p)
+		}
+	case query.OpLt:
+		value = bson.D{{Key: "$not", Value: cond("$gte", p)}}
+	case query.OpLe:
+		value = bson.D{{Key: "$not", Value: cond("$gt", p)}}
+	case query.OpGt:
+		value = cond("$gt", p)
+	case query.OpGe:
+		value = bson.D{{Key: "$not", Value: cond("$lt", p)}}
+	}
+	return bson.D{{Key: "priority", Value: value}}
+}
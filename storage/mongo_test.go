@@ -3,9 +3,14 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/storage/storagetest"
 	"github.com/amharshit45/todos-cli-/todo"
 )
 
@@ -25,7 +30,12 @@ func newTestMongoStorage(t *testing.T) *MongoStorage {
 
 	ctx := context.Background()
 	s.client.Database(dbName).Collection(collectionName).Drop(ctx)
+	s.client.Database(dbName).Collection(projectCollection).Drop(ctx)
+	s.client.Database(dbName).Collection(keyCollection).Drop(ctx)
 	s.client.Database(dbName).Collection(counterCollection).Drop(ctx)
+	if err := s.ensureIndexes(ctx); err != nil {
+		t.Fatalf("ensureIndexes: %v", err)
+	}
 
 	t.Cleanup(func() {
 		s.client.Database(dbName).Drop(context.Background())
@@ -35,11 +45,16 @@ func newTestMongoStorage(t *testing.T) *MongoStorage {
 	return s
 }
 
+func TestMongoConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) todo.Storage { return newTestMongoStorage(t) })
+	storagetest.RunKeys(t, func(t *testing.T) todo.KeyStore { return newTestMongoStorage(t) })
+}
+
 func TestMongoAddAndList(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -47,14 +62,14 @@ func TestMongoAddAndList(t *testing.T) {
 		t.Fatalf("expected 0 todos, got %d", len(todos))
 	}
 
-	if err := s.Add(ctx, "first", "first details"); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "first", Description: "first details"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
-	if err := s.Add(ctx, "second", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "second"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	todos, err = s.List(ctx)
+	todos, _, err = s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -76,7 +91,7 @@ func TestMongoAddEmptyTitle(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	err := s.Add(ctx, "", "some desc")
+	_, err := s.Add(ctx, todo.Draft{Title: "", Description: "some desc"})
 	if err == nil {
 		t.Fatal("expected error for empty title")
 	}
@@ -89,10 +104,10 @@ func TestMongoDelete(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "to delete", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "to delete"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
-	if err := s.Add(ctx, "to keep", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "to keep"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
@@ -100,7 +115,7 @@ func TestMongoDelete(t *testing.T) {
 		t.Fatalf("Delete: %v", err)
 	}
 
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -147,14 +162,14 @@ func TestMongoSetCompleted(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	if err := s.SetCompleted(ctx, 1, true); err != nil {
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
 		t.Fatalf("SetCompleted(true): %v", err)
 	}
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -162,10 +177,10 @@ func TestMongoSetCompleted(t *testing.T) {
 		t.Fatal("expected completed=true")
 	}
 
-	if err := s.SetCompleted(ctx, 1, false); err != nil {
+	if _, err := s.SetCompleted(ctx, 1, false); err != nil {
 		t.Fatalf("SetCompleted(false): %v", err)
 	}
-	todos, err = s.List(ctx)
+	todos, _, err = s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -174,15 +189,39 @@ func TestMongoSetCompleted(t *testing.T) {
 	}
 }
 
+func TestMongoUpdateUnversioned(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	// A todo stored before versioning, without a version field.
+	if _, err := s.coll().InsertOne(ctx, bson.D{{Key: "_id", Value: 1}, {Key: "title", Value: "old"}}); err != nil {
+		t.Fatalf("InsertOne: %v", err)
+	}
+	updated, err := s.Update(ctx, todo.Todo{ID: 1, Title: "renamed"}, []string{todo.FieldTitle})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Version != 1 {
+		t.Fatalf("expected version 1 after Update, got %d", updated.Version)
+	}
+	completed, err := s.SetCompleted(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("SetCompleted after Update: %v", err)
+	}
+	if !completed.Completed || completed.Version != 2 {
+		t.Fatalf("expected completed at version 2, got %+v", completed)
+	}
+}
+
 func TestMongoSetCompletedAlready(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	err := s.SetCompleted(ctx, 1, false)
+	_, err := s.SetCompleted(ctx, 1, false)
 	if err == nil {
 		t.Fatal("expected error for already incomplete")
 	}
@@ -190,11 +229,11 @@ func TestMongoSetCompletedAlready(t *testing.T) {
 		t.Fatalf("expected ErrAlreadyIncomplete, got: %v", err)
 	}
 
-	if err := s.SetCompleted(ctx, 1, true); err != nil {
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
 		t.Fatalf("SetCompleted(true): %v", err)
 	}
 
-	err = s.SetCompleted(ctx, 1, true)
+	_, err = s.SetCompleted(ctx, 1, true)
 	if err == nil {
 		t.Fatal("expected error for already completed")
 	}
@@ -207,7 +246,7 @@ func TestMongoSetCompletedNotFound(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	err := s.SetCompleted(ctx, 999, true)
+	_, err := s.SetCompleted(ctx, 999, true)
 	if !errors.Is(err, todo.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got: %v", err)
 	}
@@ -217,14 +256,14 @@ func TestMongoEditTitle(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "original", "desc"); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "original", Description: "desc"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	if err := s.EditTitle(ctx, 1, "updated"); err != nil {
+	if _, err := s.EditTitle(ctx, 1, "updated"); err != nil {
 		t.Fatalf("EditTitle: %v", err)
 	}
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -240,7 +279,7 @@ func TestMongoEditTitleNotFound(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	err := s.EditTitle(ctx, 999, "nope")
+	_, err := s.EditTitle(ctx, 999, "nope")
 	if !errors.Is(err, todo.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got: %v", err)
 	}
@@ -250,11 +289,11 @@ func TestMongoEditTitleUnchanged(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "same", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "same"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	err := s.EditTitle(ctx, 1, "same")
+	_, err := s.EditTitle(ctx, 1, "same")
 	if !errors.Is(err, todo.ErrTitleUnchanged) {
 		t.Fatalf("expected ErrTitleUnchanged, got: %v", err)
 	}
@@ -264,11 +303,11 @@ func TestMongoEditTitleEmpty(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", ""); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	err := s.EditTitle(ctx, 1, "")
+	_, err := s.EditTitle(ctx, 1, "")
 	if !errors.Is(err, todo.ErrEmptyTitle) {
 		t.Fatalf("expected ErrEmptyTitle, got: %v", err)
 	}
@@ -278,14 +317,14 @@ func TestMongoEditDescription(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", "old"); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Description: "old"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	if err := s.EditDescription(ctx, 1, "new"); err != nil {
+	if _, err := s.EditDescription(ctx, 1, "new"); err != nil {
 		t.Fatalf("EditDescription: %v", err)
 	}
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -301,7 +340,7 @@ func TestMongoEditDescriptionNotFound(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	err := s.EditDescription(ctx, 999, "nope")
+	_, err := s.EditDescription(ctx, 999, "nope")
 	if !errors.Is(err, todo.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got: %v", err)
 	}
@@ -311,11 +350,11 @@ func TestMongoEditDescriptionUnchanged(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", "same"); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Description: "same"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	err := s.EditDescription(ctx, 1, "same")
+	_, err := s.EditDescription(ctx, 1, "same")
 	if !errors.Is(err, todo.ErrDescriptionUnchanged) {
 		t.Fatalf("expected ErrDescriptionUnchanged, got: %v", err)
 	}
@@ -325,14 +364,14 @@ func TestMongoEditDescriptionToEmpty(t *testing.T) {
 	s := newTestMongoStorage(t)
 	ctx := context.Background()
 
-	if err := s.Add(ctx, "task", "has desc"); err != nil {
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Description: "has desc"}); err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	if err := s.EditDescription(ctx, 1, ""); err != nil {
+	if _, err := s.EditDescription(ctx, 1, ""); err != nil {
 		t.Fatalf("EditDescription to empty: %v", err)
 	}
-	todos, err := s.List(ctx)
+	todos, _, err := s.List(ctx, todo.ListOptions{})
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -341,9 +380,324 @@ func TestMongoEditDescriptionToEmpty(t *testing.T) {
 	}
 }
 
+func TestMongoAddWithDue(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "report", DueDate: "2026-03-01", DueTime: "09:30"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if todos[0].DueDate != "2026-03-01" || todos[0].DueTime != "09:30" {
+		t.Fatalf("unexpected due: %q %q", todos[0].DueDate, todos[0].DueTime)
+	}
+}
+
+func TestMongoAddInvalidDueDate(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	_, err := s.Add(ctx, todo.Draft{Title: "task", DueDate: "2026-02-30"})
+	if !errors.Is(err, todo.ErrInvalidDueDate) {
+		t.Fatalf("expected ErrInvalidDueDate, got: %v", err)
+	}
+}
+
+func TestMongoEditDue(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", DueDate: "2026-03-01", DueTime: "09:30"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if _, err := s.EditDue(ctx, 1, "2026-04-01", ""); err != nil {
+		t.Fatalf("EditDue: %v", err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if todos[0].DueDate != "2026-04-01" || todos[0].DueTime != "" {
+		t.Fatalf("unexpected due: %q %q", todos[0].DueDate, todos[0].DueTime)
+	}
+
+	_, err = s.EditDue(ctx, 1, "2026-04-01", "")
+	if !errors.Is(err, todo.ErrDueUnchanged) {
+		t.Fatalf("expected ErrDueUnchanged, got: %v", err)
+	}
+}
+
+func TestMongoEditDueClearNeverSet(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	_, err := s.EditDue(ctx, 1, "", "")
+	if !errors.Is(err, todo.ErrDueUnchanged) {
+		t.Fatalf("expected ErrDueUnchanged, got: %v", err)
+	}
+}
+
+func TestMongoEditPriority(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Priority: todo.PriorityLow}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if _, err := s.EditPriority(ctx, 1, todo.PriorityUrgent); err != nil {
+		t.Fatalf("EditPriority: %v", err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if todos[0].Priority != todo.PriorityUrgent {
+		t.Fatalf("expected urgent, got %v", todos[0].Priority)
+	}
+
+	_, err = s.EditPriority(ctx, 1, todo.PriorityUrgent)
+	if !errors.Is(err, todo.ErrPriorityUnchanged) {
+		t.Fatalf("expected ErrPriorityUnchanged, got: %v", err)
+	}
+
+	if _, err := s.EditPriority(ctx, 1, todo.PriorityNone); err != nil {
+		t.Fatalf("EditPriority to none: %v", err)
+	}
+	_, err = s.EditPriority(ctx, 1, todo.PriorityNone)
+	if !errors.Is(err, todo.ErrPriorityUnchanged) {
+		t.Fatalf("expected ErrPriorityUnchanged, got: %v", err)
+	}
+}
+
+func TestMongoTags(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "deploy", Tags: []string{"Work", "work", "ops"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := s.Add(ctx, todo.Draft{Title: "groceries", Tags: []string{"home"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	todos, _, err := s.List(ctx, todo.ListOptions{Tags: []string{"WORK"}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 1 {
+		t.Fatalf("expected only todo 1, got %+v", todos)
+	}
+	if len(todos[0].Tags) != 2 || todos[0].Tags[0] != "work" || todos[0].Tags[1] != "ops" {
+		t.Fatalf("expected normalized tags [work ops], got %v", todos[0].Tags)
+	}
+
+	if _, err := s.AddTag(ctx, 2, "errand"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	if _, err := s.AddTag(ctx, 2, "Errand"); !errors.Is(err, todo.ErrTagAlreadyPresent) {
+		t.Fatalf("expected ErrTagAlreadyPresent, got: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 2, "home"); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 2, "home"); !errors.Is(err, todo.ErrTagNotPresent) {
+		t.Fatalf("expected ErrTagNotPresent, got: %v", err)
+	}
+	if _, err := s.AddTag(ctx, 999, "x"); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestMongoTagLimit(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	for i := range todo.MaxTags {
+		if _, err := s.AddTag(ctx, 1, fmt.Sprintf("t%d", i)); err != nil {
+			t.Fatalf("AddTag %d: %v", i, err)
+		}
+	}
+	if _, err := s.AddTag(ctx, 1, "one-more"); !errors.Is(err, todo.ErrTooManyTags) {
+		t.Fatalf("expected ErrTooManyTags, got: %v", err)
+	}
+}
+
+func TestMongoListFilter(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	drafts := []todo.Draft{
+		{Title: "Deploy API", Priority: todo.PriorityHigh, DueDate: "2026-03-05", Tags: []string{"work"}},
+		{Title: "deploy docs"},
+		{Title: "groceries", Tags: []string{"home"}},
+	}
+	for _, d := range drafts {
+		if _, err := s.Add(ctx, d); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if _, err := s.SetCompleted(ctx, 2, true); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{`completed=false AND title~"deploy"`, []int{1}},
+		{`title~"DEPLOY"`, []int{1, 2}},
+		{`priority=none`, []int{2, 3}},
+		{`priority<high`, []int{2, 3}},
+		{`priority>=high`, []int{1}},
+		{`due_date=""`, []int{2, 3}},
+		{`due_date<2026-04-01`, []int{1}},
+		{`tag=work OR tag=home`, []int{1, 3}},
+		{`NOT tag=work`, []int{2, 3}},
+		{`title~"a.i"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			todos, _, err := s.List(ctx, todo.ListOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []int
+			for _, td := range todos {
+				got = append(got, td.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got IDs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMongoListPagination(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 7; i++ {
+		if _, err := s.Add(ctx, todo.Draft{Title: fmt.Sprintf("task %d", i)}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if err := s.Delete(ctx, 4); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	var ids []int
+	opts := todo.ListOptions{PageSize: 3}
+	for {
+		todos, next, err := s.List(ctx, opts)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, td := range todos {
+			ids = append(ids, td.ID)
+		}
+		if next == "" {
+			break
+		}
+		opts.PageToken = next
+	}
+	if fmt.Sprint(ids) != "[1 2 3 5 6 7]" {
+		t.Fatalf("unexpected IDs across pages: %v", ids)
+	}
+
+	_, _, err := s.List(ctx, todo.ListOptions{PageToken: "bogus"})
+	if !errors.Is(err, todo.ErrInvalidPageToken) {
+		t.Fatalf("expected ErrInvalidPageToken, got: %v", err)
+	}
+}
+
+func TestMongoListInvalidFilter(t *testing.T) {
+	s := newTestMongoStorage(t)
+
+	_, _, err := s.List(context.Background(), todo.ListOptions{Filter: "colour=red"})
+	if !errors.Is(err, todo.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got: %v", err)
+	}
+}
+
+func TestExprToBSON(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{`completed=false`, `{"completed": {"$eq": false}}`},
+		{`title~"a.b"`, `{"title": {"$regex": "a\\.b","$options": "i"}}`},
+		{`id>2 AND tag!=work`, `{"$and": [{"_id": {"$gt": {"$numberInt":"2"}}},{"tags": {"$ne": "work"}}]}`},
+		{`NOT due_date=""`, `{"$nor": [{"due_date": {"$exists": false}}]}`},
+		{`priority=none OR priority<=low`, `{"$or": [{"priority": {"$in": [null,{"$numberInt":"0"}]}},{"priority": {"$not": {"$gt": {"$numberInt":"1"}}}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := query.Parse(tt.filter)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			raw, err := bson.Marshal(exprToBSON(e))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if got := bson.Raw(raw).String(); got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMongoClose(t *testing.T) {
 	s := newTestMongoStorage(t)
 	if err := s.Close(context.Background()); err != nil {
 		t.Fatalf("Close: %v", err)
 	}
 }
+
+// TestMongoCounterBehind checks that Add recovers when the ID counter has
+// fallen behind the todos, as a rolled-back or reset counter used to leave
+// it, instead of failing or reusing an ID.
+func TestMongoCounterBehind(t *testing.T) {
+	s := newTestMongoStorage(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"first", "second"} {
+		if _, err := s.Add(ctx, todo.Draft{Title: title}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	_, err := s.counters().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: collectionName}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "seq", Value: 0}}}},
+	)
+	if err != nil {
+		t.Fatalf("resetting counter: %v", err)
+	}
+
+	added, err := s.Add(ctx, todo.Draft{Title: "third"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if added.ID != 3 {
+		t.Fatalf("expected ID 3, got %d", added.ID)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 3 || todos[0].Title != "first" || todos[1].Title != "second" {
+		t.Fatalf("expected existing todos to keep their IDs, got %+v", todos)
+	}
+}
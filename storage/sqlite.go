@@ -0,0 +1,1121 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// sqliteMigrations are applied in order to bring a database up to date.
+// PRAGMA user_version records how many have run, so entries must only ever
+// be appended.
+var sqliteMigrations = []string{
+	`CREATE TABLE todos (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		title       TEXT    NOT NULL,
+		description TEXT    NOT NULL DEFAULT '',
+		completed   INTEGER NOT NULL DEFAULT 0,
+		due_date    TEXT    NOT NULL DEFAULT '',
+		due_time    TEXT    NOT NULL DEFAULT '',
+		priority    INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE todo_tags (
+		todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		tag     TEXT    NOT NULL,
+		PRIMARY KEY (todo_id, tag)
+	);
+	CREATE INDEX todo_tags_tag ON todo_tags(tag);`,
+	`ALTER TABLE todos ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+	`ALTER TABLE todos ADD COLUMN deleted_at TEXT;`,
+	`ALTER TABLE todos ADD COLUMN created_at TEXT;
+	ALTER TABLE todos ADD COLUMN updated_at TEXT;
+	ALTER TABLE todos ADD COLUMN completed_at TEXT;`,
+	`ALTER TABLE todos ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX todos_parent_id ON todos(parent_id);`,
+	`CREATE TABLE todo_dependencies (
+		todo_id    INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		blocker_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		PRIMARY KEY (todo_id, blocker_id)
+	);
+	CREATE INDEX todo_dependencies_blocker_id ON todo_dependencies(blocker_id);`,
+	`ALTER TABLE todos ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
+	ALTER TABLE todos ADD COLUMN next_id INTEGER NOT NULL DEFAULT 0;`,
+	// project_id 0 is the Inbox, which has no row, so it is no foreign key.
+	`CREATE TABLE projects (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		name     TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+		archived INTEGER NOT NULL DEFAULT 0
+	);
+	ALTER TABLE todos ADD COLUMN project_id INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX todos_project_id ON todos(project_id);`,
+	// Project names become unique per owner, which takes a new table.
+	`ALTER TABLE todos ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+	DROP INDEX todos_project_id;
+	CREATE INDEX todos_owner_project_id ON todos(owner, project_id);
+	CREATE TABLE projects_new (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		owner    TEXT    NOT NULL DEFAULT '',
+		name     TEXT    NOT NULL COLLATE NOCASE,
+		archived INTEGER NOT NULL DEFAULT 0,
+		UNIQUE (owner, name)
+	);
+	INSERT INTO projects_new (id, name, archived) SELECT id, name, archived FROM projects;
+	DROP TABLE projects;
+	ALTER TABLE projects_new RENAME TO projects;`,
+	`CREATE TABLE api_keys (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_name  TEXT    NOT NULL,
+		hash       TEXT    NOT NULL UNIQUE,
+		created_at TEXT    NOT NULL
+	);`,
+}
+
+// sqliteTimeLayout stores timestamps as fixed-width UTC text, so they sort
+// and compare as strings.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// sqliteTime returns the column value for t: NULL for the zero time.
+func sqliteTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(sqliteTimeLayout)
+}
+
+// parseSQLiteTime decodes a timestamp column; NULL decodes to the zero time.
+func parseSQLiteTime(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+	return time.Parse(sqliteTimeLayout, s.String)
+}
+
+var (
+	_ todo.Storage  = (*SQLiteStorage)(nil)
+	_ todo.KeyStore = (*SQLiteStorage)(nil)
+)
+
+// SQLiteStorage stores todos in an embedded SQLite database file. IDs come
+// from an AUTOINCREMENT column, so like MongoStorage's counter they are
+// never reused after a delete.
+type SQLiteStorage struct {
+	db        *sql.DB
+	closeOnce sync.Once
+}
+
+// NewSQLiteStorage opens, creating if needed, the database at path and
+// applies any pending migrations.
+func NewSQLiteStorage(ctx context.Context, path string) (*SQLiteStorage, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows a single writer; one connection serializes access
+	// instead of surfacing SQLITE_BUSY under concurrent writes.
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStorage{db: db}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStorage) migrate(ctx context.Context) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var version int
+	if err := s.db.QueryRowContext(opCtx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than this binary supports (%d)", version, len(sqliteMigrations))
+	}
+	for i := version; i < len(sqliteMigrations); i++ {
+		err := s.inTx(opCtx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(opCtx, sqliteMigrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(opCtx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing if it returns nil.
+func (s *SQLiteStorage) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// checkVersion reports ErrNotFound if the todo with the given ID is not
+// stored for ctx's owner or is in the trash, and ErrVersionConflict if it
+// is not at the version ctx expects.
+func checkVersion(ctx context.Context, tx *sql.Tx, id int) error {
+	current := todo.Todo{ID: id}
+	err := tx.QueryRowContext(ctx, "SELECT version FROM todos WHERE id = ? AND owner = ? AND deleted_at IS NULL",
+		id, todo.Owner(ctx)).Scan(&current.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find todo: %w", err)
+	}
+	return todo.CheckVersion(ctx, current)
+}
+
+// bumpVersion increments the version of the todo with the given ID and
+// stamps the change.
+func bumpVersion(ctx context.Context, tx *sql.Tx, id int) error {
+	_, err := tx.ExecContext(ctx, "UPDATE todos SET version = version + 1, updated_at = ? WHERE id = ?", sqliteTime(now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to update todo: %w", err)
+	}
+	return nil
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx. Reads made inside a
+// transaction must go through the *sql.Tx: the pool has a single
+// connection, which the transaction holds.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// selectTodos returns the todos selected by clauses, the part of the query
+// following WHERE, without their tags and blockers.
+func selectTodos(ctx context.Context, q querier, clauses string, args ...any) ([]todo.Todo, error) {
+	rows, err := q.QueryContext(ctx, `SELECT id, title, description, completed, due_date, due_time, priority, parent_id,
+		project_id, recurrence, next_id, owner, version, deleted_at, created_at, updated_at, completed_at FROM todos
+		WHERE `+clauses, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find todos: %w", err)
+	}
+	defer rows.Close()
+	todos := []todo.Todo{}
+	for rows.Next() {
+		var t todo.Todo
+		var deletedAt, createdAt, updatedAt, completedAt sql.NullString
+		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Completed, &t.DueDate, &t.DueTime, &t.Priority, &t.ParentID,
+			&t.ProjectID, &t.Recurrence, &t.NextID, &t.Owner, &t.Version, &deletedAt, &createdAt, &updatedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to decode todos: %w", err)
+		}
+		for _, ts := range []struct {
+			dst *time.Time
+			src sql.NullString
+		}{
+			{&t.DeletedAt, deletedAt},
+			{&t.CreatedAt, createdAt},
+			{&t.UpdatedAt, updatedAt},
+			{&t.CompletedAt, completedAt},
+		} {
+			var err error
+			if *ts.dst, err = parseSQLiteTime(ts.src); err != nil {
+				return nil, fmt.Errorf("failed to decode todos: %w", err)
+			}
+		}
+		todos = append(todos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode todos: %w", err)
+	}
+	return todos, nil
+}
+
+// getTodo returns the todo of ctx's owner with the given ID, tags and
+// blockers included, whether live or trashed.
+func getTodo(ctx context.Context, q querier, id int) (todo.Todo, error) {
+	todos, err := selectTodos(ctx, q, "id = ? AND owner = ?", id, todo.Owner(ctx))
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if len(todos) == 0 {
+		return todo.Todo{}, fmt.Errorf("todo with id %d: %w", id, todo.ErrNotFound)
+	}
+	if err := loadLists(ctx, q, todos); err != nil {
+		return todo.Todo{}, err
+	}
+	return todos[0], nil
+}
+
+// getter returns getTodo bound to q, for todo.CheckParent and the other
+// checks that look at related todos.
+func getter(ctx context.Context, q querier) func(id int) (todo.Todo, error) {
+	return func(id int) (todo.Todo, error) { return getTodo(ctx, q, id) }
+}
+
+// hasSubtasks reports whether the todo with the given ID has live subtasks.
+func hasSubtasks(ctx context.Context, q querier, id int) (bool, error) {
+	var found bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM todos WHERE parent_id = ? AND deleted_at IS NULL)", id).
+		Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	return found, nil
+}
+
+// subtreeSQL returns a WITH clause that selects into tree the ID bound to
+// its first placeholder and the IDs of those of its subtasks, at any depth,
+// that satisfy cond; the subtasks of a subtask it skips are skipped too.
+func subtreeSQL(cond string) string {
+	return `WITH RECURSIVE tree(id) AS (
+		SELECT ?
+		UNION ALL
+		SELECT todos.id FROM todos JOIN tree ON todos.parent_id = tree.id WHERE ` + cond + `
+	) `
+}
+
+// update runs stmt, whose WHERE clause must only match the todo when the
+// statement would change it, and returns the todo as changed with its
+// version bumped and, if it is due to recur, its next occurrence added. It
+// reports ErrNotFound for a missing todo, ErrVersionConflict if the todo is
+// not at the version ctx expects, and unchanged if the statement changes
+// nothing, mirroring MongoStorage.
+func (s *SQLiteStorage) update(ctx context.Context, id int, unchanged error, stmt string, args ...any) (todo.Todo, error) {
+	return s.updateIf(ctx, id, nil, unchanged, stmt, args...)
+}
+
+// updateIf is update with a check that runs in the same transaction once
+// the todo's version has been checked, and whose error, if any, stops the
+// update. check may be nil.
+func (s *SQLiteStorage) updateIf(ctx context.Context, id int, check func(ctx context.Context, tx *sql.Tx) error, unchanged error, stmt string, args ...any) (todo.Todo, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var updated todo.Todo
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := checkVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(opCtx, tx); err != nil {
+				return err
+			}
+		}
+		result, err := tx.ExecContext(opCtx, stmt, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		} else if n == 0 {
+			return unchanged
+		}
+		if err := bumpVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		if updated, err = getTodo(opCtx, tx, id); err != nil {
+			return err
+		}
+		return recur(opCtx, tx, &updated)
+	})
+	return updated, err
+}
+
+// recur adds the next occurrence of t if it is due to recur, records its ID
+// in t and in the stored todo, and sets t.Recurred.
+func recur(ctx context.Context, tx *sql.Tx, t *todo.Todo) error {
+	draft, ok := t.Recur()
+	if !ok {
+		return nil
+	}
+	nextID, err := insertTodo(ctx, tx, draft, t.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE todos SET next_id = ? WHERE id = ?", nextID, t.ID); err != nil {
+		return fmt.Errorf("failed to update todo: %w", err)
+	}
+	t.NextID = nextID
+	t.Recurred = true
+	return nil
+}
+
+// insertTodo stores a new todo of ctx's owner from a validated draft, added
+// at the given time, and returns its ID.
+func insertTodo(ctx context.Context, tx *sql.Tx, draft todo.Draft, at time.Time) (int, error) {
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO todos (title, description, due_date, due_time, priority, parent_id, project_id, recurrence,
+			owner, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		draft.Title, draft.Description, draft.DueDate, draft.DueTime, int(draft.Priority), draft.ParentID, draft.ProjectID,
+		todo.NormalizeRecurrence(draft.Recurrence), todo.Owner(ctx), sqliteTime(at), sqliteTime(at))
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert todo: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert todo: %w", err)
+	}
+	for _, tag := range todo.NormalizeTags(draft.Tags) {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO todo_tags (todo_id, tag) VALUES (?, ?)", id, tag); err != nil {
+			return 0, fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	return int(id), nil
+}
+
+func (s *SQLiteStorage) Add(ctx context.Context, draft todo.Draft) (todo.Todo, error) {
+	if err := draft.Validate(); err != nil {
+		return todo.Todo{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var added todo.Todo
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := todo.CheckProject(draft.ProjectID, projectGetter(opCtx, tx)); err != nil {
+			return err
+		}
+		if err := todo.CheckParent(0, draft.ParentID, draft.ProjectID, getter(opCtx, tx)); err != nil {
+			return err
+		}
+		id, err := insertTodo(opCtx, tx, draft, now())
+		if err != nil {
+			return err
+		}
+		added, err = getTodo(opCtx, tx, id)
+		return err
+	})
+	return added, err
+}
+
+func (s *SQLiteStorage) List(ctx context.Context, opts todo.ListOptions) ([]todo.Todo, string, error) {
+	if err := todo.ValidatePageSize(opts.PageSize); err != nil {
+		return nil, "", err
+	}
+	if err := todo.ValidateProjectRef(opts.ProjectID); err != nil {
+		return nil, "", err
+	}
+	after, err := todo.DecodePageToken(opts.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	expr, err := query.Parse(opts.Filter)
+	if err != nil {
+		return nil, "", err
+	}
+	where := []string{"id > ?", "deleted_at IS NULL", "owner = ?", "project_id = ?"}
+	if opts.Trashed {
+		where[1] = "deleted_at IS NOT NULL"
+	}
+	args := []any{after, todo.Owner(ctx), opts.ProjectID}
+	for _, tag := range todo.NormalizeTags(opts.Tags) {
+		where = append(where, hasTagSQL)
+		args = append(args, tag)
+	}
+	if expr != nil {
+		cond, condArgs := exprToSQL(expr)
+		where = append(where, cond)
+		args = append(args, condArgs...)
+	}
+	clauses := strings.Join(where, " AND ") + " ORDER BY id"
+	if opts.PageSize > 0 {
+		// One extra row tells us whether another page follows.
+		clauses += " LIMIT ?"
+		args = append(args, opts.PageSize+1)
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	todos, err := selectTodos(opCtx, s.db, clauses, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if opts.PageSize > 0 && len(todos) > opts.PageSize {
+		todos = todos[:opts.PageSize]
+		next = todo.EncodePageToken(todos[len(todos)-1].ID)
+	}
+	if err := loadLists(opCtx, s.db, todos); err != nil {
+		return nil, "", err
+	}
+	return todos, next, nil
+}
+
+// loadLists fills in the tags and blockers of todos, each in the order they
+// were added.
+func loadLists(ctx context.Context, q querier, todos []todo.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(todos))
+	args := make([]any, len(todos))
+	for i, t := range todos {
+		index[t.ID] = i
+		args[i] = t.ID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(todos)), ",")
+	err := scanList(ctx, q, "tags",
+		"SELECT todo_id, tag FROM todo_tags WHERE todo_id IN ("+placeholders+") ORDER BY rowid", args,
+		func(id int, tag string) {
+			t := &todos[index[id]]
+			t.Tags = append(t.Tags, tag)
+		})
+	if err != nil {
+		return err
+	}
+	return scanList(ctx, q, "blockers",
+		"SELECT todo_id, blocker_id FROM todo_dependencies WHERE todo_id IN ("+placeholders+") ORDER BY rowid", args,
+		func(id, blockerID int) {
+			t := &todos[index[id]]
+			t.BlockedBy = append(t.BlockedBy, blockerID)
+		})
+}
+
+// scanList runs query, which selects a todo ID and a value of type V per
+// row, and passes each row to add. what names the values in errors.
+func scanList[V any](ctx context.Context, q querier, what, query string, args []any, add func(id int, value V)) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", what, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var value V
+		if err := rows.Scan(&id, &value); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", what, err)
+		}
+		add(id, value)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) Delete(ctx context.Context, id int) error {
+	if err := todo.ValidateID(id); err != nil {
+		return err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := checkVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		if found, err := hasSubtasks(opCtx, tx, id); err != nil {
+			return err
+		} else if found {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrHasSubtasks)
+		}
+		at := sqliteTime(now())
+		_, err := tx.ExecContext(opCtx, "UPDATE todos SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+			at, at, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete todo: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *SQLiteStorage) DeleteTree(ctx context.Context, id int) (int, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return 0, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var n int64
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := checkVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		// One timestamp for the whole tree lets Restore bring it back
+		// together.
+		at := sqliteTime(now())
+		result, err := tx.ExecContext(opCtx, subtreeSQL("todos.deleted_at IS NULL")+
+			`UPDATE todos SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id IN tree`,
+			id, at, at)
+		if err != nil {
+			return fmt.Errorf("failed to delete todos: %w", err)
+		}
+		n, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to delete todos: %w", err)
+		}
+		return nil
+	})
+	return int(n), err
+}
+
+func (s *SQLiteStorage) Restore(ctx context.Context, id int) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var restored todo.Todo
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		current, err := getTodo(opCtx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := todo.CheckVersion(opCtx, current); err != nil {
+			return err
+		}
+		if !current.Trashed() {
+			return fmt.Errorf("todo %d: %w", id, todo.ErrNotInTrash)
+		}
+		if err := todo.CheckParent(id, current.ParentID, current.ProjectID, getter(opCtx, tx)); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(opCtx, subtreeSQL("todos.deleted_at = ?")+
+			`UPDATE todos SET deleted_at = NULL, updated_at = ?, version = version + 1 WHERE id IN tree`,
+			id, sqliteTime(current.DeletedAt), sqliteTime(now()))
+		if err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+		restored, err = getTodo(opCtx, tx, id)
+		return err
+	})
+	return restored, err
+}
+
+func (s *SQLiteStorage) PurgeTrash(ctx context.Context) (int, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	// Their tags and dependencies, both ways, go with them through ON
+	// DELETE CASCADE.
+	result, err := s.db.ExecContext(opCtx, "DELETE FROM todos WHERE owner = ? AND deleted_at IS NOT NULL", todo.Owner(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return int(n), nil
+}
+
+func (s *SQLiteStorage) SetCompleted(ctx context.Context, id int, completed bool) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	unchanged := fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyIncomplete)
+	if completed {
+		unchanged = fmt.Errorf("todo %d: %w", id, todo.ErrAlreadyCompleted)
+	}
+	var completedAt time.Time
+	var check func(ctx context.Context, tx *sql.Tx) error
+	if completed {
+		completedAt = now()
+		check = func(ctx context.Context, tx *sql.Tx) error {
+			current, err := getTodo(ctx, tx, id)
+			if err != nil || current.Completed {
+				// An already completed todo is reported as unchanged.
+				return err
+			}
+			return todo.CheckBlockers(current, getter(ctx, tx))
+		}
+	}
+	return s.updateIf(ctx, id, check, unchanged,
+		"UPDATE todos SET completed = ?, completed_at = ? WHERE id = ? AND completed != ?",
+		completed, sqliteTime(completedAt), id, completed)
+}
+
+func (s *SQLiteStorage) EditTitle(ctx context.Context, id int, title string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateTitle(title); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w", id, todo.ErrTitleUnchanged),
+		"UPDATE todos SET title = ? WHERE id = ? AND title != ?", title, id, title)
+}
+
+func (s *SQLiteStorage) EditDescription(ctx context.Context, id int, description string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDescription(description); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w", id, todo.ErrDescriptionUnchanged),
+		"UPDATE todos SET description = ? WHERE id = ? AND description != ?", description, id, description)
+}
+
+func (s *SQLiteStorage) EditDue(ctx context.Context, id int, dueDate, dueTime string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidateDue(dueDate, dueTime); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w", id, todo.ErrDueUnchanged),
+		"UPDATE todos SET due_date = ?, due_time = ? WHERE id = ? AND (due_date != ? OR due_time != ?)",
+		dueDate, dueTime, id, dueDate, dueTime)
+}
+
+func (s *SQLiteStorage) EditPriority(ctx context.Context, id int, priority todo.Priority) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := todo.ValidatePriority(priority); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w", id, todo.ErrPriorityUnchanged),
+		"UPDATE todos SET priority = ? WHERE id = ? AND priority != ?", int(priority), id, int(priority))
+}
+
+func (s *SQLiteStorage) AddTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var updated todo.Todo
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := checkVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		// Like MongoStorage, a full todo reports ErrTooManyTags even when
+		// the tag is already present.
+		var n int
+		if err := tx.QueryRowContext(opCtx, "SELECT COUNT(*) FROM todo_tags WHERE todo_id = ?", id).Scan(&n); err != nil {
+			return fmt.Errorf("failed to count tags: %w", err)
+		}
+		if n >= todo.MaxTags {
+			return fmt.Errorf("todo %d: %w (max %d)", id, todo.ErrTooManyTags, todo.MaxTags)
+		}
+		result, err := tx.ExecContext(opCtx, "INSERT OR IGNORE INTO todo_tags (todo_id, tag) VALUES (?, ?)", id, tag)
+		if err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		} else if n == 0 {
+			return fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagAlreadyPresent, tag)
+		}
+		if err := bumpVersion(opCtx, tx, id); err != nil {
+			return err
+		}
+		updated, err = getTodo(opCtx, tx, id)
+		return err
+	})
+	return updated, err
+}
+
+func (s *SQLiteStorage) RemoveTag(ctx context.Context, id int, tag string) (todo.Todo, error) {
+	if err := todo.ValidateID(id); err != nil {
+		return todo.Todo{}, err
+	}
+	tag = todo.NormalizeTag(tag)
+	if err := todo.ValidateTag(tag); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w: %q", id, todo.ErrTagNotPresent, tag),
+		"DELETE FROM todo_tags WHERE todo_id = ? AND tag = ?", id, tag)
+}
+
+func (s *SQLiteStorage) AddDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.updateIf(ctx, id,
+		func(ctx context.Context, tx *sql.Tx) error {
+			var present bool
+			err := tx.QueryRowContext(ctx,
+				"SELECT EXISTS (SELECT 1 FROM todo_dependencies WHERE todo_id = ? AND blocker_id = ?)", id, blockerID).Scan(&present)
+			if err != nil {
+				return fmt.Errorf("failed to find dependency: %w", err)
+			}
+			if present {
+				return fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyPresent, blockerID)
+			}
+			return todo.CheckDependency(id, blockerID, getter(ctx, tx))
+		},
+		fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyPresent, blockerID),
+		"INSERT INTO todo_dependencies (todo_id, blocker_id) VALUES (?, ?)", id, blockerID)
+}
+
+func (s *SQLiteStorage) RemoveDependency(ctx context.Context, id, blockerID int) (todo.Todo, error) {
+	if err := todo.ValidateDependency(id, blockerID); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.update(ctx, id, fmt.Errorf("todo %d: %w: todo %d", id, todo.ErrDependencyNotPresent, blockerID),
+		"DELETE FROM todo_dependencies WHERE todo_id = ? AND blocker_id = ?", id, blockerID)
+}
+
+func (s *SQLiteStorage) Update(ctx context.Context, patch todo.Todo, mask []string) (todo.Todo, error) {
+	patch, err := todo.ValidateUpdate(patch, mask)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var updated todo.Todo
+	err = s.inTx(opCtx, func(tx *sql.Tx) error {
+		current, err := getTodo(opCtx, tx, patch.ID)
+		if err != nil {
+			return err
+		}
+		if current.Trashed() {
+			return fmt.Errorf("todo with id %d: %w", patch.ID, todo.ErrNotFound)
+		}
+		if err := todo.CheckVersion(opCtx, current); err != nil {
+			return err
+		}
+		next, changed := current.Apply(patch, mask)
+		if !changed {
+			return fmt.Errorf("todo %d: %w", patch.ID, todo.ErrTodoUnchanged)
+		}
+		if next.ProjectID != current.ProjectID {
+			if err := todo.CheckProject(next.ProjectID, projectGetter(opCtx, tx)); err != nil {
+				return err
+			}
+			if found, err := hasSubtasks(opCtx, tx, next.ID); err != nil {
+				return err
+			} else if found {
+				return fmt.Errorf("todo %d: %w", next.ID, todo.ErrHasSubtasks)
+			}
+		}
+		if next.ParentID != current.ParentID || next.ProjectID != current.ProjectID {
+			if err := todo.CheckParent(next.ID, next.ParentID, next.ProjectID, getter(opCtx, tx)); err != nil {
+				return err
+			}
+		}
+		if next.Completed && !current.Completed {
+			if err := todo.CheckBlockers(current, getter(opCtx, tx)); err != nil {
+				return err
+			}
+		}
+		next = next.Stamp(current, now())
+		_, err = tx.ExecContext(opCtx,
+			`UPDATE todos SET title = ?, description = ?, completed = ?, due_date = ?, due_time = ?, priority = ?, parent_id = ?,
+				project_id = ?, recurrence = ?, updated_at = ?, completed_at = ?, version = version + 1
+			WHERE id = ?`,
+			next.Title, next.Description, next.Completed, next.DueDate, next.DueTime, int(next.Priority), next.ParentID,
+			next.ProjectID, next.Recurrence, sqliteTime(next.UpdatedAt), sqliteTime(next.CompletedAt), next.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		if !slices.Equal(next.Tags, current.Tags) {
+			if _, err := tx.ExecContext(opCtx, "DELETE FROM todo_tags WHERE todo_id = ?", next.ID); err != nil {
+				return fmt.Errorf("failed to update tags: %w", err)
+			}
+			for _, tag := range next.Tags {
+				if _, err := tx.ExecContext(opCtx, "INSERT INTO todo_tags (todo_id, tag) VALUES (?, ?)", next.ID, tag); err != nil {
+					return fmt.Errorf("failed to update tags: %w", err)
+				}
+			}
+		}
+		if updated, err = getTodo(opCtx, tx, next.ID); err != nil {
+			return err
+		}
+		return recur(opCtx, tx, &updated)
+	})
+	return updated, err
+}
+
+// getProject returns the project of ctx's owner with the given ID.
+func getProject(ctx context.Context, q querier, id int) (todo.Project, error) {
+	p := todo.Project{ID: id, Owner: todo.Owner(ctx)}
+	err := q.QueryRowContext(ctx, "SELECT name, archived FROM projects WHERE id = ? AND owner = ?", id, p.Owner).
+		Scan(&p.Name, &p.Archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return todo.Project{}, fmt.Errorf("project with id %d: %w", id, todo.ErrProjectNotFound)
+	}
+	if err != nil {
+		return todo.Project{}, fmt.Errorf("failed to find project: %w", err)
+	}
+	return p, nil
+}
+
+// projectGetter returns getProject bound to q, for todo.CheckProject.
+func projectGetter(ctx context.Context, q querier) func(id int) (todo.Project, error) {
+	return func(id int) (todo.Project, error) { return getProject(ctx, q, id) }
+}
+
+// selectProjects returns the projects of ctx's owner.
+func selectProjects(ctx context.Context, q querier) ([]todo.Project, error) {
+	owner := todo.Owner(ctx)
+	rows, err := q.QueryContext(ctx, "SELECT id, name, archived FROM projects WHERE owner = ? ORDER BY id", owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find projects: %w", err)
+	}
+	defer rows.Close()
+	projects := []todo.Project{}
+	for rows.Next() {
+		p := todo.Project{Owner: owner}
+		if err := rows.Scan(&p.ID, &p.Name, &p.Archived); err != nil {
+			return nil, fmt.Errorf("failed to decode projects: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+	return projects, nil
+}
+
+// checkProjectName reports ErrProjectNameTaken if a project of ctx's owner
+// other than the one with ID id is called name in any case; the column's
+// NOCASE collation only folds ASCII letters.
+func checkProjectName(ctx context.Context, q querier, id int, name string) error {
+	projects, err := selectProjects(ctx, q)
+	if err != nil {
+		return err
+	}
+	if p, ok := todo.ProjectNamed(projects, name); ok && p.ID != id {
+		return fmt.Errorf("%w: %q", todo.ErrProjectNameTaken, name)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) CreateProject(ctx context.Context, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var created todo.Project
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if err := checkProjectName(opCtx, tx, 0, name); err != nil {
+			return err
+		}
+		owner := todo.Owner(opCtx)
+		result, err := tx.ExecContext(opCtx, "INSERT INTO projects (owner, name) VALUES (?, ?)", owner, name)
+		if err != nil {
+			return fmt.Errorf("failed to insert project: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to insert project: %w", err)
+		}
+		created = todo.Project{ID: int(id), Name: name, Owner: owner}
+		return nil
+	})
+	return created, err
+}
+
+func (s *SQLiteStorage) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	return selectProjects(opCtx, s.db)
+}
+
+// updateProject runs stmt on the project with the given ID, after check,
+// which may be nil, and returns the project as changed. stmt's WHERE clause
+// must only match the project when the statement would change it; if it
+// matches nothing, unchanged is reported.
+func (s *SQLiteStorage) updateProject(ctx context.Context, id int, check func(ctx context.Context, tx *sql.Tx) error, unchanged error, stmt string, args ...any) (todo.Project, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var updated todo.Project
+	err := s.inTx(opCtx, func(tx *sql.Tx) error {
+		if _, err := getProject(opCtx, tx, id); err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(opCtx, tx); err != nil {
+				return err
+			}
+		}
+		result, err := tx.ExecContext(opCtx, stmt, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update project: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to update project: %w", err)
+		} else if n == 0 {
+			return unchanged
+		}
+		updated, err = getProject(opCtx, tx, id)
+		return err
+	})
+	return updated, err
+}
+
+func (s *SQLiteStorage) RenameProject(ctx context.Context, id int, name string) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	if err := todo.ValidateProjectName(name); err != nil {
+		return todo.Project{}, err
+	}
+	unchanged := fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged)
+	return s.updateProject(ctx, id,
+		func(ctx context.Context, tx *sql.Tx) error {
+			current, err := getProject(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if current.Name == name {
+				return unchanged
+			}
+			return checkProjectName(ctx, tx, id, name)
+		},
+		unchanged,
+		// The column's NOCASE collation would take a change of case for no
+		// change, so the names are compared as binary.
+		"UPDATE projects SET name = ? WHERE id = ? AND name != ? COLLATE BINARY", name, id, name)
+}
+
+func (s *SQLiteStorage) ArchiveProject(ctx context.Context, id int, archived bool) (todo.Project, error) {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return todo.Project{}, err
+	}
+	return s.updateProject(ctx, id, nil, fmt.Errorf("project %d: %w", id, todo.ErrProjectUnchanged),
+		"UPDATE projects SET archived = ? WHERE id = ? AND archived != ?", archived, id, archived)
+}
+
+func (s *SQLiteStorage) DeleteProject(ctx context.Context, id int) error {
+	if err := todo.ValidateProjectID(id); err != nil {
+		return err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return s.inTx(opCtx, func(tx *sql.Tx) error {
+		if _, err := getProject(opCtx, tx, id); err != nil {
+			return err
+		}
+		var hasTodos bool
+		if err := tx.QueryRowContext(opCtx, "SELECT EXISTS (SELECT 1 FROM todos WHERE owner = ? AND project_id = ?)",
+			todo.Owner(opCtx), id).Scan(&hasTodos); err != nil {
+			return fmt.Errorf("failed to find todos: %w", err)
+		}
+		if hasTodos {
+			return fmt.Errorf("project %d: %w", id, todo.ErrProjectNotEmpty)
+		}
+		if _, err := tx.ExecContext(opCtx, "DELETE FROM projects WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete project: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *SQLiteStorage) AddKey(ctx context.Context, user, hash string) (todo.APIKey, error) {
+	if err := todo.ValidateUser(user); err != nil {
+		return todo.APIKey{}, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	key := todo.APIKey{User: user, Hash: hash, CreatedAt: now()}
+	result, err := s.db.ExecContext(opCtx, "INSERT INTO api_keys (user_name, hash, created_at) VALUES (?, ?, ?)",
+		user, hash, sqliteTime(key.CreatedAt))
+	if err != nil {
+		return todo.APIKey{}, fmt.Errorf("failed to insert API key: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return todo.APIKey{}, fmt.Errorf("failed to insert API key: %w", err)
+	}
+	key.ID = int(id)
+	return key, nil
+}
+
+// selectKeys returns the API keys selected by clauses, the part of the
+// query following WHERE.
+func selectKeys(ctx context.Context, q querier, clauses string, args ...any) ([]todo.APIKey, error) {
+	rows, err := q.QueryContext(ctx, "SELECT id, user_name, hash, created_at FROM api_keys WHERE "+clauses, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API keys: %w", err)
+	}
+	defer rows.Close()
+	keys := []todo.APIKey{}
+	for rows.Next() {
+		var key todo.APIKey
+		var createdAt sql.NullString
+		if err := rows.Scan(&key.ID, &key.User, &key.Hash, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to decode API keys: %w", err)
+		}
+		if key.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+			return nil, fmt.Errorf("failed to decode API keys: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *SQLiteStorage) ListKeys(ctx context.Context) ([]todo.APIKey, error) {
+	opCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	return selectKeys(opCtx, s.db, "1 = 1 ORDER BY id")
+}
+
+func (s *SQLiteStorage) KeyByHash(ctx context.Context, hash string) (todo.APIKey, error) {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	keys, err := selectKeys(opCtx, s.db, "hash = ?", hash)
+	if err != nil {
+		return todo.APIKey{}, err
+	}
+	if len(keys) == 0 {
+		return todo.APIKey{}, todo.ErrKeyNotFound
+	}
+	return keys[0], nil
+}
+
+func (s *SQLiteStorage) RevokeKey(ctx context.Context, id int) error {
+	opCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(opCtx, "DELETE FROM api_keys WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("API key with id %d: %w", id, todo.ErrKeyNotFound)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) Close(_ context.Context) error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.db.Close()
+	})
+	return err
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"modernc.org/sqlite"
+
+	"github.com/amharshit45/todos-cli-/query"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func init() {
+	// contains_fold matches query.Match's ~ operator exactly, including
+	// non-ASCII case folding, which SQLite's own lower() and LIKE lack.
+	sqlite.MustRegisterDeterministicScalarFunction("contains_fold", 2,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			have, _ := args[0].(string)
+			want, _ := args[1].(string)
+			return strings.Contains(strings.ToLower(have), strings.ToLower(want)), nil
+		})
+}
+
+// columns maps query fields to columns of the todos table. Tags live in
+// their own table and are handled separately.
+var columns = map[query.Field]string{
+	query.FieldID:          "id",
+	query.FieldTitle:       "title",
+	query.FieldDescription: "description",
+	query.FieldCompleted:   "completed",
+	query.FieldDueDate:     "due_date",
+	query.FieldDueTime:     "due_time",
+	query.FieldPriority:    "priority",
+}
+
+// hasTagSQL matches todos carrying the tag bound to its placeholder.
+const hasTagSQL = "EXISTS (SELECT 1 FROM todo_tags WHERE todo_tags.todo_id = todos.id AND todo_tags.tag = ?)"
+
+// exprToSQL translates a parsed filter into an SQL condition on the todos
+// table and its arguments, following the semantics of query.Match.
+func exprToSQL(e query.Expr) (string, []any) {
+	switch e := e.(type) {
+	case *query.And:
+		l, la := exprToSQL(e.Left)
+		r, ra := exprToSQL(e.Right)
+		return "(" + l + " AND " + r + ")", append(la, ra...)
+	case *query.Or:
+		l, la := exprToSQL(e.Left)
+		r, ra := exprToSQL(e.Right)
+		return "(" + l + " OR " + r + ")", append(la, ra...)
+	case *query.Not:
+		x, xa := exprToSQL(e.X)
+		return "NOT " + x, xa
+	case *query.Comparison:
+		return comparisonToSQL(e)
+	}
+	return "1", nil
+}
+
+func comparisonToSQL(c *query.Comparison) (string, []any) {
+	switch c.Field {
+	case query.FieldTag:
+		if c.Op == query.OpEq {
+			return hasTagSQL, []any{c.Value}
+		}
+		return "NOT " + hasTagSQL, []any{c.Value}
+	case query.FieldCompleted:
+		return fmt.Sprintf("completed %s ?", c.Op), []any{c.Value}
+	case query.FieldDueDate, query.FieldDueTime:
+		// Unset due fields are stored as "", which must only satisfy
+		// comparisons for equality, as in query.Match.
+		col := columns[c.Field]
+		if c.Op != query.OpEq && c.Op != query.OpNe {
+			if c.Value == "" {
+				return "0", nil
+			}
+			return fmt.Sprintf("(%s != '' AND %s %s ?)", col, col, c.Op), []any{c.Value}
+		}
+	}
+	col := columns[c.Field]
+	if c.Op == query.OpContains {
+		return fmt.Sprintf("contains_fold(%s, ?)", col), []any{c.Value}
+	}
+	value := c.Value
+	if p, ok := value.(todo.Priority); ok {
+		value = int(p)
+	}
+	return fmt.Sprintf("%s %s ?", col, c.Op), []any{value}
+}
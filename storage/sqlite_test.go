@@ -0,0 +1,458 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/storage/storagetest"
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+func newTestSQLiteStorage(t *testing.T) *SQLiteStorage {
+	t.Helper()
+
+	s, err := NewSQLiteStorage(context.Background(), filepath.Join(t.TempDir(), "todos.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Close(context.Background())
+	})
+	return s
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) todo.Storage { return newTestSQLiteStorage(t) })
+	storagetest.RunKeys(t, func(t *testing.T) todo.KeyStore { return newTestSQLiteStorage(t) })
+}
+
+func TestSQLiteAddAndList(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 0 {
+		t.Fatalf("expected 0 todos, got %d", len(todos))
+	}
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "first", Description: "first details", DueDate: "2026-03-05", DueTime: "09:30", Priority: todo.PriorityHigh}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := s.Add(ctx, todo.Draft{Title: "second"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	todos, _, err = s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []todo.Todo{
+		{ID: 1, Title: "first", Description: "first details", DueDate: "2026-03-05", DueTime: "09:30", Priority: todo.PriorityHigh, Version: 1},
+		{ID: 2, Title: "second", Version: 1},
+	}
+	for i := range todos {
+		todos[i] = storagetest.Timeless(todos[i])
+	}
+	if fmt.Sprintf("%+v", todos) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("got %+v, want %+v", todos, want)
+	}
+
+	if _, err := s.Add(ctx, todo.Draft{}); !errors.Is(err, todo.ErrEmptyTitle) {
+		t.Fatalf("expected ErrEmptyTitle, got: %v", err)
+	}
+}
+
+func TestSQLiteDelete(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Tags: []string{"work"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete(ctx, 1); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+	if err := s.Delete(ctx, 0); !errors.Is(err, todo.ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got: %v", err)
+	}
+
+	// IDs are not reused once deleted.
+	if _, err := s.Add(ctx, todo.Draft{Title: "next"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 2 || len(todos[0].Tags) != 0 {
+		t.Fatalf("expected only a fresh todo 2, got %+v", todos)
+	}
+}
+
+func TestSQLiteSetCompleted(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := s.SetCompleted(ctx, 1, false); !errors.Is(err, todo.ErrAlreadyIncomplete) {
+		t.Fatalf("expected ErrAlreadyIncomplete, got: %v", err)
+	}
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+	if _, err := s.SetCompleted(ctx, 1, true); !errors.Is(err, todo.ErrAlreadyCompleted) {
+		t.Fatalf("expected ErrAlreadyCompleted, got: %v", err)
+	}
+	if _, err := s.SetCompleted(ctx, 999, true); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !todos[0].Completed {
+		t.Fatal("expected todo to be completed")
+	}
+}
+
+func TestSQLiteEdits(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "task", Description: "details"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if _, err := s.EditTitle(ctx, 1, "renamed"); err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+	if _, err := s.EditTitle(ctx, 1, "renamed"); !errors.Is(err, todo.ErrTitleUnchanged) {
+		t.Fatalf("expected ErrTitleUnchanged, got: %v", err)
+	}
+	if _, err := s.EditTitle(ctx, 999, "x"); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+	if _, err := s.EditTitle(ctx, 1, ""); !errors.Is(err, todo.ErrEmptyTitle) {
+		t.Fatalf("expected ErrEmptyTitle, got: %v", err)
+	}
+
+	if _, err := s.EditDescription(ctx, 1, ""); err != nil {
+		t.Fatalf("EditDescription: %v", err)
+	}
+	if _, err := s.EditDescription(ctx, 1, ""); !errors.Is(err, todo.ErrDescriptionUnchanged) {
+		t.Fatalf("expected ErrDescriptionUnchanged, got: %v", err)
+	}
+
+	if _, err := s.EditDue(ctx, 1, "", ""); !errors.Is(err, todo.ErrDueUnchanged) {
+		t.Fatalf("expected ErrDueUnchanged when clearing an unset due date, got: %v", err)
+	}
+	if _, err := s.EditDue(ctx, 1, "2026-03-05", "18:00"); err != nil {
+		t.Fatalf("EditDue: %v", err)
+	}
+	if _, err := s.EditDue(ctx, 1, "2026-03-05", ""); err != nil {
+		t.Fatalf("EditDue: %v", err)
+	}
+
+	if _, err := s.EditPriority(ctx, 1, todo.PriorityNone); !errors.Is(err, todo.ErrPriorityUnchanged) {
+		t.Fatalf("expected ErrPriorityUnchanged, got: %v", err)
+	}
+	if _, err := s.EditPriority(ctx, 1, todo.PriorityUrgent); err != nil {
+		t.Fatalf("EditPriority: %v", err)
+	}
+
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := todo.Todo{ID: 1, Title: "renamed", DueDate: "2026-03-05", Priority: todo.PriorityUrgent, Version: 6}
+	if fmt.Sprintf("%+v", storagetest.Timeless(todos[0])) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("got %+v, want %+v", todos[0], want)
+	}
+}
+
+func TestSQLiteTags(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Add(ctx, todo.Draft{Title: "deploy", Tags: []string{"Work", "work", "ops"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := s.Add(ctx, todo.Draft{Title: "groceries", Tags: []string{"home"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	todos, _, err := s.List(ctx, todo.ListOptions{Tags: []string{"WORK"}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 1 {
+		t.Fatalf("expected only todo 1, got %+v", todos)
+	}
+	if fmt.Sprint(todos[0].Tags) != "[work ops]" {
+		t.Fatalf("expected normalized tags [work ops], got %v", todos[0].Tags)
+	}
+
+	if _, err := s.AddTag(ctx, 2, "errand"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	if _, err := s.AddTag(ctx, 2, "Errand"); !errors.Is(err, todo.ErrTagAlreadyPresent) {
+		t.Fatalf("expected ErrTagAlreadyPresent, got: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 2, "home"); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 2, "home"); !errors.Is(err, todo.ErrTagNotPresent) {
+		t.Fatalf("expected ErrTagNotPresent, got: %v", err)
+	}
+	if _, err := s.AddTag(ctx, 999, "x"); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 999, "x"); !errors.Is(err, todo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+
+	for i := range todo.MaxTags - 1 {
+		if _, err := s.AddTag(ctx, 2, fmt.Sprintf("t%d", i)); err != nil {
+			t.Fatalf("AddTag %d: %v", i, err)
+		}
+	}
+	if _, err := s.AddTag(ctx, 2, "one-more"); !errors.Is(err, todo.ErrTooManyTags) {
+		t.Fatalf("expected ErrTooManyTags, got: %v", err)
+	}
+}
+
+func TestSQLiteListFilter(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	drafts := []todo.Draft{
+		{Title: "Deploy API", Priority: todo.PriorityHigh, DueDate: "2026-03-05", DueTime: "09:00", Tags: []string{"work"}},
+		{Title: "deploy docs"},
+		{Title: "ÉTÉ groceries", Tags: []string{"home"}},
+	}
+	for _, d := range drafts {
+		if _, err := s.Add(ctx, d); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if _, err := s.SetCompleted(ctx, 2, true); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{`completed=false AND title~"deploy"`, []int{1}},
+		{`title~"DEPLOY"`, []int{1, 2}},
+		{`title~"été"`, []int{3}},
+		{`title="deploy docs"`, []int{2}},
+		{`title="DEPLOY DOCS"`, nil},
+		{`id>=2`, []int{2, 3}},
+		{`priority=none`, []int{2, 3}},
+		{`priority<high`, []int{2, 3}},
+		{`priority>=high`, []int{1}},
+		{`due_date=""`, []int{2, 3}},
+		{`due_date!=""`, []int{1}},
+		{`due_date<2026-04-01`, []int{1}},
+		{`due_date!=2026-03-05`, []int{2, 3}},
+		{`due_time<10:00`, []int{1}},
+		{`tag=work OR tag=home`, []int{1, 3}},
+		{`NOT tag=work`, []int{2, 3}},
+		{`tag!=work AND NOT (completed=true)`, []int{3}},
+		{`title~"a.i"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			todos, _, err := s.List(ctx, todo.ListOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []int
+			for _, td := range todos {
+				got = append(got, td.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got IDs %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, _, err := s.List(ctx, todo.ListOptions{Filter: "colour=red"})
+	if !errors.Is(err, todo.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got: %v", err)
+	}
+}
+
+func TestSQLiteListPagination(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 7; i++ {
+		if _, err := s.Add(ctx, todo.Draft{Title: fmt.Sprintf("task %d", i)}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if err := s.Delete(ctx, 4); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	var ids []int
+	opts := todo.ListOptions{PageSize: 3}
+	for {
+		todos, next, err := s.List(ctx, opts)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, td := range todos {
+			ids = append(ids, td.ID)
+		}
+		if next == "" {
+			break
+		}
+		opts.PageToken = next
+	}
+	if fmt.Sprint(ids) != "[1 2 3 5 6 7]" {
+		t.Fatalf("unexpected IDs across pages: %v", ids)
+	}
+
+	_, _, err := s.List(ctx, todo.ListOptions{PageToken: "bogus"})
+	if !errors.Is(err, todo.ErrInvalidPageToken) {
+		t.Fatalf("expected ErrInvalidPageToken, got: %v", err)
+	}
+}
+
+func TestSQLiteReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todos.db")
+
+	s, err := NewSQLiteStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	if _, err := s.Add(ctx, todo.Draft{Title: "persisted", Tags: []string{"kept"}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	s, err = NewSQLiteStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close(ctx)
+
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatalf("user_version: %v", err)
+	}
+	if version != len(sqliteMigrations) {
+		t.Fatalf("expected schema version %d, got %d", len(sqliteMigrations), version)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(todos) != 1 || todos[0].Title != "persisted" || fmt.Sprint(todos[0].Tags) != "[kept]" {
+		t.Fatalf("expected the persisted todo, got %+v", todos)
+	}
+}
+
+func TestSQLiteMigratesVersions(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todos.db")
+
+	// A database written before todos had versions.
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, stmt := range []string{
+		sqliteMigrations[0],
+		"PRAGMA user_version = 1",
+		"INSERT INTO todos (title) VALUES ('old')",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	s, err := NewSQLiteStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	defer s.Close(ctx)
+	updated, err := s.EditTitle(todo.WithExpectedVersion(ctx, 1), 1, "new")
+	if err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("expected version 2, got %d", updated.Version)
+	}
+	// When it was created went unrecorded, but the edit is stamped.
+	if !updated.CreatedAt.IsZero() || updated.UpdatedAt.IsZero() {
+		t.Fatalf("expected only UpdatedAt to be set, got %+v", updated)
+	}
+}
+
+func TestSQLiteMigratesOwners(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todos.db")
+
+	// A database written before todos and projects had owners.
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	stmts := append(slices.Clone(sqliteMigrations[:8]),
+		"PRAGMA user_version = 8",
+		"INSERT INTO projects (name) VALUES ('Work')",
+		"INSERT INTO todos (title, project_id) VALUES ('old', 1)",
+	)
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	s, err := NewSQLiteStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	defer s.Close(ctx)
+	// Everything stored so far becomes the shared list's.
+	if projects, err := s.ListProjects(ctx); err != nil || len(projects) != 1 || projects[0].Name != "Work" {
+		t.Fatalf("ListProjects: got %+v, %v", projects, err)
+	}
+	todos, _, err := s.List(ctx, todo.ListOptions{ProjectID: 1})
+	if err != nil || len(todos) != 1 || todos[0].Title != "old" {
+		t.Fatalf("List: got %+v, %v", todos, err)
+	}
+	p, err := s.CreateProject(todo.WithOwner(ctx, "alice"), "Work")
+	if err != nil {
+		t.Fatalf("CreateProject as alice: %v", err)
+	}
+	if p.ID != 2 {
+		t.Fatalf("expected project IDs to continue at 2, got %d", p.ID)
+	}
+}
@@ -0,0 +1,72 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// KeyFactory returns a new key store without keys for a single subtest,
+// like Factory.
+type KeyFactory func(t *testing.T) todo.KeyStore
+
+// RunKeys exercises the todo.KeyStore contract against key stores made by
+// newStore.
+func RunKeys(t *testing.T, newStore KeyFactory) {
+	t.Run("Keys", func(t *testing.T) { testKeys(t, newStore(t)) })
+}
+
+func testKeys(t *testing.T, s todo.KeyStore) {
+	ctx := context.Background()
+	if keys, err := s.ListKeys(ctx); err != nil || keys == nil || len(keys) != 0 {
+		t.Fatalf("expected an empty, non-nil list, got %#v, %v", keys, err)
+	}
+	_, hash, err := todo.NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	alice, err := s.AddKey(ctx, "alice", hash)
+	if err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+	if alice.ID != 1 || alice.User != "alice" || alice.Hash != hash || alice.CreatedAt.IsZero() {
+		t.Fatalf("unexpected key %+v", alice)
+	}
+	// Keys belong to no owner.
+	bob, err := s.AddKey(todo.WithOwner(ctx, "carol"), "bob", todo.HashToken("bob's token"))
+	if err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+	expectErr(t, "add key for invalid user", errOfKey(s.AddKey(ctx, "bad user", todo.HashToken("t"))), todo.ErrInvalidUser)
+
+	keys, err := s.ListKeys(todo.WithOwner(ctx, "carol"))
+	if err != nil {
+		t.Fatalf("ListKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != alice.ID || keys[1].ID != bob.ID || keys[1].User != "bob" {
+		t.Fatalf("unexpected keys %+v", keys)
+	}
+	found, err := s.KeyByHash(ctx, todo.HashToken("bob's token"))
+	if err != nil {
+		t.Fatalf("KeyByHash: %v", err)
+	}
+	if found.ID != bob.ID || found.User != "bob" || !found.CreatedAt.Equal(bob.CreatedAt) {
+		t.Fatalf("found %+v, want %+v", found, bob)
+	}
+	expectErr(t, "find unknown hash", errOfKey(s.KeyByHash(ctx, todo.HashToken("guess"))), todo.ErrKeyNotFound)
+
+	if err := s.RevokeKey(ctx, bob.ID); err != nil {
+		t.Fatalf("RevokeKey: %v", err)
+	}
+	expectErr(t, "find revoked key", errOfKey(s.KeyByHash(ctx, bob.Hash)), todo.ErrKeyNotFound)
+	expectErr(t, "revoke revoked key", s.RevokeKey(ctx, bob.ID), todo.ErrKeyNotFound)
+	if keys, err := s.ListKeys(ctx); err != nil || len(keys) != 1 || keys[0].ID != alice.ID {
+		t.Fatalf("expected only key %d left, got %+v, %v", alice.ID, keys, err)
+	}
+}
+
+// errOfKey drops the key returned by a KeyStore method, keeping its error.
+func errOfKey(_ todo.APIKey, err error) error {
+	return err
+}
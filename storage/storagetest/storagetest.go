@@ -0,0 +1,1578 @@
+// Package storagetest provides a conformance suite for todo.Storage
+// implementations. Every backend, local or remote, is expected to pass it
+// unchanged, which keeps their behaviour, and in particular the sentinel
+// errors they return, interchangeable.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amharshit45/todos-cli-/todo"
+)
+
+// Factory returns a new, empty storage for a single subtest. It should
+// register any cleanup with t; the suite may already have closed the
+// storage by then, so cleanup must tolerate a second Close.
+type Factory func(t *testing.T) todo.Storage
+
+// Run exercises the full todo.Storage contract against storages made by
+// newStorage, one per subtest.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s todo.Storage)
+	}{
+		{"AddAndList", testAddAndList},
+		{"IDAllocation", testIDAllocation},
+		{"Ordering", testOrdering},
+		{"AddValidation", testAddValidation},
+		{"InvalidID", testInvalidID},
+		{"NotFound", testNotFound},
+		{"SetCompleted", testSetCompleted},
+		{"Edits", testEdits},
+		{"ReturnedTodos", testReturnedTodos},
+		{"Unchanged", testUnchanged},
+		{"Update", testUpdate},
+		{"UpdateValidation", testUpdateValidation},
+		{"Versions", testVersions},
+		{"Timestamps", testTimestamps},
+		{"VersionConflicts", testVersionConflicts},
+		{"ConcurrentConflicts", testConcurrentConflicts},
+		{"Trash", testTrash},
+		{"Restore", testRestore},
+		{"PurgeTrash", testPurgeTrash},
+		{"Subtasks", testSubtasks},
+		{"SubtaskTrash", testSubtaskTrash},
+		{"Dependencies", testDependencies},
+		{"DependencyTrash", testDependencyTrash},
+		{"Recurrence", testRecurrence},
+		{"RecurrenceRules", testRecurrenceRules},
+		{"Projects", testProjects},
+		{"ProjectTodos", testProjectTodos},
+		{"ProjectMoves", testProjectMoves},
+		{"Owners", testOwners},
+		{"Tags", testTags},
+		{"TagLimit", testTagLimit},
+		{"Filter", testFilter},
+		{"Pagination", testPagination},
+		{"InvalidListOptions", testInvalidListOptions},
+		{"ConcurrentAdds", testConcurrentAdds},
+		{"CloseIdempotent", testCloseIdempotent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+func add(t *testing.T, s todo.Storage, draft todo.Draft) todo.Todo {
+	t.Helper()
+	added, err := s.Add(context.Background(), draft)
+	if err != nil {
+		t.Fatalf("Add(%q): %v", draft.Title, err)
+	}
+	return added
+}
+
+// errOf drops the todo returned by a Storage method, keeping its error.
+func errOf(_ todo.Todo, err error) error {
+	return err
+}
+
+func list(t *testing.T, s todo.Storage, opts todo.ListOptions) []todo.Todo {
+	t.Helper()
+	todos, _, err := s.List(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	return todos
+}
+
+func ids(todos []todo.Todo) []int {
+	ids := make([]int, len(todos))
+	for i, td := range todos {
+		ids[i] = td.ID
+	}
+	return ids
+}
+
+func expectIDs(t *testing.T, todos []todo.Todo, want ...int) {
+	t.Helper()
+	if got := ids(todos); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got IDs %v, want %v", got, want)
+	}
+}
+
+func expectErr(t *testing.T, what string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("%s: expected %v, got: %v", what, want, err)
+	}
+}
+
+// Timeless returns td without the CreatedAt, UpdatedAt and CompletedAt
+// the storage stamped it with, for comparing it with a literal todo.
+func Timeless(td todo.Todo) todo.Todo {
+	td.CreatedAt, td.UpdatedAt, td.CompletedAt = time.Time{}, time.Time{}, time.Time{}
+	return td
+}
+
+// get returns the stored todo with the given ID.
+func get(t *testing.T, s todo.Storage, id int) todo.Todo {
+	t.Helper()
+	todos := list(t, s, todo.ListOptions{Filter: fmt.Sprintf("id=%d", id)})
+	if len(todos) != 1 {
+		t.Fatalf("expected todo %d to exist, got %v", id, ids(todos))
+	}
+	return todos[0]
+}
+
+func testAddAndList(t *testing.T, s todo.Storage) {
+	if todos := list(t, s, todo.ListOptions{}); todos == nil || len(todos) != 0 {
+		t.Fatalf("expected an empty, non-nil list, got %#v", todos)
+	}
+
+	add(t, s, todo.Draft{
+		Title:       "deploy",
+		Description: "prod rollout",
+		DueDate:     "2026-03-05",
+		DueTime:     "09:30",
+		Priority:    todo.PriorityHigh,
+		Tags:        []string{"Work", " ops ", "work"},
+	})
+	add(t, s, todo.Draft{Title: "plain"})
+
+	todos := list(t, s, todo.ListOptions{})
+	expectIDs(t, todos, 1, 2)
+	got := todos[0]
+	if got.Title != "deploy" || got.Description != "prod rollout" || got.Completed ||
+		got.DueDate != "2026-03-05" || got.DueTime != "09:30" || got.Priority != todo.PriorityHigh {
+		t.Fatalf("fields did not round-trip: %+v", got)
+	}
+	if fmt.Sprint(got.Tags) != "[work ops]" {
+		t.Fatalf("expected normalized tags [work ops], got %v", got.Tags)
+	}
+	plain := todos[1]
+	if plain.Description != "" || plain.DueDate != "" || plain.DueTime != "" ||
+		plain.Priority != todo.PriorityNone || len(plain.Tags) != 0 {
+		t.Fatalf("expected zero optional fields, got %+v", plain)
+	}
+}
+
+func testIDAllocation(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		add(t, s, todo.Draft{Title: fmt.Sprintf("task %d", i)})
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 2, 3)
+
+	if err := s.Delete(ctx, 3); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	add(t, s, todo.Draft{Title: "after delete"})
+	todos := list(t, s, todo.ListOptions{})
+	if len(todos) != 3 || todos[2].ID <= 3 {
+		t.Fatalf("expected deleted ID 3 not to be reused, got %v", ids(todos))
+	}
+
+	// A rejected Add must not consume an ID either.
+	if _, err := s.Add(ctx, todo.Draft{}); err == nil {
+		t.Fatal("expected Add without a title to fail")
+	}
+	last := todos[2].ID
+	add(t, s, todo.Draft{Title: "next"})
+	todos = list(t, s, todo.ListOptions{})
+	if todos[3].ID != last+1 {
+		t.Fatalf("expected ID %d after a rejected Add, got %d", last+1, todos[3].ID)
+	}
+}
+
+func testOrdering(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for i := 1; i <= 6; i++ {
+		add(t, s, todo.Draft{Title: fmt.Sprintf("task %d", 7-i), Priority: todo.Priority(i % 5)})
+	}
+	for _, id := range []int{2, 5} {
+		if err := s.Delete(ctx, id); err != nil {
+			t.Fatalf("Delete(%d): %v", id, err)
+		}
+	}
+	if _, err := s.EditTitle(ctx, 1, "renamed"); err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 3, 4, 6)
+}
+
+func testAddValidation(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	tooManyTags := make([]string, todo.MaxTags+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = fmt.Sprintf("t%d", i)
+	}
+	tests := []struct {
+		name  string
+		draft todo.Draft
+		want  error
+	}{
+		{"empty title", todo.Draft{}, todo.ErrEmptyTitle},
+		{"long title", todo.Draft{Title: strings.Repeat("x", todo.MaxTitleLength+1)}, todo.ErrTitleTooLong},
+		{"long description", todo.Draft{Title: "t", Description: strings.Repeat("x", todo.MaxDescriptionLength+1)}, todo.ErrDescriptionTooLong},
+		{"bad due date", todo.Draft{Title: "t", DueDate: "2026-02-30"}, todo.ErrInvalidDueDate},
+		{"bad due time", todo.Draft{Title: "t", DueDate: "2026-03-05", DueTime: "25:00"}, todo.ErrInvalidDueTime},
+		{"time without date", todo.Draft{Title: "t", DueTime: "09:00"}, todo.ErrDueTimeWithoutDate},
+		{"bad priority", todo.Draft{Title: "t", Priority: todo.PriorityUrgent + 1}, todo.ErrInvalidPriority},
+		{"bad tag", todo.Draft{Title: "t", Tags: []string{"two words"}}, todo.ErrInvalidTag},
+		{"too many tags", todo.Draft{Title: "t", Tags: tooManyTags}, todo.ErrTooManyTags},
+		{"bad recurrence", todo.Draft{Title: "t", Recurrence: "FREQ=HOURLY"}, todo.ErrInvalidRecurrence},
+	}
+	for _, tt := range tests {
+		expectErr(t, tt.name, errOf(s.Add(ctx, tt.draft)), tt.want)
+	}
+	if todos := list(t, s, todo.ListOptions{}); len(todos) != 0 {
+		t.Fatalf("expected rejected drafts not to be stored, got %v", ids(todos))
+	}
+}
+
+// mutations calls every Storage method that targets a single todo, with
+// arguments that would be accepted for an existing incomplete todo.
+func mutations(s todo.Storage) map[string]func(ctx context.Context, id int) error {
+	return map[string]func(ctx context.Context, id int) error{
+		"Delete":          func(ctx context.Context, id int) error { return s.Delete(ctx, id) },
+		"DeleteTree":      func(ctx context.Context, id int) error { _, err := s.DeleteTree(ctx, id); return err },
+		"SetCompleted":    func(ctx context.Context, id int) error { return errOf(s.SetCompleted(ctx, id, true)) },
+		"EditTitle":       func(ctx context.Context, id int) error { return errOf(s.EditTitle(ctx, id, "new title")) },
+		"EditDescription": func(ctx context.Context, id int) error { return errOf(s.EditDescription(ctx, id, "new")) },
+		"EditDue":         func(ctx context.Context, id int) error { return errOf(s.EditDue(ctx, id, "2026-03-05", "")) },
+		"EditPriority":    func(ctx context.Context, id int) error { return errOf(s.EditPriority(ctx, id, todo.PriorityLow)) },
+		"AddTag":          func(ctx context.Context, id int) error { return errOf(s.AddTag(ctx, id, "new")) },
+		"RemoveTag":       func(ctx context.Context, id int) error { return errOf(s.RemoveTag(ctx, id, "old")) },
+		// A todo blocked by itself is refused, but only after the todo
+		// itself has been found at the expected version.
+		"AddDependency":    func(ctx context.Context, id int) error { return errOf(s.AddDependency(ctx, id, id)) },
+		"RemoveDependency": func(ctx context.Context, id int) error { return errOf(s.RemoveDependency(ctx, id, 1)) },
+		"Update": func(ctx context.Context, id int) error {
+			return errOf(s.Update(ctx, todo.Todo{ID: id, Title: "new title"}, []string{todo.FieldTitle}))
+		},
+	}
+}
+
+func testInvalidID(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for name, fn := range mutations(s) {
+		for _, id := range []int{0, -1} {
+			expectErr(t, fmt.Sprintf("%s(%d)", name, id), fn(ctx, id), todo.ErrInvalidID)
+		}
+	}
+}
+
+func testNotFound(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "only", Tags: []string{"old"}})
+	for name, fn := range mutations(s) {
+		expectErr(t, name, fn(ctx, 2), todo.ErrNotFound)
+	}
+	if err := s.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for name, fn := range mutations(s) {
+		expectErr(t, name+" after delete", fn(ctx, 1), todo.ErrNotFound)
+	}
+}
+
+func testTrash(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		add(t, s, todo.Draft{Title: fmt.Sprintf("task %d", i), Tags: []string{"work"}})
+	}
+	before := time.Now().Truncate(time.Millisecond)
+	if err := s.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	after := time.Now()
+
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 3)
+	expectIDs(t, list(t, s, todo.ListOptions{Tags: []string{"work"}, Filter: "id>=2"}), 3)
+	trashed := list(t, s, todo.ListOptions{Trashed: true})
+	expectIDs(t, trashed, 2)
+	got := trashed[0]
+	if got.Title != "task 2" || fmt.Sprint(got.Tags) != "[work]" || got.Version != 2 {
+		t.Errorf("expected the trashed todo to keep its fields and bump its version, got %+v", got)
+	}
+	if got.DeletedAt.Before(before) || got.DeletedAt.After(after) {
+		t.Errorf("expected DeletedAt between %v and %v, got %v", before, after, got.DeletedAt)
+	}
+	if !list(t, s, todo.ListOptions{})[0].DeletedAt.IsZero() {
+		t.Error("expected live todos to have no DeletedAt")
+	}
+	expectErr(t, "delete twice", s.Delete(ctx, 2), todo.ErrNotFound)
+
+	// Trashed todos page like live ones.
+	for _, id := range []int{1, 3} {
+		if err := s.Delete(ctx, id); err != nil {
+			t.Fatalf("Delete(%d): %v", id, err)
+		}
+	}
+	page, next, err := s.List(ctx, todo.ListOptions{Trashed: true, PageSize: 2})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	expectIDs(t, page, 1, 2)
+	page, _, err = s.List(ctx, todo.ListOptions{Trashed: true, PageSize: 2, PageToken: next})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	expectIDs(t, page, 3)
+}
+
+func testRestore(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task", Tags: []string{"work"}})
+
+	expectErr(t, "restore live todo", errOf(s.Restore(ctx, 1)), todo.ErrNotInTrash)
+	expectErr(t, "restore missing todo", errOf(s.Restore(ctx, 2)), todo.ErrNotFound)
+	expectErr(t, "restore invalid ID", errOf(s.Restore(ctx, 0)), todo.ErrInvalidID)
+
+	if err := s.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectErr(t, "restore at a stale version", errOf(s.Restore(todo.WithExpectedVersion(ctx, 1), 1)), todo.ErrVersionConflict)
+	restored, err := s.Restore(todo.WithExpectedVersion(ctx, 2), 1)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	want := todo.Todo{ID: 1, Title: "task", Tags: []string{"work"}, Version: 3}
+	if fmt.Sprintf("%+v", Timeless(restored)) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("Restore returned %+v, want %+v", restored, want)
+	}
+	if got := get(t, s, 1); fmt.Sprintf("%+v", Timeless(got)) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("stored %+v, want %+v", got, want)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{Trashed: true}))
+	if _, err := s.EditTitle(ctx, 1, "renamed"); err != nil {
+		t.Fatalf("EditTitle after restore: %v", err)
+	}
+}
+
+func testPurgeTrash(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	if n, err := s.PurgeTrash(ctx); err != nil || n != 0 {
+		t.Fatalf("PurgeTrash of an empty trash: %d, %v", n, err)
+	}
+	for i := 1; i <= 3; i++ {
+		add(t, s, todo.Draft{Title: fmt.Sprintf("task %d", i), Tags: []string{"work"}})
+	}
+	for _, id := range []int{1, 3} {
+		if err := s.Delete(ctx, id); err != nil {
+			t.Fatalf("Delete(%d): %v", id, err)
+		}
+	}
+
+	n, err := s.PurgeTrash(ctx)
+	if err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 todos purged, got %d", n)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{Trashed: true}))
+	expectIDs(t, list(t, s, todo.ListOptions{Tags: []string{"work"}}), 2)
+	expectErr(t, "restore purged todo", errOf(s.Restore(ctx, 1)), todo.ErrNotFound)
+
+	// Purged IDs are not reused.
+	if added := add(t, s, todo.Draft{Title: "next"}); added.ID != 4 {
+		t.Errorf("expected ID 4 after a purge, got %d", added.ID)
+	}
+}
+
+// move makes the todo with the given ID a subtask of parentID.
+func move(s todo.Storage, id, parentID int) error {
+	return errOf(s.Update(context.Background(), todo.Todo{ID: id, ParentID: parentID}, []string{todo.FieldParentID}))
+}
+
+func testSubtasks(t *testing.T, s todo.Storage) {
+	add(t, s, todo.Draft{Title: "parent"})
+	if child := add(t, s, todo.Draft{Title: "child", ParentID: 1}); child.ParentID != 1 {
+		t.Fatalf("expected Add to return parent 1, got %d", child.ParentID)
+	}
+	add(t, s, todo.Draft{Title: "grandchild", ParentID: 2})
+	add(t, s, todo.Draft{Title: "other"})
+
+	expectErr(t, "add under missing parent", errOf(s.Add(context.Background(), todo.Draft{Title: "t", ParentID: 99})), todo.ErrParentNotFound)
+	expectErr(t, "add under invalid parent", errOf(s.Add(context.Background(), todo.Draft{Title: "t", ParentID: -1})), todo.ErrInvalidID)
+	expectErr(t, "move under itself", move(s, 1, 1), todo.ErrParentCycle)
+	expectErr(t, "move under own subtask", move(s, 1, 3), todo.ErrParentCycle)
+	expectErr(t, "move under missing parent", move(s, 4, 99), todo.ErrParentNotFound)
+	expectErr(t, "move missing todo", move(s, 99, 98), todo.ErrNotFound)
+
+	if err := move(s, 4, 2); err != nil {
+		t.Fatalf("move 4 under 2: %v", err)
+	}
+	if got := get(t, s, 4).ParentID; got != 2 {
+		t.Fatalf("expected parent 2, got %d", got)
+	}
+	if err := move(s, 4, 0); err != nil {
+		t.Fatalf("move 4 to the top: %v", err)
+	}
+	if got := get(t, s, 4); got.ParentID != 0 || got.Version != 3 {
+		t.Fatalf("expected top-level todo at version 3, got %+v", got)
+	}
+	if got := get(t, s, 3).ParentID; got != 2 {
+		t.Fatalf("expected grandchild to keep parent 2, got %d", got)
+	}
+}
+
+func testSubtaskTrash(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "parent"})
+	add(t, s, todo.Draft{Title: "child", ParentID: 1})
+	add(t, s, todo.Draft{Title: "grandchild", ParentID: 2})
+	add(t, s, todo.Draft{Title: "sibling", ParentID: 1})
+
+	expectErr(t, "delete parent", s.Delete(ctx, 1), todo.ErrHasSubtasks)
+	expectIDs(t, list(t, s, todo.ListOptions{Trashed: true}))
+	if err := s.Delete(ctx, 4); err != nil {
+		t.Fatalf("Delete leaf: %v", err)
+	}
+	// Deletions in the same millisecond share a timestamp, which would
+	// tie the sibling to the tree below.
+	time.Sleep(2 * time.Millisecond)
+
+	if _, err := s.DeleteTree(todo.WithExpectedVersion(ctx, 2), 1); !errors.Is(err, todo.ErrVersionConflict) {
+		t.Errorf("delete tree at a stale version: expected %v, got: %v", todo.ErrVersionConflict, err)
+	}
+	n, err := s.DeleteTree(todo.WithExpectedVersion(ctx, 1), 1)
+	if err != nil {
+		t.Fatalf("DeleteTree: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 todos deleted, got %d", n)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{}))
+	expectIDs(t, list(t, s, todo.ListOptions{Trashed: true}), 1, 2, 3, 4)
+	expectErr(t, "add under trashed parent", errOf(s.Add(ctx, todo.Draft{Title: "t", ParentID: 1})), todo.ErrParentNotFound)
+	expectErr(t, "restore subtask of trashed parent", errOf(s.Restore(ctx, 2)), todo.ErrParentNotFound)
+
+	restored, err := s.Restore(ctx, 1)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.ID != 1 || restored.Trashed() || restored.Version != 3 {
+		t.Fatalf("unexpected restored todo %+v", restored)
+	}
+	// The tree comes back without the sibling trashed before it.
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 2, 3)
+	expectIDs(t, list(t, s, todo.ListOptions{Trashed: true}), 4)
+	if got := get(t, s, 3); got.Version != 3 {
+		t.Fatalf("expected grandchild at version 3, got %d", got.Version)
+	}
+	if _, err := s.Restore(ctx, 4); err != nil {
+		t.Fatalf("Restore sibling: %v", err)
+	}
+}
+
+func testDependencies(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for _, title := range []string{"design", "build", "ship"} {
+		add(t, s, todo.Draft{Title: title})
+	}
+	blocked, err := s.AddDependency(todo.WithExpectedVersion(ctx, 1), 2, 1)
+	if err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if fmt.Sprint(blocked.BlockedBy) != "[1]" || blocked.Version != 2 {
+		t.Fatalf("unexpected blocked todo %+v", blocked)
+	}
+	if _, err := s.AddDependency(ctx, 3, 2); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+
+	expectErr(t, "duplicate", errOf(s.AddDependency(ctx, 2, 1)), todo.ErrDependencyPresent)
+	expectErr(t, "self", errOf(s.AddDependency(ctx, 1, 1)), todo.ErrDependencyCycle)
+	expectErr(t, "cycle", errOf(s.AddDependency(ctx, 1, 3)), todo.ErrDependencyCycle)
+	expectErr(t, "missing blocker", errOf(s.AddDependency(ctx, 1, 99)), todo.ErrBlockerNotFound)
+	expectErr(t, "invalid blocker", errOf(s.AddDependency(ctx, 1, 0)), todo.ErrInvalidID)
+	expectErr(t, "invalid blocker removal", errOf(s.RemoveDependency(ctx, 1, -1)), todo.ErrInvalidID)
+	expectErr(t, "missing dependency", errOf(s.RemoveDependency(ctx, 1, 2)), todo.ErrDependencyNotPresent)
+
+	expectErr(t, "complete blocked todo", errOf(s.SetCompleted(ctx, 2, true)), todo.ErrBlocked)
+	expectErr(t, "update blocked todo", errOf(s.Update(ctx, todo.Todo{ID: 2, Completed: true}, []string{todo.FieldCompleted})), todo.ErrBlocked)
+	if got := get(t, s, 2); got.Completed || got.Version != 2 {
+		t.Fatalf("blocked todo was changed: %+v", got)
+	}
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
+		t.Fatalf("SetCompleted(1): %v", err)
+	}
+	if _, err := s.Update(ctx, todo.Todo{ID: 2, Completed: true}, []string{todo.FieldCompleted}); err != nil {
+		t.Fatalf("Update once unblocked: %v", err)
+	}
+	// An already completed todo stays so even if a blocker reopens.
+	if _, err := s.SetCompleted(ctx, 1, false); err != nil {
+		t.Fatalf("SetCompleted(1, false): %v", err)
+	}
+	expectErr(t, "complete completed todo", errOf(s.SetCompleted(ctx, 2, true)), todo.ErrAlreadyCompleted)
+
+	unblocked, err := s.RemoveDependency(ctx, 3, 2)
+	if err != nil {
+		t.Fatalf("RemoveDependency: %v", err)
+	}
+	if len(unblocked.BlockedBy) != 0 {
+		t.Fatalf("expected no blockers left, got %v", unblocked.BlockedBy)
+	}
+	if _, err := s.SetCompleted(ctx, 3, true); err != nil {
+		t.Fatalf("SetCompleted once unblocked: %v", err)
+	}
+}
+
+func testDependencyTrash(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for _, title := range []string{"task", "blocker", "other"} {
+		add(t, s, todo.Draft{Title: title})
+	}
+	for _, blockerID := range []int{2, 3} {
+		if _, err := s.AddDependency(ctx, 1, blockerID); err != nil {
+			t.Fatalf("AddDependency(1, %d): %v", blockerID, err)
+		}
+	}
+	if _, err := s.SetCompleted(ctx, 3, true); err != nil {
+		t.Fatalf("SetCompleted(3): %v", err)
+	}
+	if err := s.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectErr(t, "block on trashed todo", errOf(s.AddDependency(ctx, 3, 2)), todo.ErrBlockerNotFound)
+
+	// A blocker in the trash no longer blocks, and purging it drops it.
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+	if got := get(t, s, 1); fmt.Sprint(got.BlockedBy) != "[2 3]" {
+		t.Fatalf("expected blockers [2 3], got %v", got.BlockedBy)
+	}
+	if _, err := s.PurgeTrash(ctx); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if got := get(t, s, 1); fmt.Sprint(got.BlockedBy) != "[3]" {
+		t.Fatalf("expected blockers [3] after the purge, got %v", got.BlockedBy)
+	}
+}
+
+func testRecurrence(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "parent"})
+	added := add(t, s, todo.Draft{
+		Title: "report", DueDate: "2099-03-02", DueTime: "09:00", Priority: todo.PriorityHigh, Tags: []string{"work"}, ParentID: 1,
+		Recurrence: "rrule:freq=weekly;byday=mo,th;interval=2",
+	})
+	if added.Recurrence != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH" {
+		t.Fatalf("expected the rule in canonical form, got %q", added.Recurrence)
+	}
+
+	done, err := s.SetCompleted(todo.WithExpectedVersion(ctx, 1), 2, true)
+	if err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+	if done.NextID != 3 || !done.Recurred || done.Version != 2 {
+		t.Fatalf("expected todo 2 at version 2 to have recurred as 3, got %+v", done)
+	}
+	want := todo.Todo{
+		ID: 3, Title: "report", DueDate: "2099-03-05", DueTime: "09:00", Priority: todo.PriorityHigh, Tags: []string{"work"}, ParentID: 1,
+		Recurrence: added.Recurrence, Version: 1,
+	}
+	if got := get(t, s, 3); fmt.Sprintf("%+v", Timeless(got)) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("unexpected next occurrence:\n got  %+v\n want %+v", got, want)
+	}
+
+	// A todo recurs once: completing it again after reopening adds nothing.
+	if _, err := s.SetCompleted(ctx, 2, false); err != nil {
+		t.Fatalf("SetCompleted(false): %v", err)
+	}
+	if again, err := s.SetCompleted(ctx, 2, true); err != nil || again.NextID != 3 || again.Recurred {
+		t.Fatalf("SetCompleted again: %+v, %v", again, err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 2, 3)
+
+	// Update recurs a todo it completes too.
+	next, err := s.Update(ctx, todo.Todo{ID: 3, Completed: true}, []string{todo.FieldCompleted})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if next.NextID != 4 || !next.Recurred || get(t, s, 4).DueDate != "2099-03-16" {
+		t.Fatalf("expected todo 3 to recur as 4 due 2099-03-16, got %+v", next)
+	}
+
+	// The rule can be changed or dropped like any other field.
+	if _, err := s.Update(ctx, todo.Todo{ID: 4}, []string{todo.FieldRecurrence}); err != nil {
+		t.Fatalf("Update(recurrence): %v", err)
+	}
+	if last, err := s.SetCompleted(ctx, 4, true); err != nil || last.NextID != 0 || last.Recurred {
+		t.Fatalf("expected a one-off todo not to recur, got %+v, %v", last, err)
+	}
+
+	// A todo completed after its due date recurs after the day it was
+	// completed, skipping the dates it missed.
+	late := add(t, s, todo.Draft{Title: "water plants", DueDate: "2020-01-01", Recurrence: "FREQ=DAILY"})
+	today := time.Now().UTC().Format(todo.DueDateLayout)
+	done, err = s.SetCompleted(ctx, late.ID, true)
+	if err != nil {
+		t.Fatalf("SetCompleted(late): %v", err)
+	}
+	if due := get(t, s, done.NextID).DueDate; due <= today {
+		t.Fatalf("expected the next occurrence after %s, got %s", today, due)
+	}
+
+	// COUNT runs down with each occurrence.
+	counted := add(t, s, todo.Draft{Title: "backup", DueDate: "2099-01-31", Recurrence: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2"})
+	done, err = s.SetCompleted(ctx, counted.ID, true)
+	if err != nil {
+		t.Fatalf("SetCompleted(counted): %v", err)
+	}
+	final := get(t, s, done.NextID)
+	if final.DueDate != "2099-02-28" || final.Recurrence != "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=1" {
+		t.Fatalf("unexpected final occurrence %+v", final)
+	}
+	if done, err := s.SetCompleted(ctx, final.ID, true); err != nil || done.NextID != 0 {
+		t.Fatalf("expected the last occurrence not to recur, got %+v, %v", done, err)
+	}
+}
+
+// testRecurrenceRules checks the due date of the occurrence that follows
+// one completed ahead of its due date, for each kind of rule.
+func testRecurrenceRules(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	tests := []struct {
+		rule, due, want string
+	}{
+		{"FREQ=DAILY", "2099-03-02", "2099-03-03"},
+		{"FREQ=DAILY;INTERVAL=3", "2099-03-02", "2099-03-05"},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", "2099-03-06", "2099-03-09"},
+		{"FREQ=WEEKLY", "2099-03-02", "2099-03-09"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", "2099-03-05", "2099-03-16"},
+		{"FREQ=MONTHLY", "2099-01-31", "2099-03-31"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "2099-01-31", "2099-02-28"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", "2099-04-01", "2099-04-15"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "2099-04-14", "2099-05-12"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2099-04-14", "2099-04-24"},
+		{"FREQ=YEARLY", "2096-02-29", "2104-02-29"},
+		{"FREQ=WEEKLY;UNTIL=20990305", "2099-03-02", ""},
+	}
+	for _, tt := range tests {
+		added := add(t, s, todo.Draft{Title: "chore", DueDate: tt.due, Recurrence: tt.rule})
+		done, err := s.SetCompleted(ctx, added.ID, true)
+		if err != nil {
+			t.Fatalf("%s: SetCompleted: %v", tt.rule, err)
+		}
+		var got string
+		if done.NextID != 0 {
+			got = get(t, s, done.NextID).DueDate
+		}
+		if got != tt.want {
+			t.Errorf("%s from %s: expected next due %q, got %q", tt.rule, tt.due, tt.want, got)
+		}
+	}
+}
+
+func createProject(t *testing.T, s todo.Storage, name string) todo.Project {
+	t.Helper()
+	p, err := s.CreateProject(context.Background(), name)
+	if err != nil {
+		t.Fatalf("CreateProject(%q): %v", name, err)
+	}
+	return p
+}
+
+func listProjects(t *testing.T, s todo.Storage) []todo.Project {
+	t.Helper()
+	projects, err := s.ListProjects(context.Background())
+	if err != nil {
+		t.Fatalf("ListProjects: %v", err)
+	}
+	return projects
+}
+
+func testProjects(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	if projects := listProjects(t, s); projects == nil || len(projects) != 0 {
+		t.Fatalf("expected an empty, non-nil list, got %#v", projects)
+	}
+	work := createProject(t, s, "Work")
+	home := createProject(t, s, "Home")
+	if work.ID != 1 || home.ID != 2 || work.Name != "Work" || work.Archived {
+		t.Fatalf("unexpected projects %+v, %+v", work, home)
+	}
+
+	expectErr(t, "create taken name", projectErrOf(s.CreateProject(ctx, "work")), todo.ErrProjectNameTaken)
+	expectErr(t, "create Inbox", projectErrOf(s.CreateProject(ctx, "INBOX")), todo.ErrProjectNameTaken)
+	expectErr(t, "create empty name", projectErrOf(s.CreateProject(ctx, "")), todo.ErrInvalidProjectName)
+	expectErr(t, "create padded name", projectErrOf(s.CreateProject(ctx, " Work ")), todo.ErrInvalidProjectName)
+	expectErr(t, "create long name", projectErrOf(s.CreateProject(ctx, strings.Repeat("x", todo.MaxProjectNameLength+1))), todo.ErrInvalidProjectName)
+
+	renamed, err := s.RenameProject(ctx, work.ID, "Office")
+	if err != nil {
+		t.Fatalf("RenameProject: %v", err)
+	}
+	if renamed != (todo.Project{ID: work.ID, Name: "Office"}) {
+		t.Fatalf("unexpected renamed project %+v", renamed)
+	}
+	if _, err := s.RenameProject(ctx, work.ID, "office"); err != nil {
+		t.Fatalf("RenameProject to a different case: %v", err)
+	}
+	expectErr(t, "rename to same name", projectErrOf(s.RenameProject(ctx, work.ID, "office")), todo.ErrProjectUnchanged)
+	expectErr(t, "rename to taken name", projectErrOf(s.RenameProject(ctx, work.ID, "HOME")), todo.ErrProjectNameTaken)
+	expectErr(t, "rename missing project", projectErrOf(s.RenameProject(ctx, 99, "Garden")), todo.ErrProjectNotFound)
+	expectErr(t, "rename Inbox", projectErrOf(s.RenameProject(ctx, todo.InboxID, "Garden")), todo.ErrInvalidID)
+	expectErr(t, "rename invalid ID", projectErrOf(s.RenameProject(ctx, -1, "Garden")), todo.ErrInvalidID)
+
+	archived, err := s.ArchiveProject(ctx, home.ID, true)
+	if err != nil {
+		t.Fatalf("ArchiveProject: %v", err)
+	}
+	if !archived.Archived || archived.Name != "Home" {
+		t.Fatalf("unexpected archived project %+v", archived)
+	}
+	expectErr(t, "archive archived project", projectErrOf(s.ArchiveProject(ctx, home.ID, true)), todo.ErrProjectUnchanged)
+	expectErr(t, "unarchive open project", projectErrOf(s.ArchiveProject(ctx, work.ID, false)), todo.ErrProjectUnchanged)
+	expectErr(t, "archive missing project", projectErrOf(s.ArchiveProject(ctx, 99, true)), todo.ErrProjectNotFound)
+	expectErr(t, "archive Inbox", projectErrOf(s.ArchiveProject(ctx, todo.InboxID, true)), todo.ErrInvalidID)
+	want := []todo.Project{{ID: 1, Name: "office"}, {ID: 2, Name: "Home", Archived: true}}
+	if got := listProjects(t, s); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got projects %+v, want %+v", got, want)
+	}
+	if p, err := s.ArchiveProject(ctx, home.ID, false); err != nil || p.Archived {
+		t.Fatalf("unarchive: got %+v, %v", p, err)
+	}
+
+	if err := s.DeleteProject(ctx, work.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	expectErr(t, "delete deleted project", s.DeleteProject(ctx, work.ID), todo.ErrProjectNotFound)
+	expectErr(t, "delete Inbox", s.DeleteProject(ctx, todo.InboxID), todo.ErrInvalidID)
+	if got := listProjects(t, s); len(got) != 1 || got[0].ID != home.ID {
+		t.Fatalf("expected only project %d left, got %+v", home.ID, got)
+	}
+	// IDs are not reused, nor is a deleted project's name held.
+	if p := createProject(t, s, "Office"); p.ID != 3 {
+		t.Fatalf("expected project 3, got %+v", p)
+	}
+}
+
+// projectErrOf drops the project returned by a Storage method, keeping its
+// error.
+func projectErrOf(_ todo.Project, err error) error {
+	return err
+}
+
+func testProjectTodos(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	work := createProject(t, s, "Work")
+	add(t, s, todo.Draft{Title: "inbox"})
+	if got := add(t, s, todo.Draft{Title: "report", ProjectID: work.ID}); got.ProjectID != work.ID {
+		t.Fatalf("expected Add to return project %d, got %d", work.ID, got.ProjectID)
+	}
+	add(t, s, todo.Draft{Title: "slides", ProjectID: work.ID, ParentID: 2})
+
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1)
+	expectIDs(t, list(t, s, todo.ListOptions{ProjectID: work.ID}), 2, 3)
+	expectIDs(t, list(t, s, todo.ListOptions{ProjectID: 99}))
+
+	expectErr(t, "add to missing project", errOf(s.Add(ctx, todo.Draft{Title: "t", ProjectID: 99})), todo.ErrProjectNotFound)
+	expectErr(t, "add to invalid project", errOf(s.Add(ctx, todo.Draft{Title: "t", ProjectID: -1})), todo.ErrInvalidID)
+	expectErr(t, "add under parent in another project", errOf(s.Add(ctx, todo.Draft{Title: "t", ParentID: 2})), todo.ErrParentNotFound)
+
+	if _, err := s.ArchiveProject(ctx, work.ID, true); err != nil {
+		t.Fatalf("ArchiveProject: %v", err)
+	}
+	expectErr(t, "add to archived project", errOf(s.Add(ctx, todo.Draft{Title: "t", ProjectID: work.ID})), todo.ErrProjectArchived)
+	// The todos of an archived project can still be changed.
+	if _, err := s.EditTitle(ctx, 2, "quarterly report"); err != nil {
+		t.Fatalf("EditTitle in archived project: %v", err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{ProjectID: work.ID}), 2, 3)
+
+	expectErr(t, "delete project with todos", s.DeleteProject(ctx, work.ID), todo.ErrProjectNotEmpty)
+	if _, err := s.DeleteTree(ctx, 2); err != nil {
+		t.Fatalf("DeleteTree: %v", err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{ProjectID: work.ID, Trashed: true}), 2, 3)
+	expectErr(t, "delete project with trashed todos", s.DeleteProject(ctx, work.ID), todo.ErrProjectNotEmpty)
+	if _, err := s.PurgeTrash(ctx); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if err := s.DeleteProject(ctx, work.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+}
+
+// moveTo moves the todo with the given ID to projectID, at the top level.
+func moveTo(s todo.Storage, id, projectID int) (todo.Todo, error) {
+	return s.Update(context.Background(), todo.Todo{ID: id, ProjectID: projectID}, []string{todo.FieldProjectID, todo.FieldParentID})
+}
+
+func testProjectMoves(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	work := createProject(t, s, "Work")
+	home := createProject(t, s, "Home")
+	add(t, s, todo.Draft{Title: "parent"})
+	add(t, s, todo.Draft{Title: "child", ParentID: 1})
+	add(t, s, todo.Draft{Title: "other", ProjectID: work.ID})
+
+	moved, err := moveTo(s, 2, work.ID)
+	if err != nil {
+		t.Fatalf("move subtask to project: %v", err)
+	}
+	if moved.ProjectID != work.ID || moved.ParentID != 0 || moved.Version != 2 {
+		t.Fatalf("unexpected moved todo %+v", moved)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{ProjectID: work.ID}), 2, 3)
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1)
+	if err := move(s, 2, 3); err != nil {
+		t.Fatalf("move under a todo in the same project: %v", err)
+	}
+
+	expectErr(t, "move with subtasks", errOf(moveTo(s, 3, todo.InboxID)), todo.ErrHasSubtasks)
+	expectErr(t, "move under todo in another project", move(s, 1, 3), todo.ErrParentNotFound)
+	expectErr(t, "move to missing project", errOf(moveTo(s, 1, 99)), todo.ErrProjectNotFound)
+	expectErr(t, "move missing todo", errOf(moveTo(s, 99, work.ID)), todo.ErrNotFound)
+	expectErr(t, "move away from parent", errOf(s.Update(ctx, todo.Todo{ID: 2, ProjectID: home.ID}, []string{todo.FieldProjectID})), todo.ErrParentNotFound)
+	expectErr(t, "move to same project", errOf(moveTo(s, 1, todo.InboxID)), todo.ErrTodoUnchanged)
+
+	if _, err := s.ArchiveProject(ctx, home.ID, true); err != nil {
+		t.Fatalf("ArchiveProject: %v", err)
+	}
+	expectErr(t, "move to archived project", errOf(moveTo(s, 1, home.ID)), todo.ErrProjectArchived)
+	// Todos can still be moved out of an archived project.
+	if _, err := s.ArchiveProject(ctx, work.ID, true); err != nil {
+		t.Fatalf("ArchiveProject: %v", err)
+	}
+	if err := move(s, 2, 0); err != nil {
+		t.Fatalf("move to the top: %v", err)
+	}
+	if _, err := moveTo(s, 2, todo.InboxID); err != nil {
+		t.Fatalf("move out of archived project: %v", err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{}), 1, 2)
+}
+
+func testOwners(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	alice := todo.WithOwner(ctx, "alice")
+	bob := todo.WithOwner(ctx, "bob")
+	add(t, s, todo.Draft{Title: "shared", Tags: []string{"old"}})
+	mine, err := s.Add(alice, todo.Draft{Title: "alice's", Tags: []string{"old"}})
+	if err != nil {
+		t.Fatalf("Add as alice: %v", err)
+	}
+	if mine.Owner != "alice" {
+		t.Fatalf("expected Add to return owner alice, got %q", mine.Owner)
+	}
+	if _, err := s.Add(bob, todo.Draft{Title: "bob's", Tags: []string{"old"}}); err != nil {
+		t.Fatalf("Add as bob: %v", err)
+	}
+
+	for _, tt := range []struct {
+		ctx  context.Context
+		want int
+	}{{ctx, 1}, {alice, 2}, {bob, 3}} {
+		todos, _, err := s.List(tt.ctx, todo.ListOptions{})
+		if err != nil {
+			t.Fatalf("List as %q: %v", todo.Owner(tt.ctx), err)
+		}
+		expectIDs(t, todos, tt.want)
+	}
+	for name, fn := range mutations(s) {
+		expectErr(t, name+" of another owner's todo", fn(alice, 3), todo.ErrNotFound)
+		expectErr(t, name+" of a shared todo", fn(alice, 1), todo.ErrNotFound)
+	}
+	expectErr(t, "add under another owner's todo", errOf(s.Add(alice, todo.Draft{Title: "t", ParentID: 3})), todo.ErrParentNotFound)
+	expectErr(t, "block on another owner's todo", errOf(s.AddDependency(alice, 2, 3)), todo.ErrBlockerNotFound)
+
+	if err := s.Delete(bob, 3); err != nil {
+		t.Fatalf("Delete as bob: %v", err)
+	}
+	expectErr(t, "restore another owner's todo", errOf(s.Restore(alice, 3)), todo.ErrNotFound)
+	if err := s.Delete(alice, 2); err != nil {
+		t.Fatalf("Delete as alice: %v", err)
+	}
+	if n, err := s.PurgeTrash(alice); err != nil || n != 1 {
+		t.Fatalf("PurgeTrash as alice: got %d, %v; want 1", n, err)
+	}
+	trashed, _, err := s.List(bob, todo.ListOptions{Trashed: true})
+	if err != nil {
+		t.Fatalf("List trash as bob: %v", err)
+	}
+	expectIDs(t, trashed, 3)
+
+	// Project names are unique per owner, but IDs are shared.
+	work, err := s.CreateProject(alice, "Work")
+	if err != nil {
+		t.Fatalf("CreateProject as alice: %v", err)
+	}
+	bobWork, err := s.CreateProject(bob, "work")
+	if err != nil {
+		t.Fatalf("CreateProject as bob with alice's project name: %v", err)
+	}
+	if work.Owner != "alice" || bobWork.ID == work.ID {
+		t.Fatalf("unexpected projects %+v, %+v", work, bobWork)
+	}
+	if projects := listProjects(t, s); len(projects) != 0 {
+		t.Fatalf("expected no shared projects, got %+v", projects)
+	}
+	if projects, err := s.ListProjects(bob); err != nil || len(projects) != 1 || projects[0].ID != bobWork.ID {
+		t.Fatalf("ListProjects as bob: got %+v, %v", projects, err)
+	}
+	expectErr(t, "add to another owner's project", errOf(s.Add(alice, todo.Draft{Title: "t", ProjectID: bobWork.ID})), todo.ErrProjectNotFound)
+	expectErr(t, "rename another owner's project", projectErrOf(s.RenameProject(alice, bobWork.ID, "Office")), todo.ErrProjectNotFound)
+	expectErr(t, "archive another owner's project", projectErrOf(s.ArchiveProject(alice, bobWork.ID, true)), todo.ErrProjectNotFound)
+	expectErr(t, "delete another owner's project", s.DeleteProject(alice, bobWork.ID), todo.ErrProjectNotFound)
+}
+
+func testSetCompleted(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task"})
+
+	expectErr(t, "uncomplete new todo", errOf(s.SetCompleted(ctx, 1, false)), todo.ErrAlreadyIncomplete)
+	if _, err := s.SetCompleted(ctx, 1, true); err != nil {
+		t.Fatalf("SetCompleted(true): %v", err)
+	}
+	if !get(t, s, 1).Completed {
+		t.Fatal("expected todo to be completed")
+	}
+	expectErr(t, "complete twice", errOf(s.SetCompleted(ctx, 1, true)), todo.ErrAlreadyCompleted)
+	if _, err := s.SetCompleted(ctx, 1, false); err != nil {
+		t.Fatalf("SetCompleted(false): %v", err)
+	}
+	if get(t, s, 1).Completed {
+		t.Fatal("expected todo to be incomplete")
+	}
+}
+
+func testEdits(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task", Description: "details", DueDate: "2026-03-01", DueTime: "08:00", Priority: todo.PriorityLow})
+	add(t, s, todo.Draft{Title: "bystander"})
+
+	steps := []struct {
+		name string
+		err  error
+	}{
+		{"EditTitle", errOf(s.EditTitle(ctx, 1, "renamed"))},
+		{"EditDescription", errOf(s.EditDescription(ctx, 1, ""))},
+		{"EditDue", errOf(s.EditDue(ctx, 1, "2026-03-05", ""))},
+		{"EditPriority", errOf(s.EditPriority(ctx, 1, todo.PriorityNone))},
+	}
+	for _, step := range steps {
+		if step.err != nil {
+			t.Fatalf("%s: %v", step.name, step.err)
+		}
+	}
+	got := get(t, s, 1)
+	if got.Title != "renamed" || got.Description != "" || got.DueDate != "2026-03-05" ||
+		got.DueTime != "" || got.Priority != todo.PriorityNone {
+		t.Fatalf("edits not applied: %+v", got)
+	}
+	if other := get(t, s, 2); other.Title != "bystander" {
+		t.Fatalf("edits leaked into another todo: %+v", other)
+	}
+
+	if _, err := s.EditDue(ctx, 1, "", ""); err != nil {
+		t.Fatalf("EditDue clear: %v", err)
+	}
+	if got := get(t, s, 1); got.DueDate != "" || got.DueTime != "" {
+		t.Fatalf("expected due date cleared, got %+v", got)
+	}
+
+	invalid := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"empty title", errOf(s.EditTitle(ctx, 1, "")), todo.ErrEmptyTitle},
+		{"long title", errOf(s.EditTitle(ctx, 1, strings.Repeat("x", todo.MaxTitleLength+1))), todo.ErrTitleTooLong},
+		{"long description", errOf(s.EditDescription(ctx, 1, strings.Repeat("x", todo.MaxDescriptionLength+1))), todo.ErrDescriptionTooLong},
+		{"bad due date", errOf(s.EditDue(ctx, 1, "tomorrow", "")), todo.ErrInvalidDueDate},
+		{"bad due time", errOf(s.EditDue(ctx, 1, "2026-03-05", "9am")), todo.ErrInvalidDueTime},
+		{"time without date", errOf(s.EditDue(ctx, 1, "", "09:00")), todo.ErrDueTimeWithoutDate},
+		{"bad priority", errOf(s.EditPriority(ctx, 1, -1)), todo.ErrInvalidPriority},
+	}
+	for _, tt := range invalid {
+		expectErr(t, tt.name, tt.err, tt.want)
+	}
+}
+
+// testReturnedTodos checks that Add and every edit return the todo exactly
+// as a later List reports it.
+func testReturnedTodos(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	expectStored := func(what string, got todo.Todo, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", what, err)
+		}
+		if want := get(t, s, got.ID); fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", want) {
+			t.Fatalf("%s returned %+v, but %+v is stored", what, got, want)
+		}
+	}
+
+	add(t, s, todo.Draft{Title: "first"})
+	added := add(t, s, todo.Draft{Title: "deploy", Description: "prod", DueDate: "2026-03-05", DueTime: "09:30", Priority: todo.PriorityHigh, Tags: []string{"Work", "ops"}})
+	if added.ID != 2 {
+		t.Fatalf("expected Add to return ID 2, got %d", added.ID)
+	}
+	expectStored("Add", added, nil)
+
+	got, err := s.SetCompleted(ctx, 2, true)
+	if !got.Completed {
+		t.Fatalf("expected SetCompleted to return a completed todo, got %+v", got)
+	}
+	expectStored("SetCompleted", got, err)
+	got, err = s.EditTitle(ctx, 2, "renamed")
+	if got.Title != "renamed" || got.Description != "prod" {
+		t.Fatalf("expected EditTitle to return the whole renamed todo, got %+v", got)
+	}
+	expectStored("EditTitle", got, err)
+	got, err = s.EditDescription(ctx, 2, "")
+	expectStored("EditDescription", got, err)
+	got, err = s.EditDue(ctx, 2, "", "")
+	expectStored("EditDue", got, err)
+	got, err = s.EditPriority(ctx, 2, todo.PriorityNone)
+	expectStored("EditPriority", got, err)
+	got, err = s.AddTag(ctx, 2, "urgent")
+	if fmt.Sprint(got.Tags) != "[work ops urgent]" {
+		t.Fatalf("expected AddTag to return tags [work ops urgent], got %v", got.Tags)
+	}
+	expectStored("AddTag", got, err)
+	got, err = s.RemoveTag(ctx, 2, "work")
+	expectStored("RemoveTag", got, err)
+	got, err = s.RemoveTag(ctx, 2, "ops")
+	expectStored("RemoveTag", got, err)
+	got, err = s.RemoveTag(ctx, 2, "urgent")
+	if len(got.Tags) != 0 {
+		t.Fatalf("expected no tags left, got %v", got.Tags)
+	}
+	expectStored("RemoveTag", got, err)
+
+	if got, err := s.EditTitle(ctx, 2, "renamed"); err == nil || got.ID != 0 {
+		t.Fatalf("expected a zero todo with the unchanged error, got %+v, %v", got, err)
+	}
+}
+
+func testUnchanged(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "same", Description: "same desc", DueDate: "2026-03-05", DueTime: "09:00", Priority: todo.PriorityMedium})
+	add(t, s, todo.Draft{Title: "bare"})
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"title", errOf(s.EditTitle(ctx, 1, "same")), todo.ErrTitleUnchanged},
+		{"description", errOf(s.EditDescription(ctx, 1, "same desc")), todo.ErrDescriptionUnchanged},
+		{"due", errOf(s.EditDue(ctx, 1, "2026-03-05", "09:00")), todo.ErrDueUnchanged},
+		{"priority", errOf(s.EditPriority(ctx, 1, todo.PriorityMedium)), todo.ErrPriorityUnchanged},
+		{"empty description", errOf(s.EditDescription(ctx, 2, "")), todo.ErrDescriptionUnchanged},
+		{"clear unset due", errOf(s.EditDue(ctx, 2, "", "")), todo.ErrDueUnchanged},
+		{"clear unset priority", errOf(s.EditPriority(ctx, 2, todo.PriorityNone)), todo.ErrPriorityUnchanged},
+	}
+	for _, tt := range tests {
+		expectErr(t, tt.name, tt.err, tt.want)
+	}
+}
+
+func testUpdate(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task", Description: "details", Priority: todo.PriorityLow, Tags: []string{"home"}})
+	add(t, s, todo.Draft{Title: "bystander"})
+
+	all := []string{todo.FieldTitle, todo.FieldDescription, todo.FieldCompleted, todo.FieldDueDate, todo.FieldDueTime, todo.FieldPriority, todo.FieldTags}
+	patch := todo.Todo{ID: 1, Title: "renamed", Description: "more", Completed: true, DueDate: "2026-03-05", DueTime: "09:30",
+		Priority: todo.PriorityHigh, Tags: []string{"Work", "ops", "work"}}
+	got, err := s.Update(ctx, patch, all)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	want := todo.Todo{ID: 1, Title: "renamed", Description: "more", Completed: true, DueDate: "2026-03-05", DueTime: "09:30",
+		Priority: todo.PriorityHigh, Tags: []string{"work", "ops"}, Version: 2}
+	if fmt.Sprintf("%+v", Timeless(got)) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("Update returned %+v, want %+v", got, want)
+	}
+	if stored := get(t, s, 1); fmt.Sprintf("%+v", Timeless(stored)) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("stored %+v, want %+v", stored, want)
+	}
+	if other := get(t, s, 2); other.Title != "bystander" || other.Completed {
+		t.Fatalf("update leaked into another todo: %+v", other)
+	}
+
+	// Fields left out of the mask keep their values.
+	got, err = s.Update(ctx, todo.Todo{ID: 1, Title: "only the title"}, []string{todo.FieldTitle})
+	if err != nil {
+		t.Fatalf("Update title: %v", err)
+	}
+	if got.Title != "only the title" || got.Description != "more" || !got.Completed || len(got.Tags) != 2 {
+		t.Fatalf("expected only the title to change, got %+v", got)
+	}
+
+	// Clearing optional fields, then clearing them again, which is a no-op.
+	optional := []string{todo.FieldDescription, todo.FieldDueDate, todo.FieldDueTime, todo.FieldPriority, todo.FieldTags}
+	got, err = s.Update(ctx, todo.Todo{ID: 1}, optional)
+	if err != nil {
+		t.Fatalf("Update clear: %v", err)
+	}
+	if got.Description != "" || got.DueDate != "" || got.DueTime != "" || got.Priority != todo.PriorityNone || len(got.Tags) != 0 {
+		t.Fatalf("expected optional fields cleared, got %+v", got)
+	}
+	expectErr(t, "clear twice", errOf(s.Update(ctx, todo.Todo{ID: 1}, optional)), todo.ErrTodoUnchanged)
+	expectErr(t, "same title", errOf(s.Update(ctx, todo.Todo{ID: 1, Title: "only the title", Completed: true},
+		[]string{todo.FieldTitle, todo.FieldCompleted})), todo.ErrTodoUnchanged)
+
+	// A todo whose last tag was removed has no tags either.
+	if _, err := s.AddTag(ctx, 2, "gone"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	if _, err := s.RemoveTag(ctx, 2, "gone"); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	expectErr(t, "clear removed tags", errOf(s.Update(ctx, todo.Todo{ID: 2}, []string{todo.FieldTags})), todo.ErrTodoUnchanged)
+}
+
+// testUpdateValidation checks that a rejected update changes nothing, even
+// when only one of the fields it names is invalid.
+func testUpdateValidation(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task", Description: "details"})
+
+	manyTags := make([]string, todo.MaxTags+1)
+	for i := range manyTags {
+		manyTags[i] = fmt.Sprintf("tag%d", i)
+	}
+	titleAnd := func(field string) []string { return []string{todo.FieldTitle, field} }
+	tests := []struct {
+		name  string
+		patch todo.Todo
+		mask  []string
+		want  error
+	}{
+		{"no fields", todo.Todo{ID: 1, Title: "new"}, nil, todo.ErrInvalidUpdateMask},
+		{"id field", todo.Todo{ID: 1, Title: "new"}, titleAnd("id"), todo.ErrInvalidUpdateMask},
+		{"unknown field", todo.Todo{ID: 1, Title: "new"}, titleAnd("colour"), todo.ErrInvalidUpdateMask},
+		{"field twice", todo.Todo{ID: 1, Title: "new"}, titleAnd(todo.FieldTitle), todo.ErrInvalidUpdateMask},
+		{"due date alone", todo.Todo{ID: 1, Title: "new", DueDate: "2026-03-05"}, titleAnd(todo.FieldDueDate), todo.ErrInvalidUpdateMask},
+		{"empty title", todo.Todo{ID: 1}, []string{todo.FieldTitle, todo.FieldDescription}, todo.ErrEmptyTitle},
+		{"long description", todo.Todo{ID: 1, Title: "new", Description: strings.Repeat("x", todo.MaxDescriptionLength+1)}, titleAnd(todo.FieldDescription), todo.ErrDescriptionTooLong},
+		{"time without date", todo.Todo{ID: 1, Title: "new", DueTime: "09:00"}, []string{todo.FieldTitle, todo.FieldDueDate, todo.FieldDueTime}, todo.ErrDueTimeWithoutDate},
+		{"bad priority", todo.Todo{ID: 1, Title: "new", Priority: -1}, titleAnd(todo.FieldPriority), todo.ErrInvalidPriority},
+		{"bad tag", todo.Todo{ID: 1, Title: "new", Tags: []string{"two words"}}, titleAnd(todo.FieldTags), todo.ErrInvalidTag},
+		{"too many tags", todo.Todo{ID: 1, Title: "new", Tags: manyTags}, titleAnd(todo.FieldTags), todo.ErrTooManyTags},
+		{"bad recurrence", todo.Todo{ID: 1, Title: "new", Recurrence: "FREQ=WEEKLY;BYMONTHDAY=1"}, titleAnd(todo.FieldRecurrence), todo.ErrInvalidRecurrence},
+	}
+	for _, tt := range tests {
+		expectErr(t, tt.name, errOf(s.Update(ctx, tt.patch, tt.mask)), tt.want)
+	}
+	if got := get(t, s, 1); got.Title != "task" || got.Description != "details" {
+		t.Fatalf("rejected updates changed the todo: %+v", got)
+	}
+}
+
+func testVersions(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	if added := add(t, s, todo.Draft{Title: "task", Tags: []string{"old"}}); added.Version != 1 {
+		t.Fatalf("expected Add to return version 1, got %d", added.Version)
+	}
+
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) (todo.Todo, error)
+	}{
+		{"SetCompleted", func(ctx context.Context) (todo.Todo, error) { return s.SetCompleted(ctx, 1, true) }},
+		{"EditTitle", func(ctx context.Context) (todo.Todo, error) { return s.EditTitle(ctx, 1, "renamed") }},
+		{"EditDescription", func(ctx context.Context) (todo.Todo, error) { return s.EditDescription(ctx, 1, "more") }},
+		{"EditDue", func(ctx context.Context) (todo.Todo, error) { return s.EditDue(ctx, 1, "2026-03-05", "") }},
+		{"EditPriority", func(ctx context.Context) (todo.Todo, error) { return s.EditPriority(ctx, 1, todo.PriorityHigh) }},
+		{"AddTag", func(ctx context.Context) (todo.Todo, error) { return s.AddTag(ctx, 1, "new") }},
+		{"RemoveTag", func(ctx context.Context) (todo.Todo, error) { return s.RemoveTag(ctx, 1, "old") }},
+		{"Update", func(ctx context.Context) (todo.Todo, error) {
+			return s.Update(ctx, todo.Todo{ID: 1, Title: "updated"}, []string{todo.FieldTitle})
+		}},
+	}
+	for i, step := range steps {
+		want := i + 2
+		// Each change is made at the version the previous one returned.
+		got, err := step.fn(todo.WithExpectedVersion(ctx, want-1))
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got.Version != want {
+			t.Fatalf("%s: expected version %d, got %d", step.name, want, got.Version)
+		}
+		if stored := get(t, s, 1); stored.Version != want {
+			t.Fatalf("%s: expected stored version %d, got %d", step.name, want, stored.Version)
+		}
+	}
+
+	// Failed changes leave the version alone.
+	expectErr(t, "unchanged title", errOf(s.EditTitle(ctx, 1, "updated")), todo.ErrTitleUnchanged)
+	expectErr(t, "empty title", errOf(s.EditTitle(ctx, 1, "")), todo.ErrEmptyTitle)
+	if stored := get(t, s, 1); stored.Version != len(steps)+1 {
+		t.Fatalf("expected failed changes to keep version %d, got %d", len(steps)+1, stored.Version)
+	}
+}
+
+// testTimestamps checks that Add stamps CreatedAt and UpdatedAt, that
+// every change moves UpdatedAt on, that a failed change does not, and that
+// CompletedAt follows the completion state whichever method changes it.
+func testTimestamps(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	// Backends store milliseconds, so the window opens at the last one.
+	start := time.Now().Truncate(time.Millisecond)
+	added := add(t, s, todo.Draft{Title: "task"})
+	if added.CreatedAt.Before(start) || added.CreatedAt.After(time.Now()) {
+		t.Fatalf("CreatedAt %v is outside the Add call", added.CreatedAt)
+	}
+	if !added.UpdatedAt.Equal(added.CreatedAt) || !added.CompletedAt.IsZero() {
+		t.Fatalf("expected UpdatedAt to equal CreatedAt and no CompletedAt, got %+v", added)
+	}
+
+	last := added
+	// expectChanged checks the todo returned by a change, which the
+	// caller's line number identifies.
+	expectChanged := func(got todo.Todo, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("change failed: %v", err)
+		}
+		if !got.CreatedAt.Equal(added.CreatedAt) {
+			t.Errorf("CreatedAt moved from %v to %v", added.CreatedAt, got.CreatedAt)
+		}
+		if got.UpdatedAt.Before(last.UpdatedAt) {
+			t.Errorf("UpdatedAt went back from %v to %v", last.UpdatedAt, got.UpdatedAt)
+		}
+		last = got
+	}
+
+	expectChanged(s.EditTitle(ctx, 1, "renamed"))
+	expectChanged(s.AddTag(ctx, 1, "work"))
+	expectErr(t, "unchanged title", errOf(s.EditTitle(ctx, 1, "renamed")), todo.ErrTitleUnchanged)
+	if got := get(t, s, 1); !got.UpdatedAt.Equal(last.UpdatedAt) {
+		t.Errorf("a failed edit moved UpdatedAt from %v to %v", last.UpdatedAt, got.UpdatedAt)
+	}
+
+	expectChanged(s.SetCompleted(ctx, 1, true))
+	completedAt := last.CompletedAt
+	if completedAt.IsZero() || completedAt.Before(added.CreatedAt) {
+		t.Fatalf("expected CompletedAt to be stamped, got %v", completedAt)
+	}
+	// Completing an already completed todo in an update keeps the time it
+	// was completed.
+	expectChanged(s.Update(ctx, todo.Todo{ID: 1, Title: "again", Completed: true},
+		[]string{todo.FieldTitle, todo.FieldCompleted}))
+	if !last.CompletedAt.Equal(completedAt) {
+		t.Errorf("expected CompletedAt to stay %v, got %v", completedAt, last.CompletedAt)
+	}
+	expectChanged(s.Update(ctx, todo.Todo{ID: 1}, []string{todo.FieldCompleted}))
+	if !last.CompletedAt.IsZero() {
+		t.Errorf("expected reopening to clear CompletedAt, got %v", last.CompletedAt)
+	}
+	expectChanged(s.Update(ctx, todo.Todo{ID: 1, Completed: true}, []string{todo.FieldCompleted}))
+	if last.CompletedAt.Before(completedAt) {
+		t.Errorf("expected a new CompletedAt, got %v", last.CompletedAt)
+	}
+	expectChanged(s.SetCompleted(ctx, 1, false))
+	if !last.CompletedAt.IsZero() {
+		t.Errorf("expected marking incomplete to clear CompletedAt, got %v", last.CompletedAt)
+	}
+
+	if err := s.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	trashed := list(t, s, todo.ListOptions{Trashed: true})[0]
+	expectChanged(trashed, nil)
+	if !trashed.UpdatedAt.Equal(trashed.DeletedAt) {
+		t.Errorf("expected Delete to stamp UpdatedAt %v with DeletedAt %v", trashed.UpdatedAt, trashed.DeletedAt)
+	}
+	expectChanged(s.Restore(ctx, 1))
+	if got := get(t, s, 1); fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", last) {
+		t.Fatalf("Restore returned %+v, but %+v is stored", last, got)
+	}
+}
+
+// testVersionConflicts checks that every change made at a stale version
+// fails, changes nothing, and takes precedence over "unchanged" errors.
+func testVersionConflicts(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task", Tags: []string{"old"}})
+	if _, err := s.EditTitle(ctx, 1, "renamed"); err != nil {
+		t.Fatalf("EditTitle: %v", err)
+	}
+
+	stale := todo.WithExpectedVersion(ctx, 1)
+	for name, fn := range mutations(s) {
+		expectErr(t, name, fn(stale, 1), todo.ErrVersionConflict)
+	}
+	expectErr(t, "unchanged title", errOf(s.EditTitle(stale, 1, "renamed")), todo.ErrVersionConflict)
+	expectErr(t, "missing todo", errOf(s.EditTitle(stale, 2, "renamed")), todo.ErrNotFound)
+	if got := get(t, s, 1); got.Title != "renamed" || got.Version != 2 || fmt.Sprint(got.Tags) != "[old]" {
+		t.Fatalf("conflicting changes were applied: %+v", got)
+	}
+
+	current := todo.WithExpectedVersion(ctx, 2)
+	if err := s.Delete(current, 1); err != nil {
+		t.Fatalf("Delete at the current version: %v", err)
+	}
+}
+
+// testConcurrentConflicts races changes made at the same version: exactly
+// one may win.
+func testConcurrentConflicts(t *testing.T, s todo.Storage) {
+	ctx := todo.WithExpectedVersion(context.Background(), 1)
+	add(t, s, todo.Draft{Title: "task"})
+
+	const n = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.EditTitle(ctx, 1, fmt.Sprintf("title %d", i))
+			switch {
+			case err == nil:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case !errors.Is(err, todo.ErrVersionConflict):
+				t.Errorf("EditTitle: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one change to win, got %d", succeeded)
+	}
+	if got := get(t, s, 1); got.Version != 2 {
+		t.Fatalf("expected version 2, got %d", got.Version)
+	}
+}
+
+func testTags(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "deploy", Tags: []string{"work"}})
+	add(t, s, todo.Draft{Title: "groceries", Tags: []string{"home"}})
+
+	if _, err := s.AddTag(ctx, 1, " Urgent "); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	expectErr(t, "AddTag duplicate", errOf(s.AddTag(ctx, 1, "URGENT")), todo.ErrTagAlreadyPresent)
+	expectErr(t, "AddTag invalid", errOf(s.AddTag(ctx, 1, "")), todo.ErrInvalidTag)
+	expectErr(t, "RemoveTag invalid", errOf(s.RemoveTag(ctx, 1, "a b")), todo.ErrInvalidTag)
+	if got := get(t, s, 1).Tags; fmt.Sprint(got) != "[work urgent]" {
+		t.Fatalf("expected tags [work urgent] in insertion order, got %v", got)
+	}
+
+	expectIDs(t, list(t, s, todo.ListOptions{Tags: []string{"Work", "urgent"}}), 1)
+	expectIDs(t, list(t, s, todo.ListOptions{Tags: []string{"work", "home"}}))
+
+	if _, err := s.RemoveTag(ctx, 2, "HOME"); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	expectErr(t, "RemoveTag absent", errOf(s.RemoveTag(ctx, 2, "home")), todo.ErrTagNotPresent)
+	if got := get(t, s, 2).Tags; len(got) != 0 {
+		t.Fatalf("expected no tags left, got %v", got)
+	}
+}
+
+func testTagLimit(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "task"})
+	for i := range todo.MaxTags {
+		if _, err := s.AddTag(ctx, 1, fmt.Sprintf("t%d", i)); err != nil {
+			t.Fatalf("AddTag %d: %v", i, err)
+		}
+	}
+	expectErr(t, "AddTag past the limit", errOf(s.AddTag(ctx, 1, "one-more")), todo.ErrTooManyTags)
+	expectErr(t, "AddTag present at the limit", errOf(s.AddTag(ctx, 1, "t0")), todo.ErrTooManyTags)
+	if got := get(t, s, 1).Tags; len(got) != todo.MaxTags {
+		t.Fatalf("expected %d tags, got %d", todo.MaxTags, len(got))
+	}
+}
+
+func testFilter(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	add(t, s, todo.Draft{Title: "Deploy API", Priority: todo.PriorityHigh, DueDate: "2026-03-05", DueTime: "09:00", Tags: []string{"work"}})
+	add(t, s, todo.Draft{Title: "deploy docs", Description: "Handbook"})
+	add(t, s, todo.Draft{Title: "groceries", DueDate: "2026-04-01", Tags: []string{"home"}})
+	if _, err := s.SetCompleted(ctx, 2, true); err != nil {
+		t.Fatalf("SetCompleted: %v", err)
+	}
+
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{``, []int{1, 2, 3}},
+		{`completed=false AND title~"deploy"`, []int{1}},
+		{`title~"DEPLOY"`, []int{1, 2}},
+		{`title="deploy docs"`, []int{2}},
+		{`description~book`, []int{2}},
+		{`id>1`, []int{2, 3}},
+		{`priority=none`, []int{2, 3}},
+		{`priority>=high`, []int{1}},
+		{`due_date=""`, []int{2}},
+		{`due_date!=""`, []int{1, 3}},
+		{`due_date<2026-04-01`, []int{1}},
+		{`due_time>=09:00`, []int{1}},
+		{`tag=work OR tag=home`, []int{1, 3}},
+		{`NOT tag=work`, []int{2, 3}},
+		{`(tag=home OR completed=true) AND NOT id=3`, []int{2}},
+		{`title~"a.i"`, []int{}},
+	}
+	for _, tt := range tests {
+		todos, _, err := s.List(ctx, todo.ListOptions{Filter: tt.filter})
+		if err != nil {
+			t.Errorf("List(%s): %v", tt.filter, err)
+			continue
+		}
+		if got := ids(todos); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("List(%s): got IDs %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	todos := list(t, s, todo.ListOptions{Filter: "completed=false", Tags: []string{"home"}})
+	expectIDs(t, todos, 3)
+}
+
+func testPagination(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	for i := 1; i <= 7; i++ {
+		add(t, s, todo.Draft{Title: fmt.Sprintf("task %d", i), Tags: []string{fmt.Sprintf("parity%d", i%2)}})
+	}
+	if err := s.Delete(ctx, 4); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	collect := func(opts todo.ListOptions) ([]int, int) {
+		var all []int
+		pages := 0
+		for {
+			todos, next, err := s.List(ctx, opts)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(todos) > opts.PageSize {
+				t.Fatalf("page of %d exceeds page size %d", len(todos), opts.PageSize)
+			}
+			pages++
+			all = append(all, ids(todos)...)
+			if next == "" {
+				return all, pages
+			}
+			opts.PageToken = next
+		}
+	}
+
+	got, pages := collect(todo.ListOptions{PageSize: 3})
+	if fmt.Sprint(got) != "[1 2 3 5 6 7]" || pages != 2 {
+		t.Fatalf("got IDs %v over %d pages, want [1 2 3 5 6 7] over 2", got, pages)
+	}
+	got, _ = collect(todo.ListOptions{PageSize: 2, Tags: []string{"parity1"}})
+	if fmt.Sprint(got) != "[1 3 5 7]" {
+		t.Fatalf("got IDs %v with a tag filter, want [1 3 5 7]", got)
+	}
+
+	// A token stays valid when the todo it points after is deleted.
+	first, next, err := s.List(ctx, todo.ListOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	expectIDs(t, first, 1, 2)
+	if err := s.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectIDs(t, list(t, s, todo.ListOptions{PageSize: 2, PageToken: next}), 3, 5)
+}
+
+func testInvalidListOptions(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want error
+	}{
+		{"negative page size", todo.ListOptions{PageSize: -1}, todo.ErrInvalidPageSize},
+		{"page size too large", todo.ListOptions{PageSize: todo.MaxPageSize + 1}, todo.ErrInvalidPageSize},
+		{"garbage token", todo.ListOptions{PageToken: "bogus"}, todo.ErrInvalidPageToken},
+		{"unknown field", todo.ListOptions{Filter: "colour=red"}, todo.ErrInvalidFilter},
+		{"bad value", todo.ListOptions{Filter: "due_date=someday"}, todo.ErrInvalidFilter},
+		{"invalid project", todo.ListOptions{ProjectID: -1}, todo.ErrInvalidID},
+	}
+	for _, tt := range tests {
+		_, _, err := s.List(ctx, tt.opts)
+		expectErr(t, tt.name, err, tt.want)
+	}
+}
+
+// testConcurrentAdds fires hundreds of Adds at once: every todo must get
+// its own ID, and keep the one Add returned for it.
+func testConcurrentAdds(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	const n = 300
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		titleOf = make(map[int]string, n)
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			title := fmt.Sprintf("task %d", i)
+			added, err := s.Add(ctx, todo.Draft{Title: title})
+			if err != nil {
+				t.Errorf("Add: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if other, taken := titleOf[added.ID]; taken {
+				t.Errorf("ID %d returned for both %q and %q", added.ID, other, title)
+			}
+			titleOf[added.ID] = title
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	todos := list(t, s, todo.ListOptions{})
+	if len(todos) != n {
+		t.Fatalf("expected %d todos, got %d", n, len(todos))
+	}
+	for i, td := range todos {
+		if i > 0 && td.ID <= todos[i-1].ID {
+			t.Fatalf("IDs not unique and increasing: %v", ids(todos))
+		}
+		if td.Title != titleOf[td.ID] {
+			t.Errorf("todo %d: expected title %q as returned by Add, got %q", td.ID, titleOf[td.ID], td.Title)
+		}
+	}
+}
+
+func testCloseIdempotent(t *testing.T, s todo.Storage) {
+	ctx := context.Background()
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package todo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// TokenPrefix starts every API key token, so a leaked one is easy to spot.
+const TokenPrefix = "todo_"
+
+// MaxUserLength is the longest user name accepted.
+const MaxUserLength = 64
+
+var validUser = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]*$`)
+
+// APIKey lets whoever holds its token act as User. Only the token's hash is
+// stored: the token itself is shown once, when the key is created.
+type APIKey struct {
+	ID   int    `json:"id" bson:"_id"`
+	User string `json:"user" bson:"user"`
+	// Hash is HashToken of the key's token.
+	Hash      string    `json:"-" bson:"hash"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// KeyStore persists API keys. Keys belong to no owner: every method sees
+// them all, whatever the owner of its context.
+type KeyStore interface {
+	// AddKey stores a key for user whose token hashes to hash, and returns
+	// it with its allocated ID.
+	AddKey(ctx context.Context, user, hash string) (APIKey, error)
+	// ListKeys returns every key, ordered by ID.
+	ListKeys(ctx context.Context) ([]APIKey, error)
+	// KeyByHash returns the key whose token hashes to hash, or
+	// ErrKeyNotFound.
+	KeyByHash(ctx context.Context, hash string) (APIKey, error)
+	// RevokeKey removes the key with the given ID, or reports
+	// ErrKeyNotFound.
+	RevokeKey(ctx context.Context, id int) error
+}
+
+// ValidateUser reports ErrInvalidUser unless name is a user name: 1 to
+// MaxUserLength letters, digits, '.', '_', '@' or '-', starting with a
+// letter or digit.
+func ValidateUser(name string) error {
+	if len(name) > MaxUserLength || !validUser.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidUser, name)
+	}
+	return nil
+}
+
+// NewToken returns a fresh random API key token and its hash.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = TokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash an API key token is stored and looked up by.
+// Tokens are long and random, so a fast unsalted hash is enough.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateDependency checks the IDs of a todo and of a todo it is to be
+// blocked by.
+func ValidateDependency(id, blockerID int) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	if blockerID <= 0 {
+		return fmt.Errorf("blocker id %d: %w", blockerID, ErrInvalidID)
+	}
+	return nil
+}
+
+// CheckDependency reports whether the todo with ID id may become blocked by
+// the todo with ID blockerID. The blocker must be live, and must not be the
+// todo itself or wait on it, directly or through other todos, which would
+// make a cycle. get returns a stored todo, live or trashed, or ErrNotFound;
+// backends call CheckDependency where the todos cannot change under it.
+func CheckDependency(id, blockerID int, get func(id int) (Todo, error)) error {
+	if blockerID == id {
+		return fmt.Errorf("todo %d: %w: a todo cannot block itself", id, ErrDependencyCycle)
+	}
+	blocker, err := get(blockerID)
+	if errors.Is(err, ErrNotFound) || err == nil && blocker.Trashed() {
+		return fmt.Errorf("%w: todo %d", ErrBlockerNotFound, blockerID)
+	}
+	if err != nil {
+		return err
+	}
+	// Trashed todos keep their dependencies, so the walk goes through them
+	// too: restoring one must not bring a cycle back.
+	seen := map[int]bool{blockerID: true}
+	for pending := []Todo{blocker}; len(pending) > 0; {
+		t := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, next := range t.BlockedBy {
+			if next == id {
+				return fmt.Errorf("todo %d: %w: todo %d already waits on it", id, ErrDependencyCycle, blockerID)
+			}
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			n, err := get(next)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			pending = append(pending, n)
+		}
+	}
+	return nil
+}
+
+// CheckBlockers returns ErrBlocked if any of the todos t is blocked by is
+// still open, that is live and incomplete. get is as for CheckDependency.
+func CheckBlockers(t Todo, get func(id int) (Todo, error)) error {
+	var open []string
+	for _, id := range t.BlockedBy {
+		blocker, err := get(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !blocker.Completed && !blocker.Trashed() {
+			open = append(open, fmt.Sprintf("#%d", id))
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("todo %d: %w: %s", t.ID, ErrBlocked, strings.Join(open, ", "))
+	}
+	return nil
+}
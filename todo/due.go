@@ -0,0 +1,49 @@
+package todo
+
+import "time"
+
+// DueAt returns the moment the todo is due in loc. A todo with a due date but
+// no due time is due at the end of that day. The second result is false when
+// the todo has no (valid) due date.
+func (t Todo) DueAt(loc *time.Location) (time.Time, bool) {
+	if t.DueDate == "" {
+		return time.Time{}, false
+	}
+	if t.DueTime == "" {
+		day, err := time.ParseInLocation(DueDateLayout, t.DueDate, loc)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+	}
+	at, err := time.ParseInLocation(DueDateLayout+" "+DueTimeLayout, t.DueDate+" "+t.DueTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// IsOverdue reports whether an incomplete todo's due moment has passed.
+func (t Todo) IsOverdue(now time.Time) bool {
+	if t.Completed {
+		return false
+	}
+	due, ok := t.DueAt(now.Location())
+	return ok && due.Before(now)
+}
+
+// IsDueWithin reports whether an incomplete todo falls due on one of the
+// given number of calendar days starting with now's day, without already
+// being overdue.
+func (t Todo) IsDueWithin(now time.Time, days int) bool {
+	if t.Completed || t.IsOverdue(now) {
+		return false
+	}
+	due, ok := t.DueAt(now.Location())
+	if !ok {
+		return false
+	}
+	y, m, d := now.Date()
+	end := time.Date(y, m, d+days, 0, 0, 0, 0, now.Location())
+	return due.Before(end)
+}
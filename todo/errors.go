@@ -8,8 +8,43 @@ var (
 	ErrAlreadyIncomplete    = errors.New("already incomplete")
 	ErrTitleUnchanged       = errors.New("title unchanged")
 	ErrDescriptionUnchanged = errors.New("description unchanged")
+	ErrDueUnchanged         = errors.New("due date unchanged")
+	ErrPriorityUnchanged    = errors.New("priority unchanged")
+	ErrTodoUnchanged        = errors.New("todo unchanged")
+	ErrNotInTrash           = errors.New("todo not in trash")
+	ErrParentNotFound       = errors.New("parent not found")
+	ErrParentCycle          = errors.New("parent would create a cycle")
+	ErrHasSubtasks          = errors.New("todo has subtasks")
+	ErrBlocked              = errors.New("blocked by open todos")
+	ErrBlockerNotFound      = errors.New("blocker not found")
+	ErrDependencyCycle      = errors.New("dependency would create a cycle")
+	ErrDependencyPresent    = errors.New("dependency already present")
+	ErrDependencyNotPresent = errors.New("dependency not present")
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrProjectNameTaken     = errors.New("project name already taken")
+	ErrProjectArchived      = errors.New("project is archived")
+	ErrProjectNotEmpty      = errors.New("project has todos")
+	ErrProjectUnchanged     = errors.New("project unchanged")
+	ErrTagAlreadyPresent    = errors.New("tag already present")
+	ErrTagNotPresent        = errors.New("tag not present")
 	ErrInvalidID            = errors.New("invalid ID")
 	ErrEmptyTitle           = errors.New("title cannot be empty")
 	ErrTitleTooLong         = errors.New("title exceeds maximum length")
 	ErrDescriptionTooLong   = errors.New("description exceeds maximum length")
+	ErrInvalidDueDate       = errors.New("invalid due date")
+	ErrInvalidDueTime       = errors.New("invalid due time")
+	ErrDueTimeWithoutDate   = errors.New("due time requires a due date")
+	ErrInvalidPriority      = errors.New("invalid priority")
+	ErrInvalidTag           = errors.New("invalid tag")
+	ErrInvalidRecurrence    = errors.New("invalid recurrence rule")
+	ErrInvalidProjectName   = errors.New("invalid project name")
+	ErrTooManyTags          = errors.New("too many tags")
+	ErrInvalidFilter        = errors.New("invalid filter")
+	ErrInvalidPageSize      = errors.New("invalid page size")
+	ErrInvalidPageToken     = errors.New("invalid page token")
+	ErrInvalidUpdateMask    = errors.New("invalid update mask")
+	ErrVersionConflict      = errors.New("version conflict")
+	ErrUnauthenticated      = errors.New("unauthenticated")
+	ErrInvalidUser          = errors.New("invalid user name")
+	ErrKeyNotFound          = errors.New("API key not found")
 )
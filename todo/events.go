@@ -0,0 +1,39 @@
+package todo
+
+import "context"
+
+// EventType says how a todo changed.
+type EventType int
+
+const (
+	EventCreated EventType = iota + 1
+	EventUpdated
+	EventDeleted
+	EventRestored
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventCreated:
+		return "created"
+	case EventUpdated:
+		return "updated"
+	case EventDeleted:
+		return "deleted"
+	case EventRestored:
+		return "restored"
+	}
+	return "unknown"
+}
+
+// Event describes a change to the todo with the given ID.
+type Event struct {
+	Type EventType
+	ID   int
+}
+
+// Watcher is implemented by storages that can stream change notifications.
+// The returned channel is closed when ctx is done or the stream ends.
+type Watcher interface {
+	Watch(ctx context.Context) (<-chan Event, error)
+}
@@ -2,19 +2,127 @@ package todo
 
 import (
 	"fmt"
+	"time"
 	"unicode/utf8"
 )
 
 const (
 	MaxTitleLength       = 100
 	MaxDescriptionLength = 500
+
+	// DueDateLayout and DueTimeLayout are the wire and storage formats of
+	// Todo.DueDate and Todo.DueTime.
+	DueDateLayout = "2006-01-02"
+	DueTimeLayout = "15:04"
 )
 
 type Todo struct {
-	ID          int    `json:"id" bson:"_id"`
-	Title       string `json:"title" bson:"title"`
-	Description string `json:"description" bson:"description"`
-	Completed   bool   `json:"completed" bson:"completed"`
+	ID          int      `json:"id" bson:"_id"`
+	Title       string   `json:"title" bson:"title"`
+	Description string   `json:"description" bson:"description"`
+	Completed   bool     `json:"completed" bson:"completed"`
+	DueDate     string   `json:"due_date,omitempty" bson:"due_date,omitempty"`
+	DueTime     string   `json:"due_time,omitempty" bson:"due_time,omitempty"`
+	Priority    Priority `json:"priority,omitempty" bson:"priority,omitempty"`
+	Tags        []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	// ParentID is the ID of the todo this one is a subtask of, or 0 for a
+	// top-level todo.
+	ParentID int `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	// ProjectID is the ID of the project the todo belongs to, or InboxID.
+	// A subtask is always in its parent's project.
+	ProjectID int `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	// BlockedBy holds the IDs of the todos that must be completed before
+	// this one can be, in the order they were added.
+	BlockedBy []int `json:"blocked_by,omitempty" bson:"blocked_by,omitempty"`
+	// Recurrence is the todo's recurrence rule in canonical RRULE syntax
+	// (see Rule), or empty for a one-off todo.
+	Recurrence string `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	// NextID is the ID of the occurrence added when this recurring todo
+	// was completed, or 0 if it has not recurred. A todo recurs once, so
+	// reopening and completing it again adds nothing.
+	NextID int `json:"next_id,omitempty" bson:"next_id,omitempty"`
+	// Recurred reports that the change that returned the todo added its
+	// next occurrence. It is not stored: a todo read back has it false.
+	Recurred bool `json:"-" bson:"-"`
+	// Owner is the user the todo belongs to, or empty for the shared list.
+	// It is set from the context the todo is added with; see WithOwner.
+	Owner string `json:"owner,omitempty" bson:"owner,omitempty"`
+	// Version starts at 1 and grows by one with every change to the todo.
+	// See WithExpectedVersion.
+	Version int `json:"version" bson:"version"`
+	// DeletedAt is when the todo was moved to the trash, or zero while it
+	// is live.
+	DeletedAt time.Time `json:"deleted_at,omitzero" bson:"deleted_at,omitempty"`
+	// CreatedAt is when the todo was added and UpdatedAt when it last
+	// changed, trash moves included. Both are zero for todos stored before
+	// they were recorded.
+	CreatedAt time.Time `json:"created_at,omitzero" bson:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitzero" bson:"updated_at,omitempty"`
+	// CompletedAt is when the todo was last completed, or zero while it is
+	// incomplete.
+	CompletedAt time.Time `json:"completed_at,omitzero" bson:"completed_at,omitempty"`
+}
+
+// Trashed reports whether the todo is in the trash.
+func (t Todo) Trashed() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// Stamp returns t, the result of a change made at the given time to the
+// todo before, with UpdatedAt set to at and CompletedAt set if the change
+// completed the todo or cleared if it left it incomplete.
+func (t Todo) Stamp(before Todo, at time.Time) Todo {
+	t.UpdatedAt = at
+	switch {
+	case !t.Completed:
+		t.CompletedAt = time.Time{}
+	case !before.Completed:
+		t.CompletedAt = at
+	}
+	return t
+}
+
+// Draft holds the caller-supplied fields of a todo that has not been stored yet.
+type Draft struct {
+	Title       string
+	Description string
+	DueDate     string
+	DueTime     string
+	Priority    Priority
+	Tags        []string
+	// ParentID makes the todo a subtask of the live todo with that ID.
+	ParentID int
+	// ProjectID adds the todo to the live project with that ID rather than
+	// the Inbox. A subtask must be added to its parent's project.
+	ProjectID int
+	// Recurrence makes the todo recur by the given rule (see Rule).
+	Recurrence string
+}
+
+// Validate checks every field of the draft.
+func (d Draft) Validate() error {
+	if err := ValidateTitle(d.Title); err != nil {
+		return err
+	}
+	if err := ValidateDescription(d.Description); err != nil {
+		return err
+	}
+	if err := ValidateDue(d.DueDate, d.DueTime); err != nil {
+		return err
+	}
+	if err := ValidatePriority(d.Priority); err != nil {
+		return err
+	}
+	if err := ValidateParentID(d.ParentID); err != nil {
+		return err
+	}
+	if err := ValidateProjectRef(d.ProjectID); err != nil {
+		return err
+	}
+	if err := ValidateRecurrence(d.Recurrence); err != nil {
+		return err
+	}
+	return ValidateTags(NormalizeTags(d.Tags))
 }
 
 func ValidateID(id int) error {
@@ -43,3 +151,31 @@ func ValidateDescription(desc string) error {
 	}
 	return nil
 }
+
+// ValidateDue checks an optional due date (YYYY-MM-DD) and an optional due
+// time (HH:MM). A due time is only meaningful together with a due date.
+func ValidateDue(date, clock string) error {
+	if date == "" {
+		if clock != "" {
+			return ErrDueTimeWithoutDate
+		}
+		return nil
+	}
+	if _, err := time.Parse(DueDateLayout, date); err != nil {
+		return fmt.Errorf("%w: %q (want YYYY-MM-DD)", ErrInvalidDueDate, date)
+	}
+	if clock == "" {
+		return nil
+	}
+	if _, err := time.Parse(DueTimeLayout, clock); err != nil {
+		return fmt.Errorf("%w: %q (want HH:MM)", ErrInvalidDueTime, clock)
+	}
+	return nil
+}
+
+func ValidatePriority(p Priority) error {
+	if p < PriorityNone || p > PriorityUrgent {
+		return fmt.Errorf("%w: %d", ErrInvalidPriority, int(p))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package todo
+
+import "context"
+
+type ownerKey struct{}
+
+// WithOwner returns a copy of ctx that scopes Storage calls to the given
+// owner: todos and projects are added for that owner, and those of other
+// owners are not found. The empty owner is the shared list of callers that
+// give no identity.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// Owner returns the owner set by WithOwner, or "" if ctx sets none.
+func Owner(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
@@ -0,0 +1,72 @@
+package todo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxPageSize caps ListOptions.PageSize.
+const MaxPageSize = 1000
+
+const pageTokenPrefix = "after:"
+
+// EncodePageToken returns the opaque token for the page that starts after
+// the todo with the given ID. Pages are keyed on ID, so a token stays valid
+// while todos are added or removed between requests.
+func EncodePageToken(lastID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.Itoa(lastID)))
+}
+
+// DecodePageToken returns the ID a page token resumes after. The empty
+// token decodes to 0, the start of the list.
+func DecodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPageToken, token)
+	}
+	rest, ok := strings.CutPrefix(string(raw), pageTokenPrefix)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPageToken, token)
+	}
+	id, err := strconv.Atoi(rest)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPageToken, token)
+	}
+	return id, nil
+}
+
+func ValidatePageSize(size int) error {
+	if size < 0 || size > MaxPageSize {
+		return fmt.Errorf("%w: %d (want 0-%d)", ErrInvalidPageSize, size, MaxPageSize)
+	}
+	return nil
+}
+
+// Paginate returns the page of todos selected by opts.PageSize and
+// opts.PageToken, together with the token for the following page. todos
+// must already be filtered and sorted by ID. It is meant for backends that
+// hold the full result set in memory.
+func Paginate(todos []Todo, opts ListOptions) ([]Todo, string, error) {
+	if err := ValidatePageSize(opts.PageSize); err != nil {
+		return nil, "", err
+	}
+	after, err := DecodePageToken(opts.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	start := 0
+	for start < len(todos) && todos[start].ID <= after {
+		start++
+	}
+	todos = todos[start:]
+	if opts.PageSize == 0 || len(todos) <= opts.PageSize {
+		return todos, "", nil
+	}
+	page := todos[:opts.PageSize]
+	return page, EncodePageToken(page[len(page)-1].ID), nil
+}
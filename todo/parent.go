@@ -0,0 +1,52 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateParentID checks an optional parent ID; 0 means no parent.
+func ValidateParentID(parentID int) error {
+	if parentID < 0 {
+		return fmt.Errorf("parent id %d: %w", parentID, ErrInvalidID)
+	}
+	return nil
+}
+
+// CheckParent reports whether the todo with ID id, or a new todo if id is
+// 0, may become a subtask of the todo with ID parentID while in the project
+// with ID projectID. The parent must be live and in that project, and must
+// not be the todo itself or one of its subtasks, which would make a cycle.
+// get returns a stored todo, live or trashed, or ErrNotFound; backends call
+// CheckParent where the todos cannot change under it.
+func CheckParent(id, parentID, projectID int, get func(id int) (Todo, error)) error {
+	if parentID == 0 {
+		return nil
+	}
+	parent, err := get(parentID)
+	if errors.Is(err, ErrNotFound) || err == nil && parent.Trashed() {
+		return fmt.Errorf("%w: todo %d", ErrParentNotFound, parentID)
+	}
+	if err != nil {
+		return err
+	}
+	if parent.ProjectID != projectID {
+		return fmt.Errorf("%w: todo %d is in another project", ErrParentNotFound, parentID)
+	}
+	// Live todos only have live ancestors, so the walk up ends at a
+	// top-level todo.
+	for ancestor := parent; ; {
+		if ancestor.ID == id && parentID == id {
+			return fmt.Errorf("todo %d: %w: a todo cannot be its own parent", id, ErrParentCycle)
+		}
+		if ancestor.ID == id {
+			return fmt.Errorf("todo %d: %w: todo %d is one of its subtasks", id, ErrParentCycle, parentID)
+		}
+		if ancestor.ParentID == 0 {
+			return nil
+		}
+		if ancestor, err = get(ancestor.ParentID); err != nil {
+			return err
+		}
+	}
+}
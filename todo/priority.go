@@ -0,0 +1,48 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority ranks how pressing a todo is. The zero value means no priority,
+// and higher values sort first.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = [...]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+// ParsePriority converts a case-insensitive priority name into a Priority.
+// The empty string parses as PriorityNone.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return PriorityNone, nil
+	}
+	for p, name := range priorityNames {
+		if s == name {
+			return Priority(p), nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("%w: %q (want none, low, medium, high or urgent)", ErrInvalidPriority, s)
+}
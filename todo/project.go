@@ -0,0 +1,103 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// InboxID is the ID of the Inbox, the built-in project that holds
+	// every todo not added to another. It is not stored, and cannot be
+	// renamed, archived or deleted.
+	InboxID   = 0
+	InboxName = "Inbox"
+
+	MaxProjectNameLength = 50
+)
+
+// Project groups todos. Every todo belongs to exactly one project, the
+// Inbox unless it was added to or moved into another.
+type Project struct {
+	ID   int    `json:"id" bson:"_id"`
+	Name string `json:"name" bson:"name"`
+	// Archived projects keep their todos but take no new ones.
+	Archived bool `json:"archived,omitempty" bson:"archived,omitempty"`
+	// Owner is the user the project belongs to; see Todo.Owner. Project
+	// names are unique per owner.
+	Owner string `json:"owner,omitempty" bson:"owner,omitempty"`
+}
+
+// Inbox returns the built-in project.
+func Inbox() Project {
+	return Project{ID: InboxID, Name: InboxName}
+}
+
+// ValidateProjectName checks the name of a new or renamed project. Names
+// are unique regardless of case, which leaves the storage to check, but
+// none may be taken by the Inbox.
+func ValidateProjectName(name string) error {
+	switch {
+	case strings.TrimSpace(name) != name:
+		return fmt.Errorf("%w: %q has leading or trailing spaces", ErrInvalidProjectName, name)
+	case name == "":
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidProjectName)
+	case utf8.RuneCountInString(name) > MaxProjectNameLength:
+		return fmt.Errorf("%w: name is %d characters long (max %d)", ErrInvalidProjectName, utf8.RuneCountInString(name), MaxProjectNameLength)
+	case strings.EqualFold(name, InboxName):
+		return fmt.Errorf("%w: %q", ErrProjectNameTaken, name)
+	}
+	return nil
+}
+
+// ValidateProjectID checks the ID of a project to rename, archive or
+// delete, which rules out the Inbox.
+func ValidateProjectID(id int) error {
+	if id == InboxID {
+		return fmt.Errorf("project %d: %w: the %s cannot be changed", id, ErrInvalidID, InboxName)
+	}
+	if id < 0 {
+		return fmt.Errorf("project id %d: %w", id, ErrInvalidID)
+	}
+	return nil
+}
+
+// ValidateProjectRef checks the project ID of a todo, or of a listing; 0
+// means the Inbox.
+func ValidateProjectRef(id int) error {
+	if id < 0 {
+		return fmt.Errorf("project id %d: %w", id, ErrInvalidID)
+	}
+	return nil
+}
+
+// CheckProject reports whether todos may be added to, or moved into, the
+// project with the given ID: it must exist and not be archived. get returns
+// a stored project or ErrProjectNotFound.
+func CheckProject(id int, get func(id int) (Project, error)) error {
+	if id == InboxID {
+		return nil
+	}
+	p, err := get(id)
+	if err != nil {
+		return err
+	}
+	if p.Archived {
+		return fmt.Errorf("project %d: %w", id, ErrProjectArchived)
+	}
+	return nil
+}
+
+// ProjectNamed returns the project among projects, or the Inbox, whose
+// name matches name regardless of case.
+func ProjectNamed(projects []Project, name string) (Project, bool) {
+	if strings.EqualFold(name, InboxName) {
+		return Inbox(), true
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Project{}, false
+}
@@ -0,0 +1,426 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+// weekdayNames are the RFC 5545 two-letter weekday names, indexed by
+// time.Weekday.
+var weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RuleDay is an entry of a rule's BYDAY: a weekday and, in a monthly rule,
+// which of its occurrences in the month is meant, e.g. 2 for the second or
+// -1 for the last. N is 0 for every occurrence.
+type RuleDay struct {
+	N   int
+	Day time.Weekday
+}
+
+func (d RuleDay) String() string {
+	if d.N == 0 {
+		return weekdayNames[d.Day]
+	}
+	return strconv.Itoa(d.N) + weekdayNames[d.Day]
+}
+
+// Rule is a recurrence rule in the subset of RFC 5545 RRULE syntax that
+// todos support: FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, BYDAY,
+// BYMONTHDAY, COUNT and UNTIL, e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO".
+// Weeks start on Monday. Occurrences that fall on a day the month lacks,
+// such as the 31st of April, are skipped, as RFC 5545 has it.
+type Rule struct {
+	Freq Frequency
+	// Interval is how many days, weeks, months or years apart occurrences
+	// are; at least 1.
+	Interval int
+	// ByDay limits daily and weekly rules to the given weekdays, and picks
+	// the days of a monthly one. Only monthly rules may number them.
+	ByDay []RuleDay
+	// ByMonthDay picks the days of a monthly rule; negative days count
+	// from the end of the month, -1 being the last.
+	ByMonthDay []int
+	// Count is how many occurrences remain, this one included, or 0 for no
+	// limit.
+	Count int
+	// Until is the last date an occurrence may fall on (YYYY-MM-DD), or
+	// empty for no limit.
+	Until string
+}
+
+// maxRuleSteps bounds the search for the next occurrence, so a rule that
+// can never match again, such as the 31st of every twelfth month starting
+// in February, gives up rather than loops.
+const maxRuleSteps = 1000
+
+// ParseRule parses a rule in RRULE syntax, with or without the "RRULE:"
+// prefix and in any case. Parts may come in any order.
+func ParseRule(s string) (Rule, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	text = strings.TrimPrefix(text, "RRULE:")
+	if text == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRecurrence)
+	}
+	r := Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(text, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: %q is not NAME=VALUE", ErrInvalidRecurrence, part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%w: %s given twice", ErrInvalidRecurrence, name)
+		}
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			err = r.parseFreq(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			r.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			err = r.parseByDay(value)
+		case "BYMONTHDAY":
+			err = r.parseByMonthDay(value)
+		default:
+			err = fmt.Errorf("%w: unsupported part %s", ErrInvalidRecurrence, name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+	return r, r.validate()
+}
+
+func (r *Rule) parseFreq(value string) error {
+	for freq, name := range frequencyNames {
+		if name == value {
+			r.Freq = freq
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unsupported FREQ %s (want DAILY, WEEKLY, MONTHLY or YEARLY)", ErrInvalidRecurrence, value)
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive number, got %q", ErrInvalidRecurrence, name, value)
+	}
+	return n, nil
+}
+
+// parseUntil accepts a date as YYYYMMDD, or as a date-time whose time it
+// drops.
+func (r *Rule) parseUntil(value string) error {
+	date, _, _ := strings.Cut(value, "T")
+	until, err := time.Parse("20060102", date)
+	if err != nil {
+		return fmt.Errorf("%w: UNTIL must be a date (YYYYMMDD), got %q", ErrInvalidRecurrence, value)
+	}
+	r.Until = until.Format(DueDateLayout)
+	return nil
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRecurrence, item)
+		}
+		prefix, name := item[:len(item)-2], item[len(item)-2:]
+		day := slices.Index(weekdayNames, name)
+		if day < 0 {
+			return fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRecurrence, item)
+		}
+		var n int
+		if prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRecurrence, item)
+			}
+		}
+		r.ByDay = append(r.ByDay, RuleDay{N: n, Day: time.Weekday(day)})
+	}
+	return nil
+}
+
+func (r *Rule) parseByMonthDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("%w: invalid BYMONTHDAY %q", ErrInvalidRecurrence, item)
+		}
+		r.ByMonthDay = append(r.ByMonthDay, day)
+	}
+	return nil
+}
+
+// validate checks the parts of a parsed rule against each other.
+func (r Rule) validate() error {
+	switch {
+	case r.Freq == 0:
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRecurrence)
+	case r.Count > 0 && r.Until != "":
+		return fmt.Errorf("%w: COUNT and UNTIL cannot both be given", ErrInvalidRecurrence)
+	case len(r.ByMonthDay) > 0 && r.Freq != Monthly:
+		return fmt.Errorf("%w: BYMONTHDAY needs FREQ=MONTHLY", ErrInvalidRecurrence)
+	case len(r.ByDay) > 0 && r.Freq == Yearly:
+		return fmt.Errorf("%w: BYDAY is not supported with FREQ=YEARLY", ErrInvalidRecurrence)
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly {
+			return fmt.Errorf("%w: numbered BYDAY %s needs FREQ=MONTHLY", ErrInvalidRecurrence, d)
+		}
+	}
+	return nil
+}
+
+// String returns the rule in canonical RRULE syntax, without the "RRULE:"
+// prefix and leaving out defaults.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + frequencyNames[r.Freq]}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != "" {
+		until, _ := time.Parse(DueDateLayout, r.Until)
+		parts = append(parts, "UNTIL="+until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ValidateRecurrence checks an optional recurrence rule.
+func ValidateRecurrence(rule string) error {
+	if rule == "" {
+		return nil
+	}
+	_, err := ParseRule(rule)
+	return err
+}
+
+// NormalizeRecurrence returns a valid rule in canonical form, and anything
+// else as given.
+func NormalizeRecurrence(rule string) string {
+	r, err := ParseRule(rule)
+	if err != nil {
+		return rule
+	}
+	return r.String()
+}
+
+// Next returns the first occurrence of the rule after the date after, and
+// false if there is none: the rule's UNTIL has passed, or no day ever
+// matches. Rule parts left out take their value from after, e.g. a weekly
+// rule without BYDAY recurs on after's weekday. after's time of day is
+// ignored.
+func (r Rule) Next(after time.Time) (time.Time, bool) {
+	after = time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(after)
+	case Weekly:
+		next, ok = r.nextWeekly(after)
+	case Monthly:
+		next, ok = r.nextMonthly(after)
+	case Yearly:
+		next, ok = r.nextYearly(after)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	if r.Until != "" && next.Format(DueDateLayout) > r.Until {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// onDay reports whether the rule's BYDAY allows date, as it does any date
+// when it is empty.
+func (r Rule) onDay(date time.Time) bool {
+	return len(r.ByDay) == 0 || slices.ContainsFunc(r.ByDay, func(d RuleDay) bool { return d.Day == date.Weekday() })
+}
+
+func (r Rule) nextDaily(after time.Time) (time.Time, bool) {
+	// The weekday repeats within seven steps, whatever the interval.
+	for step := 1; step <= 7; step++ {
+		if next := after.AddDate(0, 0, step*r.Interval); r.onDay(next) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextWeekly(after time.Time) (time.Time, bool) {
+	on := r.onDay
+	if len(r.ByDay) == 0 {
+		on = func(date time.Time) bool { return date.Weekday() == after.Weekday() }
+	}
+	// Later days of after's week, Monday to Sunday, come first, then the
+	// week interval weeks on.
+	monday := after.AddDate(0, 0, -(int(after.Weekday())+6)%7)
+	for next := after.AddDate(0, 0, 1); next.Before(monday.AddDate(0, 0, 7)); next = next.AddDate(0, 0, 1) {
+		if on(next) {
+			return next, true
+		}
+	}
+	monday = monday.AddDate(0, 0, 7*r.Interval)
+	for next := monday; ; next = next.AddDate(0, 0, 1) {
+		if on(next) {
+			return next, true
+		}
+	}
+}
+
+func (r Rule) nextMonthly(after time.Time) (time.Time, bool) {
+	year, month := after.Year(), after.Month()
+	for step := 0; step < maxRuleSteps; step++ {
+		for _, day := range r.monthDays(year, month, after.Day()) {
+			next := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			if next.After(after) {
+				return next, true
+			}
+		}
+		month += time.Month(r.Interval)
+		year, month = year+int(month-1)/12, (month-1)%12+1
+	}
+	return time.Time{}, false
+}
+
+// monthDays returns, in order, the days of the given month on which a
+// monthly rule falls. anchor is the day of month used when the rule names
+// none.
+func (r Rule) monthDays(year int, month time.Month, anchor int) []int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []int
+	add := func(day int) {
+		if day >= 1 && day <= last && !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	for _, day := range r.ByMonthDay {
+		if day < 0 {
+			day += last + 1
+		}
+		add(day)
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	for _, d := range r.ByDay {
+		firstDay := 1 + (int(d.Day)-int(first)+7)%7
+		switch {
+		case d.N > 0:
+			add(firstDay + 7*(d.N-1))
+		case d.N < 0:
+			lastDay := firstDay + 7*((last-firstDay)/7)
+			add(lastDay + 7*(d.N+1))
+		default:
+			for day := firstDay; day <= last; day += 7 {
+				add(day)
+			}
+		}
+	}
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		add(anchor)
+	}
+	slices.Sort(days)
+	return days
+}
+
+func (r Rule) nextYearly(after time.Time) (time.Time, bool) {
+	// Only the 29th of February can be missing from a year.
+	for step := 1; step <= maxRuleSteps; step++ {
+		year := after.Year() + step*r.Interval
+		next := time.Date(year, after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+		if next.Day() == after.Day() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Recur returns the draft of the next occurrence of t, a completed
+// recurring todo that has not recurred yet, and false for any other todo or
+// once its rule has run out. The next occurrence copies t's title,
+// description, due time, priority, tags, parent and project, and its rule
+// with one fewer COUNT left. It is due on the first date of the rule after
+// t's due date that is also after the day t was completed, so a chore done
+// late skips the dates it missed. Without a due date, the rule counts from
+// the day t was completed.
+func (t Todo) Recur() (Draft, bool) {
+	if !t.Completed || t.Recurrence == "" || t.NextID != 0 {
+		return Draft{}, false
+	}
+	rule, err := ParseRule(t.Recurrence)
+	if err != nil || rule.Count == 1 {
+		return Draft{}, false
+	}
+	done := t.CompletedAt.UTC()
+	from := done
+	if due, err := time.Parse(DueDateLayout, t.DueDate); err == nil {
+		from = due
+	}
+	next, ok := rule.Next(from)
+	for ok && next.Format(DueDateLayout) <= done.Format(DueDateLayout) {
+		next, ok = rule.Next(next)
+	}
+	if !ok {
+		return Draft{}, false
+	}
+	if rule.Count > 0 {
+		rule.Count--
+	}
+	return Draft{
+		Title:       t.Title,
+		Description: t.Description,
+		DueDate:     next.Format(DueDateLayout),
+		DueTime:     t.DueTime,
+		Priority:    t.Priority,
+		Tags:        slices.Clone(t.Tags),
+		ParentID:    t.ParentID,
+		ProjectID:   t.ProjectID,
+		Recurrence:  rule.String(),
+	}, true
+}
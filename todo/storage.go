@@ -2,12 +2,117 @@ package todo
 
 import "context"
 
+// ListOptions narrows the todos returned by Storage.List. The zero value
+// lists every todo.
+type ListOptions struct {
+	// Tags restricts the result to todos carrying every listed tag.
+	Tags []string
+	// Filter is an expression in the query package's filter language,
+	// e.g. `completed=false AND title~"deploy"`. Backends parse it with
+	// query.Parse and reject malformed filters with ErrInvalidFilter.
+	Filter string
+	// PageSize limits the number of todos returned; 0 returns every match.
+	PageSize int
+	// PageToken resumes a listing from the token returned with the
+	// previous page.
+	PageToken string
+	// Trashed lists the todos in the trash instead of the live ones.
+	Trashed bool
+	// ProjectID restricts the result to the todos of one project; the zero
+	// value lists the Inbox.
+	ProjectID int
+}
+
+// Storage persists todos. Every change to a todo increments its Version,
+// and a context made by WithExpectedVersion makes the change conditional on
+// the version the caller last saw. Todos in the trash are invisible to
+// every method but Restore and PurgeTrash, and to List unless it asks for
+// them: the others report ErrNotFound.
+//
+// A todo may be a subtask of another (see Todo.ParentID). The parent of a
+// live todo is always live: adding or moving a todo under a missing or
+// trashed parent fails with ErrParentNotFound, under itself or one of its
+// own subtasks with ErrParentCycle.
+//
+// A todo may also be blocked by others (see Todo.BlockedBy). Completing it,
+// by SetCompleted or Update, fails with ErrBlocked while any of them is
+// open: live and incomplete. Purging a todo drops it from the BlockedBy of
+// the others.
+//
+// A todo may recur (see Todo.Recurrence). When SetCompleted or Update leaves
+// a recurring todo completed, the same change adds its next occurrence, as
+// Todo.Recur describes, records the new todo's ID in NextID, and returns the
+// todo with Recurred set.
+//
+// Every todo belongs to a project (see Todo.ProjectID), by default the
+// Inbox. Todos can only be added to or moved into a stored project that is
+// not archived; a missing one is reported as ErrProjectNotFound, an
+// archived one as ErrProjectArchived. A todo moves between projects alone:
+// moving one with live subtasks fails with ErrHasSubtasks.
+//
+// Todos and projects belong to the owner of the context they are added
+// with (see WithOwner), and every method only sees those of the owner of
+// its context: another owner's todo is reported as ErrNotFound, and as
+// ErrParentNotFound, ErrBlockerNotFound or ErrProjectNotFound where it is
+// named as a parent, blocker or project. PurgeTrash only empties the
+// owner's own trash.
 type Storage interface {
-	Add(ctx context.Context, title, description string) error
-	List(ctx context.Context) ([]Todo, error)
+	// Add stores a new todo and returns it as stored, with its allocated ID.
+	Add(ctx context.Context, draft Draft) (Todo, error)
+	// List returns the matching todos ordered by ID and, when more remain,
+	// the token for the next page.
+	List(ctx context.Context, opts ListOptions) (todos []Todo, nextPageToken string, err error)
+	// Delete moves a todo to the trash, stamping its DeletedAt. It returns
+	// ErrHasSubtasks if the todo has live subtasks.
 	Delete(ctx context.Context, id int) error
-	SetCompleted(ctx context.Context, id int, completed bool) error
-	EditTitle(ctx context.Context, id int, title string) error
-	EditDescription(ctx context.Context, id int, description string) error
+	// DeleteTree moves a todo and all its live subtasks, at any depth, to
+	// the trash at once and returns how many todos it moved. The expected
+	// version applies to the todo named.
+	DeleteTree(ctx context.Context, id int) (int, error)
+	// Restore moves a todo out of the trash, together with the subtasks
+	// that went to the trash with it, and returns the todo. It returns
+	// ErrNotInTrash if the todo is live and ErrParentNotFound if its parent
+	// is in the trash.
+	Restore(ctx context.Context, id int) (Todo, error)
+	// PurgeTrash permanently removes every todo in the trash and returns
+	// how many there were.
+	PurgeTrash(ctx context.Context) (int, error)
+	// SetCompleted and the edit methods below return the todo as stored
+	// after the change.
+	SetCompleted(ctx context.Context, id int, completed bool) (Todo, error)
+	EditTitle(ctx context.Context, id int, title string) (Todo, error)
+	EditDescription(ctx context.Context, id int, description string) (Todo, error)
+	EditDue(ctx context.Context, id int, dueDate, dueTime string) (Todo, error)
+	EditPriority(ctx context.Context, id int, priority Priority) (Todo, error)
+	AddTag(ctx context.Context, id int, tag string) (Todo, error)
+	RemoveTag(ctx context.Context, id int, tag string) (Todo, error)
+	// AddDependency makes the todo with ID id blocked by the live todo with
+	// ID blockerID. It returns ErrBlockerNotFound if there is no such todo,
+	// and ErrDependencyCycle if the blocker is the todo itself or already
+	// waits on it.
+	AddDependency(ctx context.Context, id, blockerID int) (Todo, error)
+	RemoveDependency(ctx context.Context, id, blockerID int) (Todo, error)
+	// Update replaces the fields of the todo with ID patch.ID that mask
+	// names (see the Field constants) with those of patch, all or none of
+	// them. It returns ErrTodoUnchanged if every named field already has
+	// its new value.
+	Update(ctx context.Context, patch Todo, mask []string) (Todo, error)
+
+	// CreateProject stores a new project and returns it with its allocated
+	// ID. Project names are unique per owner regardless of case; a taken one is
+	// reported as ErrProjectNameTaken.
+	CreateProject(ctx context.Context, name string) (Project, error)
+	// ListProjects returns every stored project, archived ones included,
+	// ordered by ID. The Inbox is not among them.
+	ListProjects(ctx context.Context) ([]Project, error)
+	// RenameProject and ArchiveProject return the project as stored after
+	// the change, or ErrProjectUnchanged if it already has the given name
+	// or state.
+	RenameProject(ctx context.Context, id int, name string) (Project, error)
+	ArchiveProject(ctx context.Context, id int, archived bool) (Project, error)
+	// DeleteProject removes a project. It returns ErrProjectNotEmpty while
+	// any todo, live or in the trash, belongs to it.
+	DeleteProject(ctx context.Context, id int) error
+
 	Close(ctx context.Context) error
 }
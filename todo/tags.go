@@ -0,0 +1,83 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MaxTagLength = 30
+	MaxTags      = 20
+)
+
+// NormalizeTag returns the canonical form of a tag: trimmed and lower-cased.
+// Storage backends normalize tags before validating or persisting them, so
+// "Work" and " work " name the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes every tag and drops duplicates, keeping the
+// first occurrence of each.
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// ValidateTag checks a normalized tag.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("%w: tag cannot be empty", ErrInvalidTag)
+	}
+	if strings.ContainsFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+		return fmt.Errorf("%w: %q contains whitespace or commas", ErrInvalidTag, tag)
+	}
+	if n := utf8.RuneCountInString(tag); n > MaxTagLength {
+		return fmt.Errorf("%w: %q is %d characters (max %d)", ErrInvalidTag, tag, n, MaxTagLength)
+	}
+	return nil
+}
+
+// ValidateTags checks a normalized, de-duplicated tag set.
+func ValidateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("%w: %d tags (max %d)", ErrTooManyTags, len(tags), MaxTags)
+	}
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasTags reports whether the todo carries every one of the given tags.
+func (t Todo) HasTags(tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, have := range t.Tags {
+			if have == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
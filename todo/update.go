@@ -0,0 +1,123 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The fields Storage.Update can replace. The names are those of the Todo
+// protobuf message, so the paths of a google.protobuf.FieldMask name them
+// directly.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldCompleted   = "completed"
+	FieldDueDate     = "due_date"
+	FieldDueTime     = "due_time"
+	FieldPriority    = "priority"
+	FieldTags        = "tags"
+	FieldParentID    = "parent_id"
+	FieldProjectID   = "project_id"
+	FieldRecurrence  = "recurrence"
+)
+
+var updatableFields = []string{
+	FieldTitle, FieldDescription, FieldCompleted, FieldDueDate, FieldDueTime, FieldPriority, FieldTags, FieldParentID,
+	FieldProjectID, FieldRecurrence,
+}
+
+// ValidateUpdate checks the arguments of Storage.Update and returns patch
+// with its tags and recurrence rule normalized. mask must name at least one
+// updatable field, each at most once, and must name due_date and due_time
+// together so the pair stays valid. Only the named fields of patch are
+// validated; whether a new parent or project exists is left to the storage,
+// with CheckParent and CheckProject.
+func ValidateUpdate(patch Todo, mask []string) (Todo, error) {
+	if err := ValidateID(patch.ID); err != nil {
+		return Todo{}, err
+	}
+	if len(mask) == 0 {
+		return Todo{}, fmt.Errorf("%w: no fields given", ErrInvalidUpdateMask)
+	}
+	for i, field := range mask {
+		if !slices.Contains(updatableFields, field) {
+			return Todo{}, fmt.Errorf("%w: %q is not an updatable field", ErrInvalidUpdateMask, field)
+		}
+		if slices.Contains(mask[:i], field) {
+			return Todo{}, fmt.Errorf("%w: %q given twice", ErrInvalidUpdateMask, field)
+		}
+	}
+	if slices.Contains(mask, FieldDueDate) != slices.Contains(mask, FieldDueTime) {
+		return Todo{}, fmt.Errorf("%w: %q and %q must be updated together", ErrInvalidUpdateMask, FieldDueDate, FieldDueTime)
+	}
+
+	patch.Tags = NormalizeTags(patch.Tags)
+	patch.Recurrence = NormalizeRecurrence(patch.Recurrence)
+	for _, field := range mask {
+		var err error
+		switch field {
+		case FieldTitle:
+			err = ValidateTitle(patch.Title)
+		case FieldDescription:
+			err = ValidateDescription(patch.Description)
+		case FieldDueDate:
+			err = ValidateDue(patch.DueDate, patch.DueTime)
+		case FieldPriority:
+			err = ValidatePriority(patch.Priority)
+		case FieldTags:
+			err = ValidateTags(patch.Tags)
+		case FieldParentID:
+			err = ValidateParentID(patch.ParentID)
+		case FieldProjectID:
+			err = ValidateProjectRef(patch.ProjectID)
+		case FieldRecurrence:
+			err = ValidateRecurrence(patch.Recurrence)
+		}
+		if err != nil {
+			return Todo{}, err
+		}
+	}
+	return patch, nil
+}
+
+// Apply returns t with the fields named by mask replaced by those of patch,
+// and whether that changed any of them. The mask must have passed
+// ValidateUpdate.
+func (t Todo) Apply(patch Todo, mask []string) (Todo, bool) {
+	updated := t
+	for _, field := range mask {
+		switch field {
+		case FieldTitle:
+			updated.Title = patch.Title
+		case FieldDescription:
+			updated.Description = patch.Description
+		case FieldCompleted:
+			updated.Completed = patch.Completed
+		case FieldDueDate:
+			updated.DueDate = patch.DueDate
+		case FieldDueTime:
+			updated.DueTime = patch.DueTime
+		case FieldPriority:
+			updated.Priority = patch.Priority
+		case FieldTags:
+			updated.Tags = slices.Clone(patch.Tags)
+		case FieldParentID:
+			updated.ParentID = patch.ParentID
+		case FieldProjectID:
+			updated.ProjectID = patch.ProjectID
+		case FieldRecurrence:
+			updated.Recurrence = patch.Recurrence
+		}
+	}
+	changed := updated.Title != t.Title ||
+		updated.Description != t.Description ||
+		updated.Completed != t.Completed ||
+		updated.DueDate != t.DueDate ||
+		updated.DueTime != t.DueTime ||
+		updated.Priority != t.Priority ||
+		!slices.Equal(updated.Tags, t.Tags) ||
+		updated.ParentID != t.ParentID ||
+		updated.ProjectID != t.ProjectID ||
+		updated.Recurrence != t.Recurrence
+	return updated, changed
+}
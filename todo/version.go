@@ -0,0 +1,32 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+)
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a copy of ctx that makes Storage changes
+// conditional: Delete, SetCompleted, Update and the edit methods fail with
+// ErrVersionConflict unless the todo is still at the given version. A
+// version of 0 sets no condition.
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the version set by WithExpectedVersion, or 0 if
+// ctx sets none.
+func ExpectedVersion(ctx context.Context) int {
+	version, _ := ctx.Value(expectedVersionKey{}).(int)
+	return version
+}
+
+// CheckVersion reports ErrVersionConflict if ctx expects a version of the
+// todo other than its current one.
+func CheckVersion(ctx context.Context, current Todo) error {
+	if want := ExpectedVersion(ctx); want != 0 && want != current.Version {
+		return fmt.Errorf("todo %d: %w (now at version %d, expected %d)", current.ID, ErrVersionConflict, current.Version, want)
+	}
+	return nil
+}